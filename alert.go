@@ -0,0 +1,234 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package errors
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"go.yaml.in/yaml/v3"
+)
+
+// AlertRuleOptions 是 GenerateAlertRules 的选项
+type AlertRuleOptions struct {
+	// GroupName 是规则组的名称，为空时使用 framework-errors
+	GroupName string
+	// Metric 是错误计数指标，为空时使用 prometheuserr 的 framework_errors_total
+	Metric string
+	// Window 是计算错误速率的时间窗口，<= 0 时使用 5m
+	Window time.Duration
+	// For 是告警触发前条件需要持续的时间，<= 0 时使用 5m
+	For time.Duration
+	// Thresholds 是各严重程度的错误码每秒错误数阈值，没有设置阈值的严重程度不生成告警
+	// 为 nil 时只为影响稳定性的错误码生成告警，阈值为 0.1（每分钟 6 次）.
+	Thresholds map[Severity]float64
+	// CategoryThresholds 是分类（见 RegisterCategory）的每秒错误数阈值，分类下所有错误码合并计算
+	CategoryThresholds map[string]float64
+	// DefaultTeam 是没有设置 CodeDefinition.Owner 的错误码使用的 team 标签
+	DefaultTeam string
+	// Names 是错误码的符号名称，与 ProtoEnumOptions.Names 含义相同
+	Names map[int32]string
+	// Codes 是需要生成告警的错误码，为空时使用所有已注册的错误码（CodeSuccess 除外）
+	Codes []int32
+}
+
+// alertRuleFile 是 Prometheus 规则文件
+type alertRuleFile struct {
+	Groups []alertRuleGroup `yaml:"groups"`
+}
+
+type alertRuleGroup struct {
+	Name  string      `yaml:"name"`
+	Rules []alertRule `yaml:"rules"`
+}
+
+type alertRule struct {
+	Alert       string            `yaml:"alert"`
+	Expr        string            `yaml:"expr"`
+	For         string            `yaml:"for"`
+	Labels      map[string]string `yaml:"labels"`
+	Annotations map[string]string `yaml:"annotations"`
+}
+
+// GenerateAlertRules 根据已注册的错误码生成 Prometheus 告警规则文件（YAML）
+// 每个错误码按严重程度的阈值生成一条规则，每个设置了阈值的分类生成一条合并规则；
+// 规则带有 severity 与 team（CodeDefinition.Owner）标签，Alertmanager 可以据此路由到负责的团队，
+// 告警覆盖范围随错误码目录自动更新. 错误计数来自 prometheuserr.
+//
+//	err := errors.GenerateAlertRules(f, errors.AlertRuleOptions{
+//		CategoryThresholds: map[string]float64{errors.CategoryDependency: 1},
+//		DefaultTeam:        "platform",
+//	})
+func GenerateAlertRules(w io.Writer, opts AlertRuleOptions) error {
+	if opts.GroupName == "" {
+		opts.GroupName = "framework-errors"
+	}
+	if opts.Metric == "" {
+		opts.Metric = "framework_errors_total"
+	}
+	if opts.Window <= 0 {
+		opts.Window = 5 * time.Minute
+	}
+	if opts.For <= 0 {
+		opts.For = 5 * time.Minute
+	}
+	if opts.Thresholds == nil {
+		opts.Thresholds = map[Severity]float64{SeverityCritical: 0.1}
+	}
+
+	codes := opts.Codes
+	if len(codes) == 0 {
+		for code := range CodeDefinitions {
+			if code != CodeSuccess {
+				codes = append(codes, code)
+			}
+		}
+	}
+	codes = append([]int32(nil), codes...)
+	sort.Slice(codes, func(i, j int) bool { return codes[i] < codes[j] })
+
+	group := alertRuleGroup{Name: opts.GroupName}
+	for _, code := range codes {
+		def := GetCodeDefinition(code)
+		severity := SeverityWarning
+		if def.IsAffectStability {
+			severity = SeverityCritical
+		}
+		threshold, ok := opts.Thresholds[severity]
+		if !ok {
+			continue
+		}
+		name := codeName(code, opts.Names)
+		rule := alertRule{
+			Alert: "FrameworkError" + alertName(name),
+			Expr:  alertExpr(opts, `code="`+strconv.Itoa(int(code))+`"`, threshold),
+			For:   promDuration(opts.For),
+			Labels: map[string]string{
+				"severity": string(severity),
+				"team":     alertTeam(def.Owner, opts.DefaultTeam),
+				"code":     strconv.Itoa(int(code)),
+				"reason":   name,
+			},
+			Annotations: map[string]string{
+				"summary":     fmt.Sprintf("错误码 %d（%s）每秒超过 %s 次", code, name, formatThreshold(threshold)),
+				"description": def.Message,
+			},
+		}
+		if def.DocURL != "" {
+			rule.Annotations["runbook_url"] = def.DocURL
+		}
+		group.Rules = append(group.Rules, rule)
+	}
+
+	categories := make([]string, 0, len(opts.CategoryThresholds))
+	for category := range opts.CategoryThresholds {
+		categories = append(categories, category)
+	}
+	sort.Strings(categories)
+	for _, category := range categories {
+		codes := categoryCodes(category)
+		if len(codes) == 0 {
+			continue
+		}
+		threshold := opts.CategoryThresholds[category]
+		severity := SeverityWarning
+		owners := map[string]bool{}
+		values := make([]string, len(codes))
+		for i, code := range codes {
+			def := GetCodeDefinition(code)
+			if def.IsAffectStability {
+				severity = SeverityCritical
+			}
+			owners[def.Owner] = true
+			values[i] = strconv.Itoa(int(code))
+		}
+		// 分类下的错误码属于同一个团队时路由到该团队
+		team := opts.DefaultTeam
+		if len(owners) == 1 {
+			team = alertTeam(GetCodeDefinition(codes[0]).Owner, opts.DefaultTeam)
+		}
+		group.Rules = append(group.Rules, alertRule{
+			Alert: "FrameworkErrorCategory" + alertName(category),
+			Expr:  alertExpr(opts, `code=~"`+strings.Join(values, "|")+`"`, threshold),
+			For:   promDuration(opts.For),
+			Labels: map[string]string{
+				"severity": string(severity),
+				"team":     team,
+				"category": category,
+			},
+			Annotations: map[string]string{
+				"summary": fmt.Sprintf("%s 类错误每秒超过 %s 次", category, formatThreshold(threshold)),
+			},
+		})
+	}
+
+	enc := yaml.NewEncoder(w)
+	enc.SetIndent(2)
+	if err := enc.Encode(alertRuleFile{Groups: []alertRuleGroup{group}}); err != nil {
+		return err
+	}
+	return enc.Close()
+}
+
+// alertExpr 返回按 selector 统计错误速率并与阈值比较的 PromQL 表达式
+func alertExpr(opts AlertRuleOptions, selector string, threshold float64) string {
+	return fmt.Sprintf("sum(rate(%s{%s}[%s])) > %s", opts.Metric, selector, promDuration(opts.Window), formatThreshold(threshold))
+}
+
+// alertTeam 返回告警的 team 标签
+func alertTeam(owner, defaultTeam string) string {
+	if owner != "" {
+		return owner
+	}
+	return defaultTeam
+}
+
+// alertName 将 UPPER_SNAKE 或小写的名称转换为告警名称使用的驼峰格式，例如 INTERNAL_ERROR 转换为 InternalError
+func alertName(name string) string {
+	var b strings.Builder
+	for _, part := range strings.FieldsFunc(name, func(r rune) bool { return r == '_' || r == '-' || r == ' ' }) {
+		r, size := utf8.DecodeRuneInString(part)
+		b.WriteRune(unicode.ToUpper(r))
+		b.WriteString(strings.ToLower(part[size:]))
+	}
+	return b.String()
+}
+
+// formatThreshold 格式化阈值，不输出多余的 0
+func formatThreshold(v float64) string {
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+// promDuration 将时间格式化为 Prometheus 的时间格式，例如 5m
+func promDuration(d time.Duration) string {
+	switch {
+	case d%time.Hour == 0:
+		return strconv.FormatInt(int64(d/time.Hour), 10) + "h"
+	case d%time.Minute == 0:
+		return strconv.FormatInt(int64(d/time.Minute), 10) + "m"
+	case d%time.Second == 0:
+		return strconv.FormatInt(int64(d/time.Second), 10) + "s"
+	default:
+		return strconv.FormatInt(d.Milliseconds(), 10) + "ms"
+	}
+}
@@ -0,0 +1,99 @@
+package errors_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-anyway/framework-errors"
+	"go.yaml.in/yaml/v3"
+)
+
+// alertRules 是生成的规则文件中测试关心的字段
+type alertRules struct {
+	Groups []struct {
+		Name  string `yaml:"name"`
+		Rules []struct {
+			Alert       string            `yaml:"alert"`
+			Expr        string            `yaml:"expr"`
+			For         string            `yaml:"for"`
+			Labels      map[string]string `yaml:"labels"`
+			Annotations map[string]string `yaml:"annotations"`
+		} `yaml:"rules"`
+	} `yaml:"groups"`
+}
+
+func TestGenerateAlertRules(t *testing.T) {
+	var buf bytes.Buffer
+	err := errors.GenerateAlertRules(&buf, errors.AlertRuleOptions{
+		Window:             time.Minute,
+		Thresholds:         map[errors.Severity]float64{errors.SeverityCritical: 0.5, errors.SeverityWarning: 10},
+		CategoryThresholds: map[string]float64{errors.CategoryDependency: 1},
+		DefaultTeam:        "platform",
+		Codes:              []int32{errors.CodeNotFound, errors.CodeInternalError},
+	})
+	if err != nil {
+		t.Fatalf("GenerateAlertRules() error = %v", err)
+	}
+
+	var file alertRules
+	if err := yaml.Unmarshal(buf.Bytes(), &file); err != nil {
+		t.Fatalf("生成的规则不是合法的 YAML: %v\n%s", err, buf.String())
+	}
+	if len(file.Groups) != 1 || len(file.Groups[0].Rules) != 3 {
+		t.Fatalf("rules =\n%s", buf.String())
+	}
+
+	rules := file.Groups[0].Rules
+	tests := []struct {
+		name     string
+		alert    string
+		expr     string
+		severity string
+	}{
+		{name: "不影响稳定性", alert: "FrameworkErrorNotFound", expr: `sum(rate(framework_errors_total{code="1004"}[1m])) > 10`, severity: "warning"},
+		{name: "影响稳定性", alert: "FrameworkErrorInternalError", expr: `sum(rate(framework_errors_total{code="1006"}[1m])) > 0.5`, severity: "critical"},
+		{name: "分类", alert: "FrameworkErrorCategoryDependency", expr: `[1m])) > 1`, severity: "critical"},
+	}
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := rules[i]
+			if r.Alert != tt.alert || !strings.HasSuffix(r.Expr, tt.expr) || r.Labels["severity"] != tt.severity {
+				t.Errorf("rule = %+v", r)
+			}
+			if r.For != "5m" || r.Labels["team"] != "platform" {
+				t.Errorf("for = %q, team = %q", r.For, r.Labels["team"])
+			}
+		})
+	}
+	if !strings.Contains(rules[2].Expr, `code=~"3001|3002|`) {
+		t.Errorf("category expr = %q", rules[2].Expr)
+	}
+}
+
+func TestGenerateAlertRulesOwner(t *testing.T) {
+	const code int32 = 47001
+	errors.CodeDefinitions[code] = errors.CodeDefinition{
+		Message:           "库存服务异常",
+		Reason:            "INVENTORY_BROKEN",
+		IsAffectStability: true,
+		Owner:             "inventory",
+		DocURL:            "https://runbooks.example.com/inventory",
+	}
+	defer delete(errors.CodeDefinitions, code)
+
+	var buf bytes.Buffer
+	if err := errors.GenerateAlertRules(&buf, errors.AlertRuleOptions{Codes: []int32{code}}); err != nil {
+		t.Fatalf("GenerateAlertRules() error = %v", err)
+	}
+	var file alertRules
+	if err := yaml.Unmarshal(buf.Bytes(), &file); err != nil {
+		t.Fatal(err)
+	}
+	r := file.Groups[0].Rules[0]
+	if r.Alert != "FrameworkErrorInventoryBroken" || r.Labels["team"] != "inventory" ||
+		r.Annotations["runbook_url"] != "https://runbooks.example.com/inventory" || !strings.HasSuffix(r.Expr, "[5m])) > 0.1") {
+		t.Errorf("rule = %+v", r)
+	}
+}
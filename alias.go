@@ -0,0 +1,73 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package errors
+
+import "sync/atomic"
+
+// aliases 是旧错误码到新错误码的映射，写时复制
+var aliases atomic.Pointer[map[int32]int32]
+
+// RegisterAlias 注册错误码别名，用于错误码的重命名与重新编号
+// 构造函数同时接受新旧错误码，创建的错误使用新错误码，因此序列化后传递给调用方的也是新错误码；
+// FromGRPCStatus、DecodeHeaders 等解析还未升级的调用方发送的旧错误码时同样转换为新错误码.
+// 别名会被展开，newCode 本身是别名时指向其最终的错误码；会形成循环的别名被忽略.
+//
+//	errors.RegisterAlias(CodeOrderClosedV1, CodeOrderClosed)
+func RegisterAlias(oldCode, newCode int32) {
+	for {
+		current := aliases.Load()
+		next := make(map[int32]int32)
+		if current != nil {
+			for k, v := range *current {
+				next[k] = v
+			}
+		}
+		if target, ok := next[newCode]; ok {
+			newCode = target
+		}
+		if newCode == oldCode {
+			return
+		}
+		next[oldCode] = newCode
+		for k, v := range next {
+			if v == oldCode {
+				next[k] = newCode
+			}
+		}
+		if aliases.CompareAndSwap(current, &next) {
+			return
+		}
+	}
+}
+
+// CanonicalCode 返回错误码别名对应的新错误码，不是别名时原样返回
+// 组合错误码（见 ComposeCode）按原错误码转换并保留服务 ID.
+func CanonicalCode(code int32) int32 {
+	m := aliases.Load()
+	if m == nil {
+		return code
+	}
+	if target, ok := (*m)[code]; ok {
+		return target
+	}
+	if service, base := SplitCode(code); service != 0 {
+		if target, ok := (*m)[base]; ok {
+			return ComposeCode(service, target)
+		}
+	}
+	return code
+}
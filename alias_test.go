@@ -0,0 +1,65 @@
+package errors_test
+
+import (
+	"strconv"
+	"testing"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/go-anyway/framework-errors"
+)
+
+func TestRegisterAlias(t *testing.T) {
+	const (
+		codeOrderClosed   int32 = 20001
+		codeOrderClosedV1 int32 = 20101
+		codeOrderClosedV0 int32 = 20201
+	)
+	defer errors.ResetAliases()
+	errors.RegisterAlias(codeOrderClosedV1, codeOrderClosed)
+	errors.RegisterAlias(codeOrderClosedV0, codeOrderClosedV1)
+	// 会形成循环的别名被忽略
+	errors.RegisterAlias(codeOrderClosed, codeOrderClosedV0)
+
+	tests := []struct {
+		name string
+		err  func() errors.StatusError
+		want int32
+	}{
+		{name: "NewWithStatus", err: func() errors.StatusError {
+			return errors.NewWithStatus(codeOrderClosedV1, "")
+		}, want: codeOrderClosed},
+		{name: "别名链", err: func() errors.StatusError {
+			return errors.NewStatusError(codeOrderClosedV0, "", nil)
+		}, want: codeOrderClosed},
+		{name: "新错误码", err: func() errors.StatusError {
+			return errors.NewWithStatus(codeOrderClosed, "")
+		}, want: codeOrderClosed},
+		{name: "组合错误码", err: func() errors.StatusError {
+			return errors.NewWithStatus(errors.ComposeCode(5, codeOrderClosedV1), "")
+		}, want: errors.ComposeCode(5, codeOrderClosed)},
+		{name: "旧版本调用方的 gRPC status", err: func() errors.StatusError {
+			st, _ := status.New(codes.FailedPrecondition, "订单已关闭").WithDetails(&errdetails.ErrorInfo{
+				Reason: strconv.Itoa(int(codeOrderClosedV1)),
+				Domain: errors.ErrorInfoDomain,
+			})
+			return errors.FromGRPCStatus(st)
+		}, want: codeOrderClosed},
+		{name: "旧版本调用方的消息头", err: func() errors.StatusError {
+			return errors.DecodeHeaders(map[string]string{errors.HeaderErrorCode: strconv.Itoa(int(codeOrderClosedV0))})
+		}, want: codeOrderClosed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.err().Code(); got != tt.want {
+				t.Errorf("Code() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+
+	if got := errors.CanonicalCode(codeOrderClosed); got != codeOrderClosed {
+		t.Errorf("CanonicalCode(%d) = %d, 循环别名不应生效", codeOrderClosed, got)
+	}
+}
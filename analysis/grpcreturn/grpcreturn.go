@@ -0,0 +1,145 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+// Package grpcreturn 提供检查 gRPC 方法直接返回 StatusError 的静态分析器.
+//
+// StatusError 没有实现 GRPCStatus()，gRPC 方法直接返回时调用方只能收到 codes.Unknown，
+// 错误码与扩展信息全部丢失. 应使用 errors.ToGRPCError、errors.LogAndReturnError 转换，
+// 或在服务端安装 errors.UnaryServerErrorInterceptor（见 errors.GRPCServerOptions），
+// 已安装拦截器的服务无需运行此检查.
+//
+// gRPC 方法指嵌入了 Unimplemented*Server 的类型上最后一个返回值为 error 的导出方法.
+// 只能检查静态类型实现了 StatusError 的返回值，先赋值给 error 类型变量再返回的情况无法发现.
+package grpcreturn
+
+import (
+	"go/ast"
+	"go/types"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+)
+
+// errorsPath 是 framework-errors 的导入路径
+const errorsPath = "github.com/go-anyway/framework-errors"
+
+// Analyzer 检查 gRPC 方法未经转换直接返回 StatusError
+var Analyzer = &analysis.Analyzer{
+	Name:     "grpcreturn",
+	Doc:      "检查 gRPC 方法未经 ToGRPCError 转换直接返回 StatusError，调用方将收到 codes.Unknown",
+	URL:      "https://pkg.go.dev/github.com/go-anyway/framework-errors/analysis/grpcreturn",
+	Requires: []*analysis.Analyzer{inspect.Analyzer},
+	Run:      run,
+}
+
+func run(pass *analysis.Pass) (interface{}, error) {
+	statusError := lookupStatusError(pass.Pkg)
+	if statusError == nil {
+		// 没有依赖 framework-errors，不可能返回 StatusError
+		return nil, nil
+	}
+
+	ins := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+	ins.Preorder([]ast.Node{(*ast.FuncDecl)(nil)}, func(n ast.Node) {
+		fn := n.(*ast.FuncDecl)
+		if fn.Body == nil || !isGRPCMethod(pass, fn) {
+			return
+		}
+		ast.Inspect(fn.Body, func(n ast.Node) bool {
+			switch n := n.(type) {
+			case *ast.FuncLit:
+				return false
+			case *ast.ReturnStmt:
+				if len(n.Results) == 0 {
+					return true
+				}
+				last := n.Results[len(n.Results)-1]
+				if t := pass.TypesInfo.TypeOf(last); t != nil && isStatusError(t, statusError) {
+					pass.Reportf(last.Pos(), "gRPC 方法 %s 直接返回 StatusError，调用方将收到 codes.Unknown，"+
+						"应使用 errors.ToGRPCError 转换或安装 errors.UnaryServerErrorInterceptor", fn.Name.Name)
+				}
+			}
+			return true
+		})
+	})
+	return nil, nil
+}
+
+// lookupStatusError 在 pkg 的依赖中查找 StatusError 接口
+func lookupStatusError(pkg *types.Package) *types.Interface {
+	for _, imp := range pkg.Imports() {
+		if imp.Path() != errorsPath {
+			continue
+		}
+		if obj, ok := imp.Scope().Lookup("StatusError").(*types.TypeName); ok {
+			iface, _ := obj.Type().Underlying().(*types.Interface)
+			return iface
+		}
+	}
+	return nil
+}
+
+// isStatusError 判断 t 是否实现了 StatusError，error 接口本身不算
+func isStatusError(t types.Type, statusError *types.Interface) bool {
+	if types.Identical(t, types.Universe.Lookup("error").Type()) {
+		return false
+	}
+	return types.Implements(t, statusError) || types.Implements(types.NewPointer(t), statusError)
+}
+
+// isGRPCMethod 判断 fn 是否是 gRPC 服务实现的方法
+func isGRPCMethod(pass *analysis.Pass, fn *ast.FuncDecl) bool {
+	if fn.Recv == nil || !fn.Name.IsExported() {
+		return false
+	}
+	obj, ok := pass.TypesInfo.Defs[fn.Name].(*types.Func)
+	if !ok {
+		return false
+	}
+	sig := obj.Type().(*types.Signature)
+	results := sig.Results()
+	if results.Len() == 0 || !types.Identical(results.At(results.Len()-1).Type(), types.Universe.Lookup("error").Type()) {
+		return false
+	}
+
+	recv := sig.Recv().Type()
+	if ptr, ok := recv.(*types.Pointer); ok {
+		recv = ptr.Elem()
+	}
+	st, ok := recv.Underlying().(*types.Struct)
+	if !ok {
+		return false
+	}
+	for i := 0; i < st.NumFields(); i++ {
+		f := st.Field(i)
+		if !f.Embedded() {
+			continue
+		}
+		t := f.Type()
+		if ptr, ok := t.(*types.Pointer); ok {
+			t = ptr.Elem()
+		}
+		if named, ok := t.(*types.Named); ok {
+			name := named.Obj().Name()
+			if strings.HasPrefix(name, "Unimplemented") && strings.HasSuffix(name, "Server") {
+				return true
+			}
+		}
+	}
+	return false
+}
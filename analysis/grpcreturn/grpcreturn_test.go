@@ -0,0 +1,13 @@
+package grpcreturn_test
+
+import (
+	"testing"
+
+	"golang.org/x/tools/go/analysis/analysistest"
+
+	"github.com/go-anyway/framework-errors/analysis/grpcreturn"
+)
+
+func TestAnalyzer(t *testing.T) {
+	analysistest.Run(t, analysistest.TestData(), grpcreturn.Analyzer, "a")
+}
@@ -0,0 +1,40 @@
+package a
+
+import (
+	"context"
+
+	"github.com/go-anyway/framework-errors"
+)
+
+type UnimplementedGreeterServer struct{}
+
+type server struct {
+	UnimplementedGreeterServer
+}
+
+func (s *server) SayHello(ctx context.Context, name string) (string, error) {
+	if name == "" {
+		return "", errors.NewWithStatus(1001, "") // want `gRPC 方法 SayHello 直接返回 StatusError`
+	}
+	if name == "ptr" {
+		return "", errors.NewStatusErrorPtr(1001) // want `gRPC 方法 SayHello 直接返回 StatusError`
+	}
+	if name == "converted" {
+		return "", errors.ToGRPCError(errors.NewWithStatus(1004, ""))
+	}
+	var err error = errors.NewWithStatus(1006, "")
+	go func() error {
+		return errors.NewWithStatus(1006, "")
+	}()
+	return "hello " + name, err
+}
+
+func (s *server) helper() errors.StatusError {
+	return errors.NewWithStatus(1006, "")
+}
+
+type repo struct{}
+
+func (r *repo) Find(ctx context.Context) error {
+	return errors.NewWithStatus(1004, "")
+}
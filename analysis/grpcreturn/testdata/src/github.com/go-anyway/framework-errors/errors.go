@@ -0,0 +1,23 @@
+package errors
+
+type StatusError interface {
+	error
+	Code() int32
+}
+
+type statusError struct{ code int32 }
+
+func (e *statusError) Error() string { return "" }
+func (e *statusError) Code() int32   { return e.code }
+
+func NewWithStatus(code int32, message string) StatusError {
+	return &statusError{code: code}
+}
+
+func NewStatusErrorPtr(code int32) *statusError {
+	return &statusError{code: code}
+}
+
+func ToGRPCError(err StatusError) error {
+	return err
+}
@@ -0,0 +1,91 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package errors
+
+import (
+	"errors"
+	"fmt"
+	"runtime"
+)
+
+// Annotate 为已有错误添加上下文，保留原错误的错误码、稳定性与扩展信息
+// annotation 支持 Param 占位符，opts 中的 Extra 会追加到扩展信息中.
+// 新错误的 Msg() 为 "annotation: 原消息"，原错误的调用堆栈被保留，
+// Annotate 的调用位置可以通过 AnnotationSite 获取. err 不是 StatusError 时先经过 Translate.
+//
+//	if err := charge(ctx, order); err != nil {
+//		return errors.Annotate(err, "while charging order {id}", errors.Param("id", order.ID))
+//	}
+func Annotate(err error, annotation string, opts ...Option) StatusError {
+	if err == nil {
+		return nil
+	}
+
+	se := Translate(err)
+	site := captureStack(2) // 跳过当前函数和调用者
+
+	var ws *withStatus
+	var inner *statusError
+	c := &withStatus{site: site, cause: err}
+	switch {
+	case errors.As(se, &ws):
+		c.status = ws.status.clone()
+		c.stack = ws.stack
+		if site != nil && site.n > 0 {
+			c.sites = appendSite(ws.sites, site.pcs[0])
+		}
+	case errors.As(se, &inner):
+		c.status = inner.clone()
+		c.stack = site
+	default:
+		c.status = &statusError{
+			statusCode: se.Code(),
+			ext: Extension{
+				IsAffectStability: se.IsAffectStability(),
+				Retryable:         IsRetryable(se),
+				Extra:             se.Extra(),
+			},
+			details: detailsOf(se),
+		}
+		c.stack = site
+	}
+	if !errors.As(err, new(StatusError)) {
+		// err 被 Translate 包装过，使用包装后的错误作为 cause
+		c.cause = se
+	}
+
+	// 先在 annotation 上应用 opts，使 Param 只替换 annotation 中的占位符
+	c.status.message = annotation
+	for _, opt := range opts {
+		opt(c)
+	}
+	c.annotation = c.status.message
+	c.status.message = fmt.Sprintf("%s: %s", c.annotation, se.Msg())
+	c.status.template = fmt.Sprintf("%s: %s", annotation, messageTemplate(se))
+	return c
+}
+
+// AnnotationSite 返回 err 最近一次 Annotate 的调用位置，格式与调用堆栈相同
+// err 没有经过 Annotate 时返回空字符串.
+func AnnotationSite(err error) string {
+	var ws *withStatus
+	if !errors.As(err, &ws) || ws.site == nil || ws.site.n == 0 {
+		return ""
+	}
+	frame, _ := runtime.CallersFrames(ws.site.pcs[:1]).Next()
+	return fmt.Sprintf("%s\n\t%s:%d", frame.Function, frame.File, frame.Line)
+}
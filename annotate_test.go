@@ -0,0 +1,75 @@
+package errors_test
+
+import (
+	errstd "errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/go-anyway/framework-errors"
+)
+
+func TestAnnotate(t *testing.T) {
+	inner := errors.NewWithStatus(errors.CodeRateLimitExceeded, "支付通道限流", errors.Extra("channel", "alipay"))
+	err := errors.Annotate(inner, "while charging order {id}", errors.Param("id", "42"), errors.Extra("order_id", "42"))
+
+	if err.Code() != errors.CodeRateLimitExceeded {
+		t.Errorf("Code() = %d, want %d", err.Code(), errors.CodeRateLimitExceeded)
+	}
+	if err.IsAffectStability() != inner.IsAffectStability() || errors.IsRetryable(err) != errors.IsRetryable(inner) {
+		t.Error("Annotate 应保留稳定性与可重试标记")
+	}
+	if err.Msg() != "while charging order 42: 支付通道限流" {
+		t.Errorf("Msg() = %s", err.Msg())
+	}
+	if err.Error() != "while charging order 42: 支付通道限流" {
+		t.Errorf("Error() = %s", err.Error())
+	}
+	extra := err.Extra()
+	if extra["channel"] != "alipay" || extra["order_id"] != "42" {
+		t.Errorf("Extra() = %v", extra)
+	}
+	if extra["stack"] != inner.Extra()["stack"] {
+		t.Error("Annotate 应保留原错误的调用堆栈")
+	}
+	if _, ok := inner.Extra()["order_id"]; ok {
+		t.Error("Annotate 不应修改原错误")
+	}
+	if !errstd.Is(err, inner) {
+		t.Error("errors.Is(err, inner) = false")
+	}
+	if site := errors.AnnotationSite(err); !strings.Contains(site, "TestAnnotate") {
+		t.Errorf("AnnotationSite() = %s", site)
+	}
+}
+
+func TestAnnotateChain(t *testing.T) {
+	cause := fmt.Errorf("dial tcp: %w", errstd.New("connection refused"))
+	err := errors.Annotate(errors.WrapWithStatusOptions(cause, errors.CodeDependencyUnavailable, "库存服务不可用"), "while reserving stock")
+	err = errors.Annotate(err, "while placing order")
+
+	if err.Code() != errors.CodeDependencyUnavailable {
+		t.Errorf("Code() = %d, want %d", err.Code(), errors.CodeDependencyUnavailable)
+	}
+	want := "while placing order: while reserving stock: 库存服务不可用"
+	if err.Msg() != want {
+		t.Errorf("Msg() = %s, want %s", err.Msg(), want)
+	}
+	if want += ": dial tcp: connection refused"; err.Error() != want {
+		t.Errorf("Error() = %s, want %s", err.Error(), want)
+	}
+}
+
+func TestAnnotatePlainError(t *testing.T) {
+	cause := errstd.New("boom")
+	err := errors.Annotate(cause, "while loading config")
+	if err.Code() != errors.CodeInternalError {
+		t.Errorf("Code() = %d, want %d", err.Code(), errors.CodeInternalError)
+	}
+	if !errstd.Is(err, cause) {
+		t.Error("errors.Is(err, cause) = false")
+	}
+	if errors.Annotate(nil, "ctx") != nil {
+		t.Error("Annotate(nil) 应返回 nil")
+	}
+}
@@ -0,0 +1,110 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package errors
+
+import (
+	"context"
+	"time"
+
+	"google.golang.org/grpc"
+)
+
+// AuditEvent 是敏感错误产生的结构化审计事件，见 NewAuditReporter
+type AuditEvent struct {
+	// Actor 是发起请求的主体，由 AuditOptions.Actor 从 context 中解析
+	// 不使用错误记录的 UserID，后者由业务代码填写，不能作为可信的身份.
+	Actor string `json:"actor,omitempty"`
+	// Action 是被拒绝的操作，优先使用 Permission 中的操作，其次是 gRPC 方法名
+	Action string `json:"action,omitempty"`
+	// Resource 是被访问的资源，来自 Permission
+	Resource  string    `json:"resource,omitempty"`
+	Code      int32     `json:"code"`
+	Reason    string    `json:"reason,omitempty"`
+	RequestID string    `json:"request_id,omitempty"`
+	Time      time.Time `json:"time"`
+}
+
+// AuditOptions 是 NewAuditReporter 的选项
+type AuditOptions struct {
+	// Categories 是需要审计的错误码分类，与 Codes 都为空时使用 CategoryAuth（认证失败与权限错误）
+	Categories []string
+	// Codes 是需要审计的错误码
+	Codes []int32
+	// Actor 从请求的 context 中解析发起请求的主体，例如认证中间件写入的用户 ID，为 nil 时 AuditEvent.Actor 为空
+	Actor func(ctx context.Context) string
+}
+
+// NewAuditReporter 返回为指定分类的错误生成审计事件的 Reporter，安全团队无需在每个 handler 中埋点即可获得审计记录
+// 审计事件不允许丢失，因此返回的 Reporter 不经过有界的分发队列：即使通过 RegisterReporter 注册，
+// emit 也会在处理错误的 goroutine 中同步调用（见 RegisterSyncReporter），应尽快返回.
+//
+//	errors.RegisterReporter(errors.NewAuditReporter(func(ctx context.Context, ev errors.AuditEvent) {
+//		auditLogger.Info("access denied", zap.Any("event", ev))
+//	}, errors.AuditOptions{Actor: auth.UserIDFromContext}))
+func NewAuditReporter(emit func(ctx context.Context, ev AuditEvent), opts AuditOptions) Reporter {
+	if len(opts.Categories) == 0 && len(opts.Codes) == 0 {
+		opts.Categories = []string{CategoryAuth}
+	}
+	codes := make(map[int32]bool, len(opts.Codes))
+	for _, code := range opts.Codes {
+		codes[code] = true
+	}
+	categories := append([]string(nil), opts.Categories...)
+
+	return auditReporter{ReporterFunc(func(ctx context.Context, err StatusError) {
+		if !auditable(err, codes, categories) {
+			return
+		}
+		ev := AuditEvent{
+			Code:      err.Code(),
+			Reason:    Reason(err),
+			RequestID: RequestIDOf(err),
+			Time:      now(),
+		}
+		if opts.Actor != nil {
+			ev.Actor = opts.Actor(ctx)
+		}
+		if p, ok := Permission(err); ok {
+			ev.Action, ev.Resource = p.Action, p.Resource
+		}
+		if ev.Action == "" {
+			ev.Action, _ = grpc.Method(ctx)
+		}
+		emit(ctx, ev)
+	})}
+}
+
+// auditReporter 是 NewAuditReporter 返回的 Reporter，总是同步调用
+type auditReporter struct {
+	ReporterFunc
+}
+
+// reportSync 实现 syncReporter 接口
+func (auditReporter) reportSync() {}
+
+// auditable 判断错误是否属于需要审计的错误码或分类
+func auditable(err StatusError, codes map[int32]bool, categories []string) bool {
+	if codes[err.Code()] {
+		return true
+	}
+	for _, category := range categories {
+		if IsCategory(err, category) {
+			return true
+		}
+	}
+	return false
+}
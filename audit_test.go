@@ -0,0 +1,123 @@
+package errors_test
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+
+	"github.com/go-anyway/framework-errors"
+)
+
+type userKey struct{}
+
+// fakeTransportStream 使 grpc.Method 返回指定的方法名
+type fakeTransportStream struct{ method string }
+
+func (s fakeTransportStream) Method() string               { return s.method }
+func (s fakeTransportStream) SetHeader(metadata.MD) error  { return nil }
+func (s fakeTransportStream) SendHeader(metadata.MD) error { return nil }
+func (s fakeTransportStream) SetTrailer(metadata.MD) error { return nil }
+
+func TestNewAuditReporter(t *testing.T) {
+	at := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	defer errors.SetClock(func() time.Time { return at })()
+
+	ctx := context.WithValue(context.Background(), userKey{}, "u-42")
+	grpcCtx := grpc.NewContextWithServerTransportStream(ctx, fakeTransportStream{method: "/orders.v1.Orders/Delete"})
+	actor := func(ctx context.Context) string {
+		id, _ := ctx.Value(userKey{}).(string)
+		return id
+	}
+
+	tests := []struct {
+		name string
+		opts errors.AuditOptions
+		ctx  context.Context
+		err  errors.StatusError
+		want []errors.AuditEvent
+	}{
+		{
+			name: "权限错误",
+			opts: errors.AuditOptions{Actor: actor},
+			ctx:  ctx,
+			err:  errors.NewPermissionDenied("orders/1", "delete", nil, errors.RequestID("req-1")),
+			want: []errors.AuditEvent{{
+				Actor: "u-42", Action: "delete", Resource: "orders/1",
+				Code: errors.CodeForbidden, Reason: "FORBIDDEN", RequestID: "req-1", Time: at,
+			}},
+		},
+		{
+			name: "认证失败使用 gRPC 方法名作为操作",
+			opts: errors.AuditOptions{Actor: actor},
+			ctx:  grpcCtx,
+			err:  errors.NewWithStatus(errors.CodeTokenExpired, ""),
+			want: []errors.AuditEvent{{
+				Actor: "u-42", Action: "/orders.v1.Orders/Delete",
+				Code: errors.CodeTokenExpired, Reason: "TOKEN_EXPIRED", Time: at,
+			}},
+		},
+		{
+			name: "默认不审计其他错误",
+			ctx:  ctx,
+			err:  errors.NewWithStatus(errors.CodeNotFound, ""),
+		},
+		{
+			name: "指定错误码，不使用错误记录的用户作为主体",
+			opts: errors.AuditOptions{Codes: []int32{errors.CodeNotFound}},
+			ctx:  ctx,
+			err:  errors.NewWithStatus(errors.CodeNotFound, "", errors.UserID("u-7")),
+			want: []errors.AuditEvent{{Code: errors.CodeNotFound, Reason: "NOT_FOUND", Time: at}},
+		},
+		{
+			name: "指定错误码时不再默认审计认证错误",
+			opts: errors.AuditOptions{Codes: []int32{errors.CodeNotFound}},
+			ctx:  ctx,
+			err:  errors.NewWithStatus(errors.CodeUnauthorized, ""),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []errors.AuditEvent
+			r := errors.NewAuditReporter(func(_ context.Context, ev errors.AuditEvent) {
+				got = append(got, ev)
+			}, tt.opts)
+			r.Report(tt.ctx, tt.err)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("events = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAuditReporterIsSynchronous(t *testing.T) {
+	defer errors.ResetReporters()
+
+	// 阻塞普通 Reporter 使分发队列溢出，审计事件仍然不会丢失
+	release := make(chan struct{})
+	errors.RegisterReporter(errors.ReporterFunc(func(ctx context.Context, err errors.StatusError) {
+		<-release
+	}))
+	var events int
+	errors.RegisterReporter(errors.NewAuditReporter(func(_ context.Context, ev errors.AuditEvent) {
+		events++
+	}, errors.AuditOptions{}))
+
+	const n = 2000
+	for i := 0; i < n; i++ {
+		_ = errors.LogAndReturnError(context.Background(), errors.NewStatusError(errors.CodeForbidden, "", nil), errors.SkipLog())
+	}
+	if events != n {
+		t.Errorf("events = %d, want %d", events, n)
+	}
+
+	close(release)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := errors.FlushReports(ctx); err != nil {
+		t.Fatalf("FlushReports() error = %v", err)
+	}
+}
@@ -0,0 +1,114 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package errors
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// RetryAfterKey 是建议的重试等待时间（毫秒）在扩展信息中的 key
+const RetryAfterKey = "retry_after_ms"
+
+// maxRetryAfter 是建议的重试等待时间的上限，来自上游的更大的值被视为无效
+const maxRetryAfter = 24 * time.Hour
+
+// RetryAfter 用于设置建议调用方重试前等待的时间，例如限流时令牌桶的补充时间
+// WriteError 会将其转换为 Retry-After 响应头，ToGRPCStatus 会附加 errdetails.RetryInfo，
+// FromGRPCStatus 与 CheckResponse 会还原该时间，Retry 会优先使用该时间作为等待时间.
+func RetryAfter(d time.Duration) Option {
+	return func(ws *withStatus) {
+		if d <= 0 {
+			return
+		}
+		Extra(RetryAfterKey, strconv.FormatInt(d.Milliseconds(), 10))(ws)
+	}
+}
+
+// RetryAfter 返回建议的重试等待时间，没有时返回 0
+func (e *statusError) RetryAfter() time.Duration {
+	ms, err := strconv.ParseInt(e.ext.Extra[RetryAfterKey], 10, 64)
+	if err != nil || ms <= 0 || ms > maxRetryAfter.Milliseconds() {
+		return 0
+	}
+	return time.Duration(ms) * time.Millisecond
+}
+
+// RetryAfter 返回建议的重试等待时间，没有时返回 0
+func (w *withStatus) RetryAfter() time.Duration {
+	return w.status.RetryAfter()
+}
+
+// BackoffHint 从错误链中读取建议的重试等待时间，没有时返回 0
+// 错误链中任意一个错误实现了 RetryAfter() time.Duration 即以其结果为准.
+func BackoffHint(err error) time.Duration {
+	var r interface{ RetryAfter() time.Duration }
+	if errors.As(err, &r) {
+		return r.RetryAfter()
+	}
+	return 0
+}
+
+// retryInfoDetail 将建议的重试等待时间编码为 errdetails.RetryInfo，没有时返回 nil
+func retryInfoDetail(err StatusError) *errdetails.RetryInfo {
+	d := BackoffHint(err)
+	if d <= 0 {
+		return nil
+	}
+	return &errdetails.RetryInfo{RetryDelay: durationpb.New(d)}
+}
+
+// setRetryAfterHeader 将建议的重试等待时间写入 Retry-After 响应头，不足 1 秒按 1 秒计算
+func setRetryAfterHeader(h http.Header, err StatusError) {
+	d := BackoffHint(err)
+	if d <= 0 {
+		return
+	}
+	seconds := int64((d + time.Second - 1) / time.Second)
+	h.Set("Retry-After", strconv.FormatInt(seconds, 10))
+}
+
+// withRetryAfterHeader 根据 Retry-After 响应头为 se 补充建议的重试等待时间
+// 支持秒数与 HTTP 日期两种格式，响应体中已经包含等待时间时以响应体为准，超过 24 小时的值被忽略.
+func withRetryAfterHeader(se StatusError, h http.Header) StatusError {
+	if se == nil || BackoffHint(se) > 0 {
+		return se
+	}
+	v := h.Get("Retry-After")
+	if v == "" {
+		return se
+	}
+	var d time.Duration
+	if seconds, err := strconv.ParseInt(v, 10, 64); err == nil {
+		// 先检查范围再转换，避免乘法溢出
+		if seconds > int64(maxRetryAfter/time.Second) {
+			return se
+		}
+		d = time.Duration(seconds) * time.Second
+	} else if t, err := http.ParseTime(v); err == nil {
+		d = t.Sub(now())
+	}
+	if d <= 0 || d > maxRetryAfter {
+		return se
+	}
+	return With(se, RetryAfter(d))
+}
@@ -0,0 +1,84 @@
+package errors_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+
+	"github.com/go-anyway/framework-errors"
+)
+
+func TestBackoffHint(t *testing.T) {
+	err := errors.NewWithStatus(errors.CodeRateLimitExceeded, "", errors.RetryAfter(1500*time.Millisecond))
+	if got := errors.BackoffHint(err); got != 1500*time.Millisecond {
+		t.Errorf("BackoffHint() = %v, want 1.5s", got)
+	}
+	if got := errors.BackoffHint(errors.NewWithStatus(errors.CodeRateLimitExceeded, "")); got != 0 {
+		t.Errorf("BackoffHint() = %v, want 0", got)
+	}
+}
+
+func TestBackoffHintGRPC(t *testing.T) {
+	// 白名单模式下扩展信息不会传递，RetryInfo 仍然可以还原等待时间
+	errors.EnableExtraAllowlist()
+	defer errors.DisableExtraAllowlist()
+
+	st := errors.ToGRPCStatus(errors.NewWithStatus(errors.CodeRateLimitExceeded, "", errors.RetryAfter(2*time.Second)))
+	var found bool
+	for _, d := range st.Details() {
+		if ri, ok := d.(*errdetails.RetryInfo); ok {
+			found = ri.GetRetryDelay().AsDuration() == 2*time.Second
+		}
+	}
+	if !found {
+		t.Error("ToGRPCStatus() 应附加 RetryInfo")
+	}
+	if got := errors.BackoffHint(errors.FromGRPCStatus(st)); got != 2*time.Second {
+		t.Errorf("BackoffHint() = %v, want 2s", got)
+	}
+}
+
+func TestBackoffHintHTTP(t *testing.T) {
+	rec := httptest.NewRecorder()
+	errors.WriteError(rec, httptest.NewRequest(http.MethodGet, "/", nil),
+		errors.NewWithStatus(errors.CodeRateLimitExceeded, "", errors.RetryAfter(1500*time.Millisecond)))
+	if got := rec.Header().Get("Retry-After"); got != "2" {
+		t.Errorf("Retry-After = %q, want 2", got)
+	}
+
+	restore := errors.SetClock(func() time.Time { return time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC) })
+	defer restore()
+
+	tests := []struct {
+		name       string
+		retryAfter string
+		body       string
+		want       time.Duration
+	}{
+		{name: "秒数", retryAfter: "30", want: 30 * time.Second},
+		{name: "HTTP 日期", retryAfter: "Wed, 01 Jan 2025 00:01:00 GMT", want: time.Minute},
+		{name: "响应体优先", retryAfter: "30", body: `{"code":3001,"message":"too many","extra":{"retry_after_ms":"500"}}`, want: 500 * time.Millisecond},
+		{name: "无效值", retryAfter: "soon", want: 0},
+		{name: "超过 24 小时", retryAfter: "86401", want: 0},
+		{name: "溢出的秒数", retryAfter: "9223372036854775807", want: 0},
+		{name: "过远的 HTTP 日期", retryAfter: "Wed, 01 Jan 2125 00:00:00 GMT", want: 0},
+		{name: "响应体中溢出的毫秒数", body: `{"code":3001,"extra":{"retry_after_ms":"9223372036854775807"}}`, want: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := &http.Response{
+				StatusCode: http.StatusTooManyRequests,
+				Header:     http.Header{"Retry-After": []string{tt.retryAfter}},
+				Body:       io.NopCloser(strings.NewReader(tt.body)),
+			}
+			if got := errors.BackoffHint(errors.CheckResponse(resp)); got != tt.want {
+				t.Errorf("BackoffHint() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
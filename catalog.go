@@ -0,0 +1,184 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package errors
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+	"sort"
+	"strings"
+)
+
+// CatalogFormat 是错误码目录文档的格式
+type CatalogFormat int
+
+const (
+	// CatalogMarkdown 生成 Markdown 表格，默认值
+	CatalogMarkdown CatalogFormat = iota
+	// CatalogHTML 生成 HTML 页面
+	CatalogHTML
+)
+
+// CatalogDocOptions 是 GenerateCatalogDoc 的选项
+type CatalogDocOptions struct {
+	// Format 是文档格式
+	Format CatalogFormat
+	// Title 是文档标题，为空时使用“错误码目录”
+	Title string
+	// Locales 是需要列出消息的语言，为空时使用 DefaultLocale 与所有注册了消息的语言
+	Locales []string
+	// Names 是错误码的符号名称，与 ProtoEnumOptions.Names 含义相同
+	Names map[int32]string
+}
+
+// catalogRow 是错误码目录中的一行
+type catalogRow struct {
+	Code      int32
+	Name      string
+	Messages  []string
+	HTTP      int
+	GRPC      string
+	Retryable bool
+	Owner     string
+	DocURL    string
+}
+
+// GenerateCatalogDoc 根据已注册的错误码生成便于阅读的错误码目录
+// 每个错误码列出各语言的消息、HTTP 状态码与 gRPC code 映射、是否可重试、负责人与文档链接，
+// 适合在每次发布时生成并发布到开发者门户.
+//
+//	err := errors.GenerateCatalogDoc(f, errors.CatalogDocOptions{Format: errors.CatalogHTML})
+func GenerateCatalogDoc(w io.Writer, opts CatalogDocOptions) error {
+	if opts.Title == "" {
+		opts.Title = "错误码目录"
+	}
+	locales := opts.Locales
+	if len(locales) == 0 {
+		locales = []string{DefaultLocale}
+		for _, locale := range registeredLocales() {
+			if !strings.EqualFold(locale, DefaultLocale) {
+				locales = append(locales, locale)
+			}
+		}
+	}
+
+	codes := make([]int32, 0, len(CodeDefinitions))
+	for code := range CodeDefinitions {
+		codes = append(codes, code)
+	}
+	sort.Slice(codes, func(i, j int) bool { return codes[i] < codes[j] })
+
+	rows := make([]catalogRow, len(codes))
+	for i, code := range codes {
+		def := GetCodeDefinition(code)
+		row := catalogRow{
+			Code:      code,
+			Name:      codeName(code, opts.Names),
+			HTTP:      HTTPStatus(code),
+			GRPC:      GRPCCode(code).String(),
+			Retryable: def.Retryable,
+			Owner:     def.Owner,
+			DocURL:    def.DocURL,
+		}
+		for _, locale := range locales {
+			msg := def.Message
+			if !strings.EqualFold(locale, DefaultLocale) {
+				msg, _ = LocalizedMessage(code, locale)
+			}
+			row.Messages = append(row.Messages, msg)
+		}
+		rows[i] = row
+	}
+
+	if opts.Format == CatalogHTML {
+		return catalogHTML.Execute(w, map[string]interface{}{
+			"Title":   opts.Title,
+			"Locales": locales,
+			"Rows":    rows,
+		})
+	}
+	return writeCatalogMarkdown(w, opts.Title, locales, rows)
+}
+
+// writeCatalogMarkdown 将错误码目录写为 Markdown 表格
+func writeCatalogMarkdown(w io.Writer, title string, locales []string, rows []catalogRow) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s\n\n", title)
+	b.WriteString("| 错误码 | 名称 |")
+	for _, locale := range locales {
+		fmt.Fprintf(&b, " 消息（%s） |", markdownCell(locale))
+	}
+	b.WriteString(" HTTP | gRPC | 可重试 | 负责人 | 文档 |\n|")
+	for i := 0; i < len(locales)+7; i++ {
+		b.WriteString(" --- |")
+	}
+	b.WriteByte('\n')
+
+	for _, row := range rows {
+		fmt.Fprintf(&b, "| %d | %s |", row.Code, row.Name)
+		for _, msg := range row.Messages {
+			fmt.Fprintf(&b, " %s |", markdownCell(msg))
+		}
+		doc := ""
+		if row.DocURL != "" {
+			doc = "[链接](" + row.DocURL + ")"
+		}
+		fmt.Fprintf(&b, " %d | %s | %s | %s | %s |\n",
+			row.HTTP, row.GRPC, yesNo(row.Retryable), markdownCell(row.Owner), doc)
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// markdownCell 转义 Markdown 表格单元格中的特殊字符
+func markdownCell(s string) string {
+	s = strings.ReplaceAll(s, "|", `\|`)
+	return strings.ReplaceAll(s, "\n", "<br>")
+}
+
+func yesNo(b bool) string {
+	if b {
+		return "是"
+	}
+	return "否"
+}
+
+var catalogHTML = template.Must(template.New("catalog").Funcs(template.FuncMap{
+	"yesNo": yesNo,
+}).Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+<table>
+<thead>
+<tr><th>错误码</th><th>名称</th>{{range .Locales}}<th>消息（{{.}}）</th>{{end}}<th>HTTP</th><th>gRPC</th><th>可重试</th><th>负责人</th><th>文档</th></tr>
+</thead>
+<tbody>
+{{- range .Rows}}
+<tr id="{{.Code}}"><td>{{.Code}}</td><td>{{.Name}}</td>{{range .Messages}}<td>{{.}}</td>{{end}}<td>{{.HTTP}}</td><td>{{.GRPC}}</td><td>{{yesNo .Retryable}}</td><td>{{.Owner}}</td><td>{{if .DocURL}}<a href="{{.DocURL}}">链接</a>{{end}}</td></tr>
+{{- end}}
+</tbody>
+</table>
+</body>
+</html>
+`))
@@ -0,0 +1,59 @@
+package errors_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/go-anyway/framework-errors"
+)
+
+func TestGenerateCatalogDoc(t *testing.T) {
+	const codeOrderClosed int32 = 20001
+	errors.CodeDefinitions[codeOrderClosed] = errors.CodeDefinition{
+		Message:   "订单|已关闭",
+		Retryable: true,
+		Owner:     "orders-team",
+		DocURL:    "https://docs.example.com/errors/20001",
+	}
+	defer delete(errors.CodeDefinitions, codeOrderClosed)
+
+	tests := []struct {
+		name string
+		opts errors.CatalogDocOptions
+		want []string
+	}{
+		{
+			name: "Markdown",
+			opts: errors.CatalogDocOptions{Locales: []string{"zh-CN", "en"}, Names: map[int32]string{codeOrderClosed: "ORDER_CLOSED"}},
+			want: []string{
+				"# 错误码目录",
+				"| 错误码 | 名称 | 消息（zh-CN） | 消息（en） | HTTP | gRPC | 可重试 | 负责人 | 文档 |",
+				"| 1004 | NOT_FOUND | 资源未找到 | resource not found | 404 | NotFound | 否 |  |  |",
+				`| 20001 | ORDER_CLOSED | 订单\|已关闭 |  | 500 | Internal | 是 | orders-team | [链接](https://docs.example.com/errors/20001) |`,
+			},
+		},
+		{
+			name: "HTML",
+			opts: errors.CatalogDocOptions{Format: errors.CatalogHTML, Title: "订单服务 <错误码>", Locales: []string{"en"}},
+			want: []string{
+				"<h1>订单服务 &lt;错误码&gt;</h1>",
+				"<th>消息（en）</th>",
+				`<tr id="1004"><td>1004</td><td>NOT_FOUND</td><td>resource not found</td><td>404</td><td>NotFound</td><td>否</td>`,
+				`<td>orders-team</td><td><a href="https://docs.example.com/errors/20001">链接</a></td>`,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var b strings.Builder
+			if err := errors.GenerateCatalogDoc(&b, tt.opts); err != nil {
+				t.Fatalf("GenerateCatalogDoc() error = %v", err)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(b.String(), want) {
+					t.Errorf("GenerateCatalogDoc() 缺少 %q:\n%s", want, b.String())
+				}
+			}
+		})
+	}
+}
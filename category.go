@@ -0,0 +1,123 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package errors
+
+import (
+	"errors"
+	"sort"
+	"sync"
+)
+
+// 预定义的错误码分类
+const (
+	// CategoryAuth 是认证与授权相关的错误码
+	CategoryAuth = "auth"
+	// CategoryDependency 是依赖服务相关的错误码
+	CategoryDependency = "dependency"
+)
+
+var (
+	categoriesMu sync.RWMutex
+	// categories 是分类到错误码集合的映射
+	categories = map[string]map[int32]bool{
+		CategoryAuth: {
+			CodeUnauthorized: true,
+			CodeForbidden:    true,
+			CodeTokenExpired: true,
+		},
+		CategoryDependency: {
+			CodeDependencyUnavailable: true,
+			CodeDependencyTimeout:     true,
+			CodeDependencyDNSFailure:  true,
+			CodeDependencyTLSFailure:  true,
+			CodeDependencyRejected:    true,
+			CodeDependencyBadResponse: true,
+			CodeDependencyRateLimited: true,
+			CodeDependencyCircuitOpen: true,
+		},
+	}
+)
+
+// RegisterCategory 将错误码加入分类，中间件可以按分类而不是逐个错误码应用策略
+//
+//	errors.RegisterCategory(errors.CategoryAuth, CodeSessionRevoked, CodeMFARequired)
+func RegisterCategory(category string, codes ...int32) {
+	categoriesMu.Lock()
+	defer categoriesMu.Unlock()
+	if categories[category] == nil {
+		categories[category] = make(map[int32]bool, len(codes))
+	}
+	for _, code := range codes {
+		categories[category][code] = true
+	}
+}
+
+// Categories 返回错误码所属的分类，按字母顺序排列
+// 组合错误码（见 ComposeCode）同时属于原错误码的分类.
+func Categories(code int32) []string {
+	_, base := SplitCode(code)
+	categoriesMu.RLock()
+	defer categoriesMu.RUnlock()
+	var result []string
+	for category, codes := range categories {
+		if codes[code] || codes[base] {
+			result = append(result, category)
+		}
+	}
+	sort.Strings(result)
+	return result
+}
+
+// IsCategory 判断 err 的错误码是否属于分类
+//
+//	if errors.IsCategory(err, errors.CategoryAuth) {
+//		w.WriteHeader(http.StatusUnauthorized)
+//	}
+func IsCategory(err error, category string) bool {
+	var se StatusError
+	if !errors.As(err, &se) {
+		return false
+	}
+	_, base := SplitCode(se.Code())
+	categoriesMu.RLock()
+	defer categoriesMu.RUnlock()
+	codes := categories[category]
+	return codes[se.Code()] || codes[base]
+}
+
+// IsInRange 判断 err 的错误码是否在 [min, max] 范围内，例如 IsInRange(err, 2000, 2999)
+// 组合错误码（见 ComposeCode）按原错误码判断.
+func IsInRange(err error, min, max int32) bool {
+	var se StatusError
+	if !errors.As(err, &se) {
+		return false
+	}
+	_, base := SplitCode(se.Code())
+	return base >= min && base <= max
+}
+
+// categoryCodes 返回分类中的错误码，按从小到大排列
+func categoryCodes(category string) []int32 {
+	categoriesMu.RLock()
+	defer categoriesMu.RUnlock()
+	result := make([]int32, 0, len(categories[category]))
+	for code := range categories[category] {
+		result = append(result, code)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i] < result[j] })
+	return result
+}
@@ -0,0 +1,62 @@
+package errors_test
+
+import (
+	errstd "errors"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/go-anyway/framework-errors"
+)
+
+func TestIsInRange(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{name: "范围内", err: errors.NewWithStatus(errors.CodeUserNotFound, ""), want: true},
+		{name: "范围外", err: errors.NewWithStatus(errors.CodeNotFound, ""), want: false},
+		{name: "包装后", err: fmt.Errorf("get user: %w", errors.NewWithStatus(errors.CodeTokenExpired, "")), want: true},
+		{name: "组合错误码", err: errors.NewWithStatus(errors.ComposeCode(12, errors.CodeRateLimitExceeded), ""), want: true},
+		{name: "普通错误", err: errstd.New("boom"), want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := errors.IsInRange(tt.err, 2000, 2999); got != tt.want {
+				t.Errorf("IsInRange() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCategory(t *testing.T) {
+	const codeSessionRevoked int32 = 20010
+	errors.RegisterCategory(errors.CategoryAuth, codeSessionRevoked)
+	errors.RegisterCategory("billing", codeSessionRevoked)
+
+	tests := []struct {
+		name     string
+		err      error
+		category string
+		want     bool
+	}{
+		{name: "预定义", err: errors.NewWithStatus(errors.CodeTokenExpired, ""), category: errors.CategoryAuth, want: true},
+		{name: "注册", err: errors.NewWithStatus(codeSessionRevoked, ""), category: errors.CategoryAuth, want: true},
+		{name: "不属于", err: errors.NewWithStatus(errors.CodeNotFound, ""), category: errors.CategoryAuth, want: false},
+		{name: "组合错误码", err: errors.NewWithStatus(errors.ComposeCode(3, errors.CodeDependencyTimeout), ""), category: errors.CategoryDependency, want: true},
+		{name: "未知分类", err: errors.NewWithStatus(errors.CodeNotFound, ""), category: "unknown", want: false},
+		{name: "普通错误", err: errstd.New("boom"), category: errors.CategoryAuth, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := errors.IsCategory(tt.err, tt.category); got != tt.want {
+				t.Errorf("IsCategory() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	if got, want := errors.Categories(codeSessionRevoked), []string{errors.CategoryAuth, "billing"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Categories() = %v, want %v", got, want)
+	}
+}
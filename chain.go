@@ -0,0 +1,87 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package errors
+
+// maxChainDepth 是遍历错误链的最大深度，防止错误的 Unwrap 实现形成环
+const maxChainDepth = 100
+
+// Chain 返回从 err 开始、沿 Unwrap/Cause 逐层展开的错误链，第一个元素为 err 本身
+// 支持 withStatus、fmt.Errorf 的 %w、github.com/pkg/errors 风格的 Cause() 以及 errors.Join，
+// 遇到 errors.Join 等包含多个错误的节点时沿第一个分支继续. err 为 nil 时返回 nil.
+func Chain(err error) []error {
+	var chain []error
+	for err != nil && len(chain) < maxChainDepth {
+		chain = append(chain, err)
+		err = next(err)
+	}
+	return chain
+}
+
+// RootCause 返回错误链中最底层的错误，err 为 nil 时返回 nil
+func RootCause(err error) error {
+	chain := Chain(err)
+	if len(chain) == 0 {
+		return nil
+	}
+	return chain[len(chain)-1]
+}
+
+// next 返回错误链中的下一个错误
+func next(err error) error {
+	switch e := err.(type) {
+	case interface{ Unwrap() error }:
+		return e.Unwrap()
+	case interface{ Cause() error }:
+		return e.Cause()
+	case interface{ Unwrap() []error }:
+		for _, branch := range e.Unwrap() {
+			if branch != nil {
+				return branch
+			}
+		}
+	}
+	return nil
+}
+
+// Walk 以深度优先的顺序访问 err 展开后的整棵错误树，包括 errors.Join 的每个分支
+// fn 返回 false 时停止遍历，Walk 返回是否遍历完整棵树.
+//
+//	retryable := !errors.Walk(err, func(e error) bool {
+//		r, ok := e.(interface{ IsRetryable() bool })
+//		return !ok || !r.IsRetryable()
+//	})
+func Walk(err error, fn func(error) bool) bool {
+	return walk(err, fn, 0)
+}
+
+func walk(err error, fn func(error) bool, depth int) bool {
+	if err == nil || depth >= maxChainDepth {
+		return true
+	}
+	if !fn(err) {
+		return false
+	}
+	if branches, ok := err.(interface{ Unwrap() []error }); ok {
+		for _, branch := range branches.Unwrap() {
+			if !walk(branch, fn, depth+1) {
+				return false
+			}
+		}
+		return true
+	}
+	return walk(next(err), fn, depth+1)
+}
@@ -0,0 +1,90 @@
+package errors_test
+
+import (
+	errstd "errors"
+	"fmt"
+	"testing"
+
+	"github.com/go-anyway/framework-errors"
+)
+
+// causer 模拟 github.com/pkg/errors 风格的错误
+type causer struct {
+	msg   string
+	cause error
+}
+
+func (c *causer) Error() string { return c.msg + ": " + c.cause.Error() }
+func (c *causer) Cause() error  { return c.cause }
+
+func TestChain(t *testing.T) {
+	root := errstd.New("connection refused")
+	pkgErr := &causer{msg: "query", cause: root}
+	wrapped := fmt.Errorf("load user: %w", pkgErr)
+	se := errors.WrapWithStatusOptions(wrapped, errors.CodeDependencyUnavailable, "")
+	joined := errstd.Join(se, errstd.New("other"))
+
+	tests := []struct {
+		name string
+		err  error
+		want []error
+	}{
+		{name: "nil", err: nil, want: nil},
+		{name: "单个错误", err: root, want: []error{root}},
+		{name: "混合错误链", err: se, want: []error{se, wrapped, pkgErr, root}},
+		{name: "errors.Join 沿第一个分支", err: joined, want: []error{joined, se, wrapped, pkgErr, root}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := errors.Chain(tt.err)
+			if len(got) != len(tt.want) {
+				t.Fatalf("len(Chain()) = %d, want %d", len(got), len(tt.want))
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("Chain()[%d] = %v, want %v", i, got[i], tt.want[i])
+				}
+			}
+			var wantRoot error
+			if len(tt.want) > 0 {
+				wantRoot = tt.want[len(tt.want)-1]
+			}
+			if got := errors.RootCause(tt.err); got != wantRoot {
+				t.Errorf("RootCause() = %v, want %v", got, wantRoot)
+			}
+		})
+	}
+}
+
+func TestWalk(t *testing.T) {
+	a := errstd.New("a")
+	b := errors.NewStatusError(errors.CodeRateLimitExceeded, "", nil)
+	c := &causer{msg: "c", cause: errstd.New("d")}
+	tree := fmt.Errorf("top: %w", errstd.Join(a, errstd.Join(b, c)))
+
+	var visited []string
+	completed := errors.Walk(tree, func(e error) bool {
+		visited = append(visited, e.Error())
+		return true
+	})
+	if !completed {
+		t.Error("Walk() = false, want true")
+	}
+	// top、join、a、join、b、c、d
+	if len(visited) != 7 {
+		t.Errorf("visited = %v, want 7 errors", visited)
+	}
+
+	retryable := !errors.Walk(tree, func(e error) bool {
+		r, ok := e.(interface{ IsRetryable() bool })
+		return !ok || !r.IsRetryable()
+	})
+	if !retryable {
+		t.Error("错误树中存在可重试的错误")
+	}
+
+	if !errors.Walk(nil, func(error) bool { return false }) {
+		t.Error("Walk(nil) 应返回 true")
+	}
+}
@@ -0,0 +1,160 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package errors
+
+import (
+	"context"
+	"math/rand/v2"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// FaultInjectionHeader 是按请求注入错误的请求头（gRPC 中为同名的小写 metadata）
+// 值为错误码，可以附带注入概率，例如 "3001" 或 "3001;p=0.5". 只有 FaultInjection.AllowHeader 为 true 时生效.
+const FaultInjectionHeader = "X-Error-Inject"
+
+// FaultInjectedKey 是注入的错误在扩展信息中的标记，便于在日志与客户端区分真实错误
+const FaultInjectedKey = "fault_injected"
+
+// FaultRule 是一条错误注入规则
+type FaultRule struct {
+	// Method 是 HTTP 方法，为空时匹配所有方法，gRPC 请求忽略该字段
+	Method string
+	// Path 是 HTTP 路径或 gRPC 完整方法名（如 /order.v1.OrderService/Get）的匹配模式，
+	// 语法与 path.Match 相同，为空时匹配所有请求
+	Path string
+	// Code 是注入的错误码
+	Code int32
+	// Message 是注入的错误消息，为空时使用错误码的默认消息
+	Message string
+	// Probability 是注入的概率（0-1）
+	Probability float64
+}
+
+// FaultInjection 是错误注入的配置，用于在非生产环境测试客户端对各个错误码的处理，
+// 无需让真实的依赖服务出现故障. 零值不注入任何错误.
+type FaultInjection struct {
+	// Rules 是注入规则，按顺序匹配第一条规则
+	Rules []FaultRule
+	// AllowHeader 为 true 时允许调用方通过 FaultInjectionHeader 为单个请求注入错误，生产环境不要开启
+	AllowHeader bool
+}
+
+// match 返回请求需要注入的错误，不需要注入时返回 nil
+func (f *FaultInjection) match(method, target, header string) StatusError {
+	if f == nil {
+		return nil
+	}
+	if f.AllowHeader && header != "" {
+		if err := headerFault(header); err != nil {
+			return err
+		}
+	}
+	for _, r := range f.Rules {
+		if r.Method != "" && !strings.EqualFold(r.Method, method) {
+			continue
+		}
+		if r.Path != "" {
+			if ok, _ := path.Match(r.Path, target); !ok {
+				continue
+			}
+		}
+		if rand.Float64() < r.Probability {
+			return injectedError(r.Code, r.Message)
+		}
+		return nil
+	}
+	return nil
+}
+
+// headerFault 解析 FaultInjectionHeader，格式不正确或没有命中概率时返回 nil
+func headerFault(v string) StatusError {
+	codeStr, params, _ := strings.Cut(v, ";")
+	code, err := strconv.ParseInt(strings.TrimSpace(codeStr), 10, 32)
+	if err != nil || code <= 0 {
+		return nil
+	}
+	probability := 1.0
+	if p, ok := strings.CutPrefix(strings.TrimSpace(params), "p="); ok {
+		if probability, err = strconv.ParseFloat(p, 64); err != nil {
+			return nil
+		}
+	}
+	if rand.Float64() >= probability {
+		return nil
+	}
+	return injectedError(int32(code), "")
+}
+
+// injectedError 创建注入的错误
+func injectedError(code int32, message string) StatusError {
+	return NewWithStatus(code, message, Extra(FaultInjectedKey, "true"))
+}
+
+// FaultInjectionMiddleware 返回按 f 注入错误的 HTTP 中间件，f 为 nil 时不注入
+// 注入的错误通过 WriteError 渲染，与 handler 返回的错误完全一致.
+//
+//	if env != "prod" {
+//		r.Use(errors.FaultInjectionMiddleware(&errors.FaultInjection{AllowHeader: true}))
+//	}
+func FaultInjectionMiddleware(f *FaultInjection) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		if f == nil {
+			return next
+		}
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if err := f.match(r.Method, r.URL.Path, r.Header.Get(FaultInjectionHeader)); err != nil {
+				WriteError(w, r, err)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// UnaryServerFaultInterceptor 返回按 f 注入错误的 gRPC 一元拦截器，应位于 UnaryServerErrorInterceptor 之后
+func UnaryServerFaultInterceptor(f *FaultInjection) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if err := f.match("", info.FullMethod, faultHeader(ctx)); err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// StreamServerFaultInterceptor 返回按 f 注入错误的 gRPC 流拦截器，应位于 StreamServerErrorInterceptor 之后
+func StreamServerFaultInterceptor(f *FaultInjection) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if err := f.match("", info.FullMethod, faultHeader(ss.Context())); err != nil {
+			return err
+		}
+		return handler(srv, ss)
+	}
+}
+
+// faultHeader 从 incoming metadata 中读取 FaultInjectionHeader
+func faultHeader(ctx context.Context) string {
+	if v := metadata.ValueFromIncomingContext(ctx, strings.ToLower(FaultInjectionHeader)); len(v) > 0 {
+		return v[0]
+	}
+	return ""
+}
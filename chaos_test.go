@@ -0,0 +1,87 @@
+package errors_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-anyway/framework-errors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+func TestFaultInjectionMiddleware(t *testing.T) {
+	fi := &errors.FaultInjection{
+		Rules: []errors.FaultRule{
+			{Method: http.MethodPost, Path: "/orders/*", Code: errors.CodeDependencyTimeout, Probability: 1},
+			{Path: "/never", Code: errors.CodeInternalError, Probability: 0},
+		},
+	}
+	headerFI := &errors.FaultInjection{AllowHeader: true}
+
+	tests := []struct {
+		name   string
+		fi     *errors.FaultInjection
+		method string
+		path   string
+		header string
+		want   int
+	}{
+		{name: "命中规则", fi: fi, method: http.MethodPost, path: "/orders/1", want: http.StatusGatewayTimeout},
+		{name: "方法不匹配", fi: fi, method: http.MethodGet, path: "/orders/1", want: http.StatusOK},
+		{name: "概率为 0", fi: fi, method: http.MethodGet, path: "/never", want: http.StatusOK},
+		{name: "未开启请求头", fi: fi, method: http.MethodGet, path: "/", header: "1004", want: http.StatusOK},
+		{name: "请求头注入", fi: headerFI, method: http.MethodGet, path: "/", header: "1004", want: http.StatusNotFound},
+		{name: "请求头概率为 0", fi: headerFI, method: http.MethodGet, path: "/", header: "1004;p=0", want: http.StatusOK},
+		{name: "请求头格式错误", fi: headerFI, method: http.MethodGet, path: "/", header: "boom", want: http.StatusOK},
+		{name: "未配置", fi: nil, method: http.MethodPost, path: "/orders/1", want: http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := errors.FaultInjectionMiddleware(tt.fi)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			if tt.header != "" {
+				req.Header.Set(errors.FaultInjectionHeader, tt.header)
+			}
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+			if rec.Code != tt.want {
+				t.Errorf("status = %d, want %d", rec.Code, tt.want)
+			}
+		})
+	}
+}
+
+func TestUnaryServerFaultInterceptor(t *testing.T) {
+	interceptor := errors.UnaryServerFaultInterceptor(&errors.FaultInjection{
+		Rules:       []errors.FaultRule{{Path: "/order.v1.OrderService/*", Code: errors.CodeConflict, Message: "injected", Probability: 1}},
+		AllowHeader: true,
+	})
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) { return "ok", nil }
+
+	_, err := interceptor(context.Background(), nil, &grpc.UnaryServerInfo{FullMethod: "/order.v1.OrderService/Get"}, handler)
+	se, ok := err.(errors.StatusError)
+	if !ok || se.Code() != errors.CodeConflict || se.Msg() != "injected" || se.Extra()[errors.FaultInjectedKey] != "true" {
+		t.Errorf("err = %v", err)
+	}
+
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("x-error-inject", "3001"))
+	_, err = interceptor(ctx, nil, &grpc.UnaryServerInfo{FullMethod: "/user.v1.UserService/Get"}, handler)
+	if se, ok := err.(errors.StatusError); !ok || se.Code() != errors.CodeDependencyUnavailable {
+		t.Errorf("err = %v", err)
+	}
+
+	resp, err := interceptor(context.Background(), nil, &grpc.UnaryServerInfo{FullMethod: "/user.v1.UserService/Get"}, handler)
+	if err != nil || resp != "ok" {
+		t.Errorf("resp = %v, err = %v", resp, err)
+	}
+}
+
+func TestServerConfigFaultInjection(t *testing.T) {
+	cfg := errors.ServerConfig{FaultInjection: &errors.FaultInjection{}}
+	if got := len(errors.HTTPMiddlewares(cfg)); got != len(errors.HTTPMiddlewares(errors.ServerConfig{}))+1 {
+		t.Errorf("len(HTTPMiddlewares()) = %d", got)
+	}
+	grpc.NewServer(errors.GRPCServerOptions(cfg)...).Stop()
+}
@@ -0,0 +1,223 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"go/token"
+	"net/url"
+	"regexp"
+	"sort"
+	"text/template"
+
+	"go.yaml.in/yaml/v3"
+	"google.golang.org/grpc/codes"
+)
+
+// Catalog 是 YAML 错误码目录
+type Catalog struct {
+	Codes []CodeEntry `yaml:"codes"`
+}
+
+// CodeEntry 是目录中的一个错误码
+type CodeEntry struct {
+	Name            string            `yaml:"name"`
+	Code            int32             `yaml:"code"`
+	Message         string            `yaml:"message"`
+	Reason          string            `yaml:"reason"`
+	HTTP            int               `yaml:"http"`
+	GRPC            string            `yaml:"grpc"`
+	AffectStability bool              `yaml:"affect_stability"`
+	Retryable       bool              `yaml:"retryable"`
+	Timeout         bool              `yaml:"timeout"`
+	IdempotentSafe  bool              `yaml:"idempotent_safe"`
+	SLOImpact       string            `yaml:"slo_impact"`
+	Owner           string            `yaml:"owner"`
+	DocURL          string            `yaml:"doc_url"`
+	Messages        map[string]string `yaml:"messages"`
+}
+
+// sloImpacts 是 slo_impact 的取值到 errors.SLOImpact 常量名的映射
+var sloImpacts = map[string]string{
+	"none":           "SLOImpactNone",
+	"degraded":       "SLOImpactDegraded",
+	"budget_burning": "SLOImpactBudgetBurning",
+	"critical":       "SLOImpactCritical",
+}
+
+// SLOImpactConst 返回 slo_impact 对应的 errors.SLOImpact 常量名
+func (e CodeEntry) SLOImpactConst() string {
+	return sloImpacts[e.SLOImpact]
+}
+
+// grpcCodes 是 gRPC code 名称（如 NotFound）到 codes.Code 的映射
+var grpcCodes = func() map[string]codes.Code {
+	m := make(map[string]codes.Code)
+	for c := codes.OK; c <= codes.Unauthenticated; c++ {
+		m[c.String()] = c
+	}
+	return m
+}()
+
+// upperSnake 匹配 UPPER_SNAKE 格式的错误原因
+var upperSnake = regexp.MustCompile(`^[A-Z][A-Z0-9]*(_[A-Z0-9]+)*$`)
+
+// ParseCatalog 解析并校验 YAML 错误码目录
+func ParseCatalog(data []byte) (*Catalog, error) {
+	var c Catalog
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(&c); err != nil {
+		return nil, err
+	}
+
+	names := make(map[string]bool, len(c.Codes))
+	values := make(map[int32]bool, len(c.Codes))
+	for i, e := range c.Codes {
+		switch {
+		case !token.IsIdentifier("Code" + e.Name):
+			return nil, fmt.Errorf("codes[%d]: 名称 %q 不是合法的 Go 标识符", i, e.Name)
+		case names[e.Name]:
+			return nil, fmt.Errorf("codes[%d]: 名称 %s 重复", i, e.Name)
+		case e.Code <= 0:
+			return nil, fmt.Errorf("codes[%d]: %s 的错误码必须大于 0", i, e.Name)
+		case values[e.Code]:
+			return nil, fmt.Errorf("codes[%d]: 错误码 %d 重复", i, e.Code)
+		case e.Message == "":
+			return nil, fmt.Errorf("codes[%d]: %s 缺少 message", i, e.Name)
+		case e.Reason != "" && !upperSnake.MatchString(e.Reason):
+			return nil, fmt.Errorf("codes[%d]: %s 的 reason %q 不是 UPPER_SNAKE 格式", i, e.Name, e.Reason)
+		case e.HTTP != 0 && (e.HTTP < 100 || e.HTTP > 599):
+			return nil, fmt.Errorf("codes[%d]: %s 的 HTTP 状态码 %d 不合法", i, e.Name, e.HTTP)
+		}
+		if _, ok := grpcCodes[e.GRPC]; e.GRPC != "" && !ok {
+			return nil, fmt.Errorf("codes[%d]: %s 的 gRPC code %q 不合法", i, e.Name, e.GRPC)
+		}
+		if _, ok := sloImpacts[e.SLOImpact]; e.SLOImpact != "" && !ok {
+			return nil, fmt.Errorf("codes[%d]: %s 的 slo_impact %q 不合法", i, e.Name, e.SLOImpact)
+		}
+		if u, err := url.Parse(e.DocURL); e.DocURL != "" && (err != nil || !u.IsAbs() || u.Host == "") {
+			return nil, fmt.Errorf("codes[%d]: %s 的 doc_url %q 不是绝对 URL", i, e.Name, e.DocURL)
+		}
+		names[e.Name] = true
+		values[e.Code] = true
+	}
+	return &c, nil
+}
+
+// localeMessages 是某个语言下的消息，用于生成 RegisterMessages 调用
+type localeMessages struct {
+	Locale  string
+	Entries []CodeEntry
+}
+
+// Generate 根据目录生成 Go 源码
+func Generate(c *Catalog, pkg string) ([]byte, error) {
+	byLocale := make(map[string][]CodeEntry)
+	for _, e := range c.Codes {
+		for locale := range e.Messages {
+			byLocale[locale] = append(byLocale[locale], e)
+		}
+	}
+	locales := make([]localeMessages, 0, len(byLocale))
+	for locale, entries := range byLocale {
+		locales = append(locales, localeMessages{Locale: locale, Entries: entries})
+	}
+	sort.Slice(locales, func(i, j int) bool { return locales[i].Locale < locales[j].Locale })
+
+	hasGRPC := false
+	for _, e := range c.Codes {
+		hasGRPC = hasGRPC || e.GRPC != ""
+	}
+
+	var buf bytes.Buffer
+	err := codeTemplate.Execute(&buf, map[string]interface{}{
+		"Package": pkg,
+		"Codes":   c.Codes,
+		"Locales": locales,
+		"HasGRPC": hasGRPC,
+	})
+	if err != nil {
+		return nil, err
+	}
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("格式化生成的代码: %w", err)
+	}
+	return src, nil
+}
+
+var codeTemplate = template.Must(template.New("codes").Parse(`// Code generated by errorsgen. DO NOT EDIT.
+
+package {{.Package}}
+
+import (
+{{- if .HasGRPC}}
+	"google.golang.org/grpc/codes"
+{{end}}
+	"github.com/go-anyway/framework-errors"
+)
+
+// 业务错误码
+const (
+{{- range .Codes}}
+	// Code{{.Name}} {{.Message}}
+	Code{{.Name}} int32 = {{.Code}}
+{{- end}}
+)
+
+func init() {
+{{- range .Codes}}
+	errors.CodeDefinitions[Code{{.Name}}] = errors.CodeDefinition{
+		Message:           {{printf "%q" .Message}},
+{{- if .Reason}}
+		Reason:            {{printf "%q" .Reason}},
+{{- end}}
+		IsAffectStability: {{.AffectStability}},
+		Retryable:         {{.Retryable}},
+		Timeout:           {{.Timeout}},
+		IdempotentSafe:    {{.IdempotentSafe}},
+{{- if .SLOImpact}}
+		SLOImpact:         errors.{{.SLOImpactConst}},
+		HasSLOImpact:      true,
+{{- end}}
+{{- if .Owner}}
+		Owner:             {{printf "%q" .Owner}},
+{{- end}}
+{{- if .DocURL}}
+		DocURL:            {{printf "%q" .DocURL}},
+{{- end}}
+	}
+{{- if .HTTP}}
+	errors.HTTPStatusCodes[Code{{.Name}}] = {{.HTTP}}
+{{- end}}
+{{- if .GRPC}}
+	errors.RegisterGRPCCode(Code{{.Name}}, codes.{{.GRPC}})
+{{- end}}
+{{- end}}
+{{- range $l := .Locales}}
+
+	errors.RegisterMessages({{printf "%q" $l.Locale}}, map[int32]string{
+{{- range $l.Entries}}
+		Code{{.Name}}: {{printf "%q" (index .Messages $l.Locale)}},
+{{- end}}
+	})
+{{- end}}
+}
+`))
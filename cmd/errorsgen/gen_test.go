@@ -0,0 +1,72 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestGenerate(t *testing.T) {
+	data, err := os.ReadFile(filepath.Join("testdata", "errors.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	catalog, err := ParseCatalog(data)
+	if err != nil {
+		t.Fatalf("ParseCatalog() error = %v", err)
+	}
+	got, err := Generate(catalog, "tmpgen")
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	want, err := os.ReadFile(filepath.Join("testdata", "codes_gen.go.golden"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != string(want) {
+		t.Errorf("Generate() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestGenerateWithoutGRPC(t *testing.T) {
+	catalog, err := ParseCatalog([]byte("codes:\n  - name: CouponExpired\n    code: 20003\n    message: 优惠券已过期\n"))
+	if err != nil {
+		t.Fatalf("ParseCatalog() error = %v", err)
+	}
+	got, err := Generate(catalog, "coupon")
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	if strings.Contains(string(got), "grpc/codes") {
+		t.Errorf("Generate() 不应导入 gRPC codes:\n%s", got)
+	}
+}
+
+func TestParseCatalogErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		yaml string
+		want string
+	}{
+		{name: "非法名称", yaml: "codes:\n  - {name: order-closed, code: 1, message: x}\n", want: "合法的 Go 标识符"},
+		{name: "名称重复", yaml: "codes:\n  - {name: A, code: 1, message: x}\n  - {name: A, code: 2, message: x}\n", want: "名称 A 重复"},
+		{name: "错误码重复", yaml: "codes:\n  - {name: A, code: 1, message: x}\n  - {name: B, code: 1, message: x}\n", want: "错误码 1 重复"},
+		{name: "错误码非法", yaml: "codes:\n  - {name: A, code: 0, message: x}\n", want: "必须大于 0"},
+		{name: "缺少消息", yaml: "codes:\n  - {name: A, code: 1}\n", want: "缺少 message"},
+		{name: "reason 非法", yaml: "codes:\n  - {name: A, code: 1, message: x, reason: orderClosed}\n", want: "UPPER_SNAKE"},
+		{name: "HTTP 状态码非法", yaml: "codes:\n  - {name: A, code: 1, message: x, http: 42}\n", want: "HTTP 状态码"},
+		{name: "gRPC code 非法", yaml: "codes:\n  - {name: A, code: 1, message: x, grpc: NOT_FOUND}\n", want: "gRPC code"},
+		{name: "slo_impact 非法", yaml: "codes:\n  - {name: A, code: 1, message: x, slo_impact: high}\n", want: "slo_impact"},
+		{name: "doc_url 非法", yaml: "codes:\n  - {name: A, code: 1, message: x, doc_url: docs/a}\n", want: "doc_url"},
+		{name: "未知字段", yaml: "codes:\n  - {name: A, code: 1, message: x, team: orders}\n", want: "team"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseCatalog([]byte(tt.yaml))
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("ParseCatalog() error = %v, want 包含 %q", err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,74 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+// Command errorsgen 根据 YAML 错误码目录生成错误码常量、CodeDefinitions 注册代码
+// 以及 HTTP 状态码、gRPC code 与多语言消息的映射，取代手工维护的 codes.go.
+//
+//	//go:generate go run github.com/go-anyway/framework-errors/cmd/errorsgen -in errors.yaml -out codes_gen.go
+//
+// 目录格式：
+//
+//	codes:
+//	  - name: OrderClosed        # 生成常量 CodeOrderClosed
+//	    code: 20001
+//	    message: 订单已关闭
+//	    reason: ORDER_CLOSED     # 可选，错误原因（UPPER_SNAKE）
+//	    http: 409                # 可选，默认 500
+//	    grpc: FailedPrecondition # 可选，默认 Internal
+//	    affect_stability: false
+//	    retryable: false
+//	    timeout: false
+//	    idempotent_safe: false
+//	    messages:                # 可选，其他语言的消息
+//	      en: order closed
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+)
+
+func main() {
+	in := flag.String("in", "errors.yaml", "YAML 错误码目录")
+	out := flag.String("out", "codes_gen.go", "生成的 Go 文件")
+	pkg := flag.String("pkg", os.Getenv("GOPACKAGE"), "生成的 Go 文件的包名，默认为 go generate 所在的包")
+	flag.Parse()
+
+	if err := run(*in, *out, *pkg); err != nil {
+		fmt.Fprintln(os.Stderr, "errorsgen:", err)
+		os.Exit(1)
+	}
+}
+
+func run(in, out, pkg string) error {
+	if pkg == "" {
+		return fmt.Errorf("缺少包名，请通过 -pkg 指定")
+	}
+	data, err := os.ReadFile(in)
+	if err != nil {
+		return err
+	}
+	catalog, err := ParseCatalog(data)
+	if err != nil {
+		return fmt.Errorf("%s: %w", in, err)
+	}
+	src, err := Generate(catalog, pkg)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(out, src, 0o644)
+}
@@ -0,0 +1,30 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+// Command grpcreturn 检查 gRPC 方法未经转换直接返回 StatusError，见 analysis/grpcreturn.
+//
+//	go run github.com/go-anyway/framework-errors/cmd/grpcreturn ./...
+package main
+
+import (
+	"golang.org/x/tools/go/analysis/singlechecker"
+
+	"github.com/go-anyway/framework-errors/analysis/grpcreturn"
+)
+
+func main() {
+	singlechecker.Main(grpcreturn.Analyzer)
+}
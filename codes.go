@@ -19,7 +19,20 @@ package errors
 // CodeDefinition 定义了错误码的详细信息
 type CodeDefinition struct {
 	Message           string // 错误消息
+	Reason            string // 错误原因，即错误码的符号名称（UPPER_SNAKE），随错误传递给调用方
 	IsAffectStability bool   // 是否影响系统稳定性，可用于告警分级
+	Retryable         bool   // 是否可以重试，例如超时、限流、并发冲突
+	Timeout           bool   // 是否为超时类错误，对应 net.Error 的 Timeout()
+	// IdempotentSafe 表示失败的操作确定没有产生副作用，即使接口本身不是幂等的也可以安全重试，
+	// 例如请求在执行前被限流或参数校验拒绝. 超时等结果未知的错误不应标记.
+	IdempotentSafe bool
+	// SLOImpact 是对 SLO 的影响程度，未设置时影响稳定性的错误码视为 SLOImpactBudgetBurning，见 SLOImpactOf
+	SLOImpact SLOImpact
+	// HasSLOImpact 表示 SLOImpact 是显式设置的. SLOImpactNone 是零值，
+	// 影响稳定性的错误码需要同时设置该字段才能声明为 SLOImpactNone.
+	HasSLOImpact bool
+	Owner        string // 负责该错误码的团队，用于错误码目录文档
+	DocURL       string // 错误码说明文档的链接，用于错误码目录文档
 }
 
 // 业务错误码（使用 int32 以兼容 gRPC）
@@ -34,6 +47,9 @@ const (
 	CodeAlreadyExists  int32 = 1005
 	CodeInternalError  int32 = 1006
 	CodeRequestTimeout int32 = 1007
+	CodeConflict       int32 = 1008
+	CodeCacheMiss      int32 = 1009
+	CodeClientCanceled int32 = 1010
 
 	// 业务错误 2000-2999
 	CodeUserNotFound      int32 = 2001
@@ -41,6 +57,16 @@ const (
 	CodeRateLimitExceeded int32 = 2003
 	CodeTokenExpired      int32 = 2004
 	// ... 更多业务错误码可以在这里添加
+
+	// 依赖错误 3000-3999
+	CodeDependencyUnavailable int32 = 3001
+	CodeDependencyTimeout     int32 = 3002
+	CodeDependencyDNSFailure  int32 = 3003
+	CodeDependencyTLSFailure  int32 = 3004
+	CodeDependencyRejected    int32 = 3005
+	CodeDependencyBadResponse int32 = 3006
+	CodeDependencyRateLimited int32 = 3007
+	CodeDependencyCircuitOpen int32 = 3008
 )
 
 // CodeDefinitions 是预定义的错误码及其定义的映射
@@ -48,50 +74,140 @@ const (
 var CodeDefinitions = map[int32]CodeDefinition{
 	CodeSuccess: {
 		Message:           "success",
+		Reason:            "SUCCESS",
 		IsAffectStability: false,
 	},
 	CodeInvalidParam: {
 		Message:           "参数无效",
+		Reason:            "INVALID_PARAM",
 		IsAffectStability: false,
+		IdempotentSafe:    true,
 	},
 	CodeUnauthorized: {
 		Message:           "未授权",
+		Reason:            "UNAUTHORIZED",
 		IsAffectStability: false,
+		IdempotentSafe:    true,
 	},
 	CodeForbidden: {
 		Message:           "禁止访问",
+		Reason:            "FORBIDDEN",
 		IsAffectStability: false,
+		IdempotentSafe:    true,
 	},
 	CodeNotFound: {
 		Message:           "资源未找到",
+		Reason:            "NOT_FOUND",
 		IsAffectStability: false,
+		IdempotentSafe:    true,
 	},
 	CodeAlreadyExists: {
 		Message:           "资源已存在",
+		Reason:            "ALREADY_EXISTS",
 		IsAffectStability: false,
 	},
 	CodeInternalError: {
 		Message:           "内部服务器错误",
+		Reason:            "INTERNAL_ERROR",
 		IsAffectStability: true,
+		SLOImpact:         SLOImpactCritical,
 	},
 	CodeUserNotFound: {
 		Message:           "用户不存在",
+		Reason:            "USER_NOT_FOUND",
 		IsAffectStability: false,
 	},
 	CodeUserAlreadyExist: {
 		Message:           "用户已存在",
+		Reason:            "USER_ALREADY_EXIST",
 		IsAffectStability: false,
 	},
 	CodeRateLimitExceeded: {
 		Message:           "请求过于频繁",
+		Reason:            "RATE_LIMIT_EXCEEDED",
 		IsAffectStability: false,
+		Retryable:         true,
+		IdempotentSafe:    true,
 	},
 	CodeTokenExpired: {
 		Message:           "认证令牌已过期",
+		Reason:            "TOKEN_EXPIRED",
 		IsAffectStability: false,
 	},
 	CodeRequestTimeout: {
 		Message:           "请求超时",
+		Reason:            "REQUEST_TIMEOUT",
+		IsAffectStability: false,
+		Retryable:         true,
+		Timeout:           true,
+	},
+	CodeConflict: {
+		Message:           "资源冲突",
+		Reason:            "CONFLICT",
+		IsAffectStability: false,
+	},
+	CodeCacheMiss: {
+		Message:           "缓存未命中",
+		Reason:            "CACHE_MISS",
+		IsAffectStability: false,
+	},
+	CodeClientCanceled: {
+		Message:           "请求已取消",
+		Reason:            "CLIENT_CANCELED",
 		IsAffectStability: false,
 	},
+	CodeDependencyUnavailable: {
+		Message:           "依赖服务不可用",
+		Reason:            "DEPENDENCY_UNAVAILABLE",
+		IsAffectStability: true,
+		Retryable:         true,
+	},
+	CodeDependencyTimeout: {
+		Message:           "依赖服务超时",
+		Reason:            "DEPENDENCY_TIMEOUT",
+		IsAffectStability: true,
+		Retryable:         true,
+		Timeout:           true,
+	},
+	CodeDependencyDNSFailure: {
+		Message:           "依赖服务域名解析失败",
+		Reason:            "DEPENDENCY_DNS_FAILURE",
+		IsAffectStability: true,
+		Retryable:         true,
+		IdempotentSafe:    true,
+	},
+	CodeDependencyTLSFailure: {
+		Message:           "依赖服务 TLS 握手失败",
+		Reason:            "DEPENDENCY_TLS_FAILURE",
+		IsAffectStability: true,
+		Retryable:         false,
+		IdempotentSafe:    true,
+	},
+	CodeDependencyRejected: {
+		Message:           "依赖服务拒绝请求",
+		Reason:            "DEPENDENCY_REJECTED",
+		IsAffectStability: true,
+		Retryable:         false,
+	},
+	CodeDependencyBadResponse: {
+		Message:           "依赖服务返回无效响应",
+		Reason:            "DEPENDENCY_BAD_RESPONSE",
+		IsAffectStability: true,
+		Retryable:         false,
+	},
+	CodeDependencyRateLimited: {
+		Message:           "依赖服务限流",
+		Reason:            "DEPENDENCY_RATE_LIMITED",
+		IsAffectStability: true,
+		Retryable:         true,
+		IdempotentSafe:    true,
+	},
+	CodeDependencyCircuitOpen: {
+		Message:           "依赖服务已熔断",
+		Reason:            "DEPENDENCY_CIRCUIT_OPEN",
+		IsAffectStability: true,
+		Retryable:         true,
+		IdempotentSafe:    true,
+		SLOImpact:         SLOImpactDegraded,
+	},
 }
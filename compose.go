@@ -0,0 +1,59 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package errors
+
+// ServiceCodeFactor 是组合错误码中服务 ID 的倍数，组合错误码 = 服务 ID * ServiceCodeFactor + 错误码
+const ServiceCodeFactor = 100000
+
+// MaxServiceID 是组合错误码支持的最大服务 ID，保证组合后的错误码不超过 int32 的范围
+const MaxServiceID = 21473
+
+// ComposeCode 将服务 ID 嵌入错误码，错误经过多个服务传递时接收方可以据此判断最初出错的服务
+// 组合错误码的定义、HTTP 状态码、gRPC code 与多语言消息在没有单独注册时使用原错误码的配置.
+// 服务 ID 不在 1 到 MaxServiceID 之间、错误码不在 1 到 ServiceCodeFactor-1 之间或已经是组合错误码时原样返回.
+//
+//	const svcOrder = 12
+//	return errors.NewWithStatus(errors.ComposeCode(svcOrder, errors.CodeNotFound), "")
+func ComposeCode(service, code int32) int32 {
+	if service <= 0 || service > MaxServiceID || code <= 0 || code >= ServiceCodeFactor {
+		return code
+	}
+	return service*ServiceCodeFactor + code
+}
+
+// SplitCode 将组合错误码拆分为服务 ID 与原错误码，不是组合错误码时服务 ID 为 0
+func SplitCode(code int32) (service, base int32) {
+	if code < ServiceCodeFactor {
+		return 0, code
+	}
+	return code / ServiceCodeFactor, code % ServiceCodeFactor
+}
+
+// OriginService 返回错误码中嵌入的服务 ID，没有嵌入时返回 0
+func OriginService(err StatusError) int32 {
+	if err == nil {
+		return 0
+	}
+	service, _ := SplitCode(err.Code())
+	return service
+}
+
+// baseCode 返回组合错误码中的原错误码，用于查找没有单独注册的组合错误码的配置
+func baseCode(code int32) (int32, bool) {
+	service, base := SplitCode(code)
+	return base, service != 0
+}
@@ -0,0 +1,64 @@
+package errors_test
+
+import (
+	"net/http"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+
+	"github.com/go-anyway/framework-errors"
+)
+
+func TestComposeCode(t *testing.T) {
+	tests := []struct {
+		name        string
+		service     int32
+		code        int32
+		want        int32
+		wantService int32
+	}{
+		{name: "组合", service: 12, code: errors.CodeNotFound, want: 1201004, wantService: 12},
+		{name: "最大服务 ID", service: errors.MaxServiceID, code: 99999, want: 2147399999, wantService: errors.MaxServiceID},
+		{name: "服务 ID 非法", service: 0, code: errors.CodeNotFound, want: errors.CodeNotFound},
+		{name: "服务 ID 溢出", service: errors.MaxServiceID + 1, code: errors.CodeNotFound, want: errors.CodeNotFound},
+		{name: "已经是组合错误码", service: 7, code: 1201004, want: 1201004, wantService: 12},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := errors.ComposeCode(tt.service, tt.code)
+			if got != tt.want {
+				t.Fatalf("ComposeCode() = %d, want %d", got, tt.want)
+			}
+			service, base := errors.SplitCode(got)
+			if service != tt.wantService || (service != 0 && base != tt.code%errors.ServiceCodeFactor) {
+				t.Errorf("SplitCode(%d) = %d, %d", got, service, base)
+			}
+		})
+	}
+}
+
+func TestComposedCodeFallback(t *testing.T) {
+	code := errors.ComposeCode(12, errors.CodeNotFound)
+	err := errors.NewWithStatus(code, "")
+
+	if got := errors.OriginService(err); got != 12 {
+		t.Errorf("OriginService() = %d, want 12", got)
+	}
+	if err.Msg() != "资源未找到" || err.IsAffectStability() {
+		t.Errorf("组合错误码应使用原错误码的定义，got %q %v", err.Msg(), err.IsAffectStability())
+	}
+	if got := errors.HTTPStatus(code); got != http.StatusNotFound {
+		t.Errorf("HTTPStatus() = %d, want %d", got, http.StatusNotFound)
+	}
+	if got := errors.GRPCCode(code); got != codes.NotFound {
+		t.Errorf("GRPCCode() = %v, want %v", got, codes.NotFound)
+	}
+	if got := errors.Localize(err, "en"); got != "resource not found" {
+		t.Errorf("Localize() = %q, want %q", got, "resource not found")
+	}
+
+	// 经过 gRPC 传递后保留服务 ID
+	if got := errors.FromGRPCStatus(errors.ToGRPCStatus(err)); got.Code() != code {
+		t.Errorf("FromGRPCStatus().Code() = %d, want %d", got.Code(), code)
+	}
+}
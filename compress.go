@@ -0,0 +1,88 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package errors
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"io"
+	"strings"
+	"sync/atomic"
+)
+
+// compressedExtraPrefix 是压缩后的扩展信息值的前缀
+const compressedExtraPrefix = "gzip+base64:"
+
+// maxDecompressedExtraSize 是一个错误的所有扩展信息值解压后的最大总字节数，防止恶意构造的压缩数据
+const maxDecompressedExtraSize = 1 << 20
+
+var extraCompressionThreshold atomic.Int64
+
+// SetExtraCompressionThreshold 设置扩展信息值压缩的阈值（字节），0 表示不压缩（默认）
+// ToGRPCStatus 会将超过阈值的值（如 SQL 语句、请求快照等诊断信息）使用 gzip 压缩并
+// base64 编码后传输，FromGRPCStatus 会自动解压. 压缩后没有变小的值保持原样.
+func SetExtraCompressionThreshold(threshold int) {
+	extraCompressionThreshold.Store(int64(threshold))
+}
+
+// compressExtra 压缩超过阈值的扩展信息值
+func compressExtra(v string) string {
+	threshold := extraCompressionThreshold.Load()
+	if threshold <= 0 || int64(len(v)) <= threshold {
+		return v
+	}
+
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if _, err := zw.Write([]byte(v)); err != nil {
+		return v
+	}
+	if err := zw.Close(); err != nil {
+		return v
+	}
+
+	compressed := compressedExtraPrefix + base64.StdEncoding.EncodeToString(buf.Bytes())
+	if len(compressed) >= len(v) {
+		return v
+	}
+	return compressed
+}
+
+// decompressExtra 还原 compressExtra 压缩的值，最多解压 limit 字节，不是压缩数据或解压失败时原样返回
+func decompressExtra(v string, limit int) string {
+	encoded, ok := strings.CutPrefix(v, compressedExtraPrefix)
+	if !ok {
+		return v
+	}
+
+	data, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return v
+	}
+	zr, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return v
+	}
+	defer zr.Close()
+
+	raw, err := io.ReadAll(io.LimitReader(zr, int64(limit)))
+	if err != nil {
+		return v
+	}
+	return string(raw)
+}
@@ -0,0 +1,80 @@
+package errors_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/go-anyway/framework-errors"
+)
+
+func TestExtraCompression(t *testing.T) {
+	errors.SetExtraCompressionThreshold(128)
+	defer errors.SetExtraCompressionThreshold(0)
+
+	sql := strings.Repeat("SELECT * FROM orders WHERE id = 42;\n", 50)
+	tests := []struct {
+		name     string
+		encoding errors.DetailEncoding
+	}{
+		{name: "structpb 编码", encoding: errors.DetailEncodingStruct},
+		{name: "二进制编码", encoding: errors.DetailEncodingBinary},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errors.SetDetailEncoding(tt.encoding)
+			defer errors.SetDetailEncoding(errors.DetailEncodingStruct)
+
+			err := errors.NewStatusError(errors.CodeInternalError, "", map[string]string{"sql": sql, "table": "orders"})
+			st := errors.ToGRPCStatus(err)
+			if size := len(st.Proto().String()); size >= len(sql) {
+				t.Errorf("status 大小 = %d, 应小于原始值大小 %d", size, len(sql))
+			}
+
+			extra := errors.FromGRPCStatus(st).Extra()
+			if extra["sql"] != sql {
+				t.Errorf("Extra[sql] 解压后与原始值不一致, len = %d", len(extra["sql"]))
+			}
+			if extra["table"] != "orders" {
+				t.Errorf("Extra[table] = %s, want orders", extra["table"])
+			}
+		})
+	}
+}
+
+func TestExtraCompressionInvalidData(t *testing.T) {
+	st := errors.ToGRPCStatus(errors.NewStatusError(errors.CodeInternalError, "", map[string]string{"note": "gzip+base64:not-base64"}))
+	if got := errors.FromGRPCStatus(st).Extra()["note"]; got != "gzip+base64:not-base64" {
+		t.Errorf("Extra[note] = %s, 无法解压的值应原样返回", got)
+	}
+}
+
+func TestExtraDecompressionBudget(t *testing.T) {
+	errors.SetExtraCompressionThreshold(128)
+	defer errors.SetExtraCompressionThreshold(0)
+
+	// 每个值压缩后很小，解压后合计远超 1MB
+	extra := make(map[string]string)
+	for i := 0; i < 8; i++ {
+		extra[fmt.Sprintf("blob%d", i)] = strings.Repeat("a", 512<<10)
+	}
+	st := errors.ToGRPCStatus(errors.NewStatusError(errors.CodeInternalError, "", extra))
+
+	got := errors.FromGRPCStatus(st).Extra()
+	total := 0
+	for k, v := range got {
+		if k != errors.ExtraTruncatedKey {
+			total += len(v)
+		}
+	}
+	if total > 1<<20 {
+		t.Errorf("解压后的总大小 = %d, want <= %d", total, 1<<20)
+	}
+	if got["blob0"] != extra["blob0"] {
+		t.Errorf("len(Extra[blob0]) = %d, 预算内的值应完整解压", len(got["blob0"]))
+	}
+	if got[errors.ExtraTruncatedKey] != "true" {
+		t.Error("超出预算时应添加 ExtraTruncatedKey")
+	}
+}
@@ -0,0 +1,140 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package errors
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// StackMode 控制调用堆栈的采集与传递
+type StackMode int32
+
+const (
+	// StackModeFull 采集调用堆栈，记录到日志并通过 gRPC details 传递给调用方，默认值
+	StackModeFull StackMode = iota
+	// StackModeLogOnly 采集调用堆栈，只记录到日志，不会被序列化到 gRPC details、HTTP 响应体和消息头中
+	StackModeLogOnly
+	// StackModeOff 不采集调用堆栈，指纹改为由错误码和消息计算
+	StackModeOff
+)
+
+// RedactedValue 是 RedactKeys 中的扩展信息序列化和记录日志时使用的值
+const RedactedValue = "[REDACTED]"
+
+// Config 是本包的全局配置
+type Config struct {
+	// StackMode 控制调用堆栈的采集与传递
+	StackMode StackMode
+	// RedactKeys 中的扩展信息在序列化和记录日志时值被替换为 RedactedValue
+	RedactKeys []string
+	// DetailBudget 是 gRPC details 的最大字节数，0 表示不限制，见 SetDetailBudget
+	DetailBudget int
+	// DetailEncoding 是 gRPC details 的编码方式，见 SetDetailEncoding
+	DetailEncoding DetailEncoding
+	// DebugDetails 为 true 时 WriteError 等渲染的 HTTP 响应中包含调用堆栈和 InternalExtra
+	DebugDetails bool
+	// DefaultLocale 是默认消息使用的语言，为空时使用 zh-CN
+	DefaultLocale string
+	// ExtraAllowlist 不为 nil 时开启白名单模式，见 EnableExtraAllowlist
+	ExtraAllowlist []string
+	// ExtraCompressionThreshold 是扩展信息值压缩的阈值，0 表示不压缩，见 SetExtraCompressionThreshold
+	ExtraCompressionThreshold int
+	// LogDedupWindow 是日志去重窗口，0 表示不去重，见 SetLogDedupWindow
+	LogDedupWindow time.Duration
+	// ErrorCounts 为 true 时按错误码统计错误数量，见 EnableErrorCounts
+	ErrorCounts bool
+	// MessageOverrides 是按语言运行时覆盖的消息，见 SetMessageOverrides
+	MessageOverrides map[string]map[int32]string
+	// RequestSnapshots 为 true 时 AttachRequestSnapshot 附加请求快照，见 EnableRequestSnapshots
+	RequestSnapshots bool
+}
+
+var (
+	stackMode    atomic.Int32
+	redactKeys   atomic.Pointer[map[string]bool]
+	debugDetails atomic.Bool
+)
+
+// DevelopmentDefaults 返回适用于开发环境的配置
+// 调用堆栈随错误传递并出现在 HTTP 响应中，便于本地调试.
+func DevelopmentDefaults() Config {
+	return Config{
+		StackMode:        StackModeFull,
+		DebugDetails:     true,
+		DefaultLocale:    "zh-CN",
+		ErrorCounts:      true,
+		RequestSnapshots: true,
+	}
+}
+
+// ProductionDefaults 返回适用于生产环境的配置
+// 调用堆栈只记录到日志，常见的凭证类扩展信息被脱敏，并限制 gRPC details 的大小.
+func ProductionDefaults() Config {
+	return Config{
+		StackMode:     StackModeLogOnly,
+		RedactKeys:    []string{"password", "token", "secret", "authorization", "cookie"},
+		DetailBudget:  8 << 10,
+		DefaultLocale: "zh-CN",
+	}
+}
+
+// Configure 使用 cfg 替换本包的全局配置，cfg 中的零值字段恢复为默认行为
+// 应在程序启动时、创建任何错误之前调用.
+//
+//	errors.Configure(errors.ProductionDefaults())
+func Configure(cfg Config) {
+	stackMode.Store(int32(cfg.StackMode))
+
+	if len(cfg.RedactKeys) > 0 {
+		keys := make(map[string]bool, len(cfg.RedactKeys))
+		for _, k := range cfg.RedactKeys {
+			keys[k] = true
+		}
+		redactKeys.Store(&keys)
+	} else {
+		redactKeys.Store(nil)
+	}
+
+	debugDetails.Store(cfg.DebugDetails)
+
+	if cfg.DefaultLocale != "" {
+		DefaultLocale = cfg.DefaultLocale
+	} else {
+		DefaultLocale = "zh-CN"
+	}
+
+	if cfg.ExtraAllowlist != nil {
+		EnableExtraAllowlist(cfg.ExtraAllowlist...)
+	} else {
+		DisableExtraAllowlist()
+	}
+
+	SetDetailBudget(cfg.DetailBudget)
+	SetExtraCompressionThreshold(cfg.ExtraCompressionThreshold)
+	SetLogDedupWindow(cfg.LogDedupWindow)
+	EnableErrorCounts(cfg.ErrorCounts)
+	SetMessageOverrides(cfg.MessageOverrides)
+	EnableRequestSnapshots(cfg.RequestSnapshots)
+	// SetDetailEncoding 会清空 gRPC status 缓存，放在最后
+	SetDetailEncoding(cfg.DetailEncoding)
+}
+
+// getStackMode 获取当前的 StackMode
+func getStackMode() StackMode {
+	return StackMode(stackMode.Load())
+}
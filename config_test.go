@@ -0,0 +1,73 @@
+package errors_test
+
+import (
+	"encoding/json"
+	errstd "errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-anyway/framework-errors"
+)
+
+func TestConfigureProductionDefaults(t *testing.T) {
+	errors.Configure(errors.ProductionDefaults())
+	defer errors.Configure(errors.Config{})
+
+	err := errors.NewWithStatus(errors.CodeUnauthorized, "", errors.Extra("token", "abc"), errors.Extra("order_id", "7"))
+
+	// 日志中保留堆栈
+	if err.Extra()["stack"] == "" {
+		t.Error("StackModeLogOnly 应采集调用堆栈")
+	}
+
+	wire := errors.FromGRPCStatus(errors.ToGRPCStatus(err)).Extra()
+	if _, ok := wire["stack"]; ok {
+		t.Errorf("gRPC Extra() = %v, 不应包含 stack", wire)
+	}
+	if wire["token"] != errors.RedactedValue || wire["order_id"] != "7" {
+		t.Errorf("gRPC Extra() = %v, token 应被脱敏", wire)
+	}
+	if got := errors.NewEnvelope(err).Debug; got != nil {
+		t.Errorf("Envelope.Debug = %+v, want nil", got)
+	}
+}
+
+func TestConfigureStackModeOff(t *testing.T) {
+	errors.Configure(errors.Config{StackMode: errors.StackModeOff})
+	defer errors.Configure(errors.Config{})
+
+	if _, ok := errors.NewWithStatus(errors.CodeNotFound, "").Extra()["stack"]; ok {
+		t.Error("StackModeOff 不应采集调用堆栈")
+	}
+}
+
+func TestConfigureDevelopmentDefaults(t *testing.T) {
+	errors.Configure(errors.DevelopmentDefaults())
+	defer errors.Configure(errors.Config{})
+
+	cause := errstd.New("connection refused")
+	rec := httptest.NewRecorder()
+	errors.WriteError(rec, httptest.NewRequest(http.MethodGet, "/", nil),
+		errors.WrapWithStatusOptions(cause, errors.CodeInternalError, "", errors.InternalExtra("sql", "SELECT 1")))
+
+	var env errors.Envelope
+	if err := json.Unmarshal(rec.Body.Bytes(), &env); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+	if env.Debug == nil {
+		t.Fatal("Envelope.Debug = nil, 开发环境应包含诊断信息")
+	}
+	if env.Debug.Stack == "" {
+		t.Error("Debug.Stack 为空")
+	}
+	if env.Debug.Internal["sql"] != "SELECT 1" {
+		t.Errorf("Debug.Internal = %v, 应包含 sql", env.Debug.Internal)
+	}
+	if len(env.Debug.Causes) == 0 || env.Debug.Causes[len(env.Debug.Causes)-1] != "connection refused" {
+		t.Errorf("Debug.Causes = %v", env.Debug.Causes)
+	}
+	if _, ok := env.Extra["sql"]; ok {
+		t.Errorf("Extra = %v, 不应包含 sql", env.Extra)
+	}
+}
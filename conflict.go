@@ -0,0 +1,91 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package errors
+
+import "errors"
+
+// 版本冲突在扩展信息中的 key
+const (
+	ConflictResourceKey = "conflict_resource"
+	CurrentVersionKey   = "current_version"
+	ExpectedVersionKey  = "expected_version"
+)
+
+// VersionConflict 是乐观并发控制失败时携带的版本信息
+type VersionConflict struct {
+	// Resource 是发生冲突的资源，例如 "orders/123"
+	Resource string
+	// CurrentVersion 是资源当前的版本
+	CurrentVersion string
+	// ExpectedVersion 是调用方期望（读取时）的版本
+	ExpectedVersion string
+}
+
+// NewConflict 创建携带版本信息的 CodeConflict 错误，用于乐观并发控制失败
+// 版本信息随扩展信息在 gRPC details、HTTP 响应体与消息头中传递，
+// 客户端可以通过 Conflict 判断是否需要重新读取资源后重试.
+//
+//	if n == 0 {
+//		return errors.NewConflict("orders/"+id, current, req.Version)
+//	}
+func NewConflict(resource, currentVersion, expectedVersion string, opts ...Option) StatusError {
+	conflict := []Option{
+		Extra(ConflictResourceKey, resource),
+		Extra(CurrentVersionKey, currentVersion),
+		Extra(ExpectedVersionKey, expectedVersion),
+	}
+	return NewWithStatus(CodeConflict, "", append(conflict, opts...)...)
+}
+
+// Conflict 返回版本冲突信息，没有时第二个返回值为 false
+func (e *statusError) Conflict() (VersionConflict, bool) {
+	return versionConflict(e.ext.Extra)
+}
+
+// Conflict 返回版本冲突信息，没有时第二个返回值为 false
+func (w *withStatus) Conflict() (VersionConflict, bool) {
+	return w.status.Conflict()
+}
+
+// Conflict 返回 err 携带的版本冲突信息，没有时第二个返回值为 false
+//
+//	if c, ok := errors.Conflict(err); ok {
+//		order, _ = client.GetOrder(ctx, c.Resource)
+//		// 基于最新版本重新提交
+//	}
+func Conflict(err error) (VersionConflict, bool) {
+	var c interface {
+		Conflict() (VersionConflict, bool)
+	}
+	if errors.As(err, &c) {
+		return c.Conflict()
+	}
+	return VersionConflict{}, false
+}
+
+// versionConflict 从扩展信息中解析版本冲突信息，没有 ConflictResourceKey 时返回 false
+func versionConflict(extra map[string]string) (VersionConflict, bool) {
+	resource, ok := extra[ConflictResourceKey]
+	if !ok {
+		return VersionConflict{}, false
+	}
+	return VersionConflict{
+		Resource:        resource,
+		CurrentVersion:  extra[CurrentVersionKey],
+		ExpectedVersion: extra[ExpectedVersionKey],
+	}, true
+}
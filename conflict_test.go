@@ -0,0 +1,39 @@
+package errors_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/go-anyway/framework-errors"
+)
+
+func TestNewConflict(t *testing.T) {
+	err := errors.NewConflict("orders/1", "v3", "v2")
+	if err.Code() != errors.CodeConflict {
+		t.Errorf("Code() = %d", err.Code())
+	}
+
+	body, _ := json.Marshal(errors.NewEnvelope(err))
+	tests := []struct {
+		name string
+		err  error
+	}{
+		{name: "本地错误", err: err},
+		{name: "gRPC 往返", err: errors.FromGRPCStatus(errors.ToGRPCStatus(err))},
+		{name: "消息头往返", err: errors.DecodeHeaders(errors.EncodeHeaders(err))},
+		{name: "HTTP 响应体", err: errors.FromHTTPResponse(409, body)},
+	}
+	want := errors.VersionConflict{Resource: "orders/1", CurrentVersion: "v3", ExpectedVersion: "v2"}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := errors.Conflict(tt.err)
+			if !ok || got != want {
+				t.Errorf("Conflict() = %+v, %v, want %+v", got, ok, want)
+			}
+		})
+	}
+
+	if _, ok := errors.Conflict(errors.NewWithStatus(errors.CodeConflict, "")); ok {
+		t.Error("没有版本信息的错误不应返回版本冲突")
+	}
+}
@@ -0,0 +1,57 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package errors
+
+import (
+	"context"
+	"errors"
+)
+
+// FromContextErr 将 context 相关的错误转换为 StatusError
+// context.DeadlineExceeded 转换为 CodeRequestTimeout，context.Canceled 转换为 CodeClientCanceled，
+// 二者都不会被标记为影响稳定性.
+// err 为 nil 时使用 ctx.Err() 判断；既不是 context 错误且 ctx 也未结束时返回 nil.
+func FromContextErr(ctx context.Context, err error) StatusError {
+	cause := contextCause(err)
+	if cause == nil && ctx != nil {
+		cause = ctx.Err()
+	}
+	if cause == nil {
+		return nil
+	}
+	if err == nil {
+		err = cause
+	}
+
+	code := CodeClientCanceled
+	if errors.Is(cause, context.DeadlineExceeded) {
+		code = CodeRequestTimeout
+	}
+	return WrapWithStatusOptions(err, code, "", OverrideCode())
+}
+
+// contextCause 返回 err 链中的 context 错误，不存在时返回 nil
+func contextCause(err error) error {
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		return context.DeadlineExceeded
+	case errors.Is(err, context.Canceled):
+		return context.Canceled
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,73 @@
+package errors_test
+
+import (
+	"context"
+	errstd "errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/go-anyway/framework-errors"
+
+	"google.golang.org/grpc/codes"
+)
+
+func TestFromContextErr(t *testing.T) {
+	canceledCtx, cancel := context.WithCancel(context.Background())
+	cancel()
+	expiredCtx, cancel2 := context.WithTimeout(context.Background(), -time.Second)
+	defer cancel2()
+
+	tests := []struct {
+		name     string
+		ctx      context.Context
+		err      error
+		expected int32
+	}{
+		{"超时", context.Background(), context.DeadlineExceeded, errors.CodeRequestTimeout},
+		{"取消", context.Background(), fmt.Errorf("query: %w", context.Canceled), errors.CodeClientCanceled},
+		{"ctx 已取消", canceledCtx, errstd.New("driver: bad connection"), errors.CodeClientCanceled},
+		{"ctx 已超时", expiredCtx, nil, errors.CodeRequestTimeout},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			se := errors.FromContextErr(tt.ctx, tt.err)
+			if se == nil {
+				t.Fatal("FromContextErr() = nil, want StatusError")
+			}
+			if se.Code() != tt.expected {
+				t.Errorf("Code() = %d, want %d", se.Code(), tt.expected)
+			}
+			if se.IsAffectStability() {
+				t.Error("context 错误不应影响稳定性")
+			}
+		})
+	}
+}
+
+func TestFromContextErrNotContext(t *testing.T) {
+	if se := errors.FromContextErr(context.Background(), errstd.New("other")); se != nil {
+		t.Errorf("FromContextErr() = %v, want nil", se)
+	}
+	if se := errors.FromContextErr(context.Background(), nil); se != nil {
+		t.Errorf("FromContextErr() = %v, want nil", se)
+	}
+}
+
+func TestTranslateContextErr(t *testing.T) {
+	if se := errors.Translate(context.DeadlineExceeded); se.Code() != errors.CodeRequestTimeout {
+		t.Errorf("Code() = %d, want %d", se.Code(), errors.CodeRequestTimeout)
+	}
+}
+
+func TestToGRPCStatusContextCodes(t *testing.T) {
+	st := errors.ToGRPCStatus(errors.NewStatusError(errors.CodeRequestTimeout, "", nil))
+	if st.Code() != codes.DeadlineExceeded {
+		t.Errorf("gRPC status code = %v, want %v", st.Code(), codes.DeadlineExceeded)
+	}
+	st = errors.ToGRPCStatus(errors.NewStatusError(errors.CodeClientCanceled, "", nil))
+	if st.Code() != codes.Canceled {
+		t.Errorf("gRPC status code = %v, want %v", st.Code(), codes.Canceled)
+	}
+}
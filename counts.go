@@ -0,0 +1,58 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package errors
+
+import (
+	"strconv"
+	"sync"
+	"sync/atomic"
+)
+
+var (
+	errorCountsEnabled atomic.Bool
+	// errorCounts 是错误码到 *atomic.Int64 的映射
+	errorCounts sync.Map
+)
+
+// EnableErrorCounts 开启或关闭按错误码统计错误数量，默认关闭
+// 开启后 LogAndReturnError 与 WriteError 处理的错误会被计数，可以通过 ErrorCounts 读取，
+// 或使用 expvarerr.Publish 发布到 /debug/vars，无需指标系统即可查看实例的错误分布. 关闭后已有的计数保留.
+func EnableErrorCounts(enabled bool) {
+	errorCountsEnabled.Store(enabled)
+}
+
+// ErrorCounts 返回各错误码的错误数量快照，key 为错误码的十进制字符串
+func ErrorCounts() map[string]int64 {
+	counts := make(map[string]int64)
+	errorCounts.Range(func(k, v interface{}) bool {
+		counts[strconv.Itoa(int(k.(int32)))] = v.(*atomic.Int64).Load()
+		return true
+	})
+	return counts
+}
+
+// countErrorCode 将错误计入按错误码的错误数量
+func countErrorCode(code int32) {
+	if !errorCountsEnabled.Load() {
+		return
+	}
+	v, ok := errorCounts.Load(code)
+	if !ok {
+		v, _ = errorCounts.LoadOrStore(code, new(atomic.Int64))
+	}
+	v.(*atomic.Int64).Add(1)
+}
@@ -0,0 +1,36 @@
+package errors_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-anyway/framework-errors"
+)
+
+func TestEnableErrorCounts(t *testing.T) {
+	errors.EnableErrorCounts(true)
+	defer errors.EnableErrorCounts(false)
+
+	before := errors.ErrorCounts()["1004"]
+	_ = errors.LogAndReturnError(context.Background(), errors.NewStatusError(errors.CodeNotFound, "", nil), errors.SkipLog())
+	if got := errors.ErrorCounts()["1004"] - before; got != 1 {
+		t.Errorf("ErrorCounts()[1004] 增加了 %d, want 1", got)
+	}
+
+	// 关闭后不再计数
+	errors.EnableErrorCounts(false)
+	_ = errors.LogAndReturnError(context.Background(), errors.NewStatusError(errors.CodeNotFound, "", nil), errors.SkipLog())
+	if got := errors.ErrorCounts()["1004"] - before; got != 1 {
+		t.Errorf("ErrorCounts()[1004] 增加了 %d, want 1", got)
+	}
+}
+
+func TestNoDebugVarsOnDefaultMux(t *testing.T) {
+	// 本包不应引入 expvar，否则 /debug/vars 会被注册到 http.DefaultServeMux
+	_, pattern := http.DefaultServeMux.Handler(httptest.NewRequest(http.MethodGet, "/debug/vars", nil))
+	if pattern != "" {
+		t.Errorf("http.DefaultServeMux 注册了 %q", pattern)
+	}
+}
@@ -0,0 +1,61 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package errors
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+)
+
+// HeaderDebugErrors 是开启单个请求调试模式的请求头，值为 true 或 1 时生效
+const HeaderDebugErrors = "X-Debug-Errors"
+
+type debugContextKey struct{}
+
+// WithDebugErrors 返回开启调试模式的 context
+// 使用该 context 处理的请求在渲染错误时附带调用堆栈、错误链与内部扩展信息（见 DebugInfo），
+// 便于在不改变全局配置的情况下排查线上问题. 只应对经过认证的内部调用方开启.
+func WithDebugErrors(ctx context.Context) context.Context {
+	return context.WithValue(ctx, debugContextKey{}, true)
+}
+
+// DebugErrorsFromContext 判断 ctx 是否开启了调试模式
+func DebugErrorsFromContext(ctx context.Context) bool {
+	if ctx == nil {
+		return false
+	}
+	enabled, _ := ctx.Value(debugContextKey{}).(bool)
+	return enabled
+}
+
+// DebugMiddleware 返回根据 X-Debug-Errors 请求头开启调试模式的 HTTP 中间件
+// 只有 authorize 返回 true 的请求才会开启，authorize 为 nil 时始终不开启.
+//
+//	r.Use(errors.DebugMiddleware(func(r *http.Request) bool {
+//		return auth.IsSupportEngineer(r.Context())
+//	}))
+func DebugMiddleware(authorize func(r *http.Request) bool) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if enabled, _ := strconv.ParseBool(r.Header.Get(HeaderDebugErrors)); enabled && authorize != nil && authorize(r) {
+				r = r.WithContext(WithDebugErrors(r.Context()))
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
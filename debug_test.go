@@ -0,0 +1,87 @@
+package errors_test
+
+import (
+	"encoding/json"
+	errstd "errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-anyway/framework-errors"
+)
+
+func TestDebugMiddleware(t *testing.T) {
+	h := errors.DebugMiddleware(func(r *http.Request) bool {
+		return r.Header.Get("X-Role") == "support"
+	})(errors.Handler(func(w http.ResponseWriter, r *http.Request) error {
+		return errors.NewWithStatus(errors.CodeInternalError, "", errors.InternalExtra("sql", "SELECT 1"))
+	}))
+
+	tests := []struct {
+		name      string
+		headers   map[string]string
+		wantDebug bool
+	}{
+		{name: "未开启", headers: nil, wantDebug: false},
+		{name: "未授权", headers: map[string]string{errors.HeaderDebugErrors: "true"}, wantDebug: false},
+		{name: "已授权", headers: map[string]string{errors.HeaderDebugErrors: "true", "X-Role": "support"}, wantDebug: true},
+		{name: "请求头值无效", headers: map[string]string{errors.HeaderDebugErrors: "yes", "X-Role": "support"}, wantDebug: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			for k, v := range tt.headers {
+				req.Header.Set(k, v)
+			}
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+
+			var env errors.Envelope
+			if err := json.Unmarshal(rec.Body.Bytes(), &env); err != nil {
+				t.Fatalf("json.Unmarshal() error = %v", err)
+			}
+			if got := env.Debug != nil; got != tt.wantDebug {
+				t.Fatalf("Envelope.Debug = %+v, wantDebug %v", env.Debug, tt.wantDebug)
+			}
+			if tt.wantDebug && (env.Debug.Stack == "" || env.Debug.Internal["sql"] != "SELECT 1") {
+				t.Errorf("Envelope.Debug = %+v", env.Debug)
+			}
+		})
+	}
+}
+
+func TestDebugCausesScrubbed(t *testing.T) {
+	defer errors.ResetScrubbers()
+	errors.RegisterScrubber(func(key, value string) (string, bool) {
+		return strings.ReplaceAll(value, "secret", "***"), true
+	})
+
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	errors.WriteError(rec, req.WithContext(errors.WithDebugErrors(req.Context())),
+		errors.WrapWithStatusOptions(errstd.New("dial postgres://app:secret@db"), errors.CodeInternalError, ""))
+
+	var env errors.Envelope
+	if err := json.Unmarshal(rec.Body.Bytes(), &env); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+	if env.Debug == nil || len(env.Debug.Causes) == 0 {
+		t.Fatalf("Envelope.Debug = %+v", env.Debug)
+	}
+	for _, cause := range env.Debug.Causes {
+		if strings.Contains(cause, "secret") {
+			t.Errorf("Debug.Causes = %v, 应经过 Scrubber 处理", env.Debug.Causes)
+		}
+	}
+}
+
+func TestDebugErrorsFromContext(t *testing.T) {
+	ctx := httptest.NewRequest(http.MethodGet, "/", nil).Context()
+	if errors.DebugErrorsFromContext(ctx) {
+		t.Error("DebugErrorsFromContext() = true, want false")
+	}
+	if !errors.DebugErrorsFromContext(errors.WithDebugErrors(ctx)) {
+		t.Error("DebugErrorsFromContext() = false, want true")
+	}
+}
@@ -0,0 +1,204 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package errors
+
+import (
+	"fmt"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// DecodedStatus 是 DetailDecoder 从 gRPC status details 中解析出的业务错误信息
+type DecodedStatus struct {
+	// Code 是业务错误码，0 表示还没有解析出业务错误码
+	Code int32
+	// Message 是业务消息，为空时使用 status 的 message
+	Message string
+	// Reason 是错误原因，见 CodeDefinition.Reason
+	Reason string
+	// Extra 是扩展信息
+	Extra map[string]string
+
+	localized  string
+	retryDelay time.Duration
+	attached   []proto.Message
+	// retryable 与 affectStability 只在规范编码中出现，见 DetailEncodingCanonical
+	retryable       *bool
+	affectStability *bool
+}
+
+// AddExtra 添加扩展信息
+func (d *DecodedStatus) AddExtra(k, v string) {
+	if d.Extra == nil {
+		d.Extra = make(map[string]string)
+	}
+	d.Extra[k] = v
+}
+
+// DetailDecoder 从 gRPC status 的一个 detail 中解析业务错误信息，识别了该 detail 时返回 true
+// FromGRPCStatus 对每个 detail 依次尝试注册的解码器与内置解码器，直到某个解码器返回 true，
+// 都无法识别的 detail 作为附加的 proto 消息保留（见 Detail）.
+type DetailDecoder func(detail proto.Message, d *DecodedStatus) bool
+
+var (
+	detailDecodersMu sync.Mutex
+	// detailDecoders 只在注册时整体替换，读取时无需加锁
+	detailDecoders atomic.Pointer[[]DetailDecoder]
+)
+
+// builtinDetailDecoders 是内置的解码器，兼容本包各版本的编码
+var builtinDetailDecoders = []DetailDecoder{
+	decodeErrorInfo,
+	decodeRetryInfo,
+	decodeQuotaFailure,
+	decodeLocalizedMessage,
+	decodeStruct,
+}
+
+// RegisterDetailDecoder 注册 DetailDecoder，用于兼容内部其他错误库使用的 details 格式
+// 注册的解码器先于内置解码器执行，滚动升级期间混合版本的服务之间传递的错误不会退化为 CodeInternalError.
+//
+//	errors.RegisterDetailDecoder(func(detail proto.Message, d *errors.DecodedStatus) bool {
+//		e, ok := detail.(*legacypb.Error)
+//		if !ok {
+//			return false
+//		}
+//		d.Code, d.Message = e.GetErrCode(), e.GetErrMsg()
+//		return true
+//	})
+func RegisterDetailDecoder(dec DetailDecoder) {
+	if dec == nil {
+		return
+	}
+	detailDecodersMu.Lock()
+	defer detailDecodersMu.Unlock()
+	var list []DetailDecoder
+	if old := detailDecoders.Load(); old != nil {
+		list = append(list, *old...)
+	}
+	list = append(list, dec)
+	detailDecoders.Store(&list)
+}
+
+// decodeDetail 依次使用注册的解码器与内置解码器解析 detail
+func decodeDetail(detail proto.Message, d *DecodedStatus) {
+	if list := detailDecoders.Load(); list != nil {
+		for _, dec := range *list {
+			if dec(detail, d) {
+				return
+			}
+		}
+	}
+	for _, dec := range builtinDetailDecoders {
+		if dec(detail, d) {
+			return
+		}
+	}
+	// 通过 Detail 附加的 proto 消息
+	d.attached = append(d.attached, detail)
+}
+
+// decodeErrorInfo 解析二进制编码，见 DetailEncodingBinary
+func decodeErrorInfo(detail proto.Message, d *DecodedStatus) bool {
+	info, ok := detail.(*errdetails.ErrorInfo)
+	if !ok || info.GetDomain() != ErrorInfoDomain {
+		return false
+	}
+	if bizCode, err := strconv.ParseInt(info.GetReason(), 10, 32); err == nil && bizCode > 0 {
+		d.Code = int32(bizCode)
+	} else if bizCode, err := strconv.ParseInt(info.GetMetadata()[errorInfoCodeKey], 10, 32); err == nil && bizCode > 0 {
+		d.Code = int32(bizCode)
+		d.Reason = info.GetReason()
+	}
+	for k, v := range info.GetMetadata() {
+		if k != errorInfoCodeKey {
+			d.AddExtra(k, v)
+		}
+	}
+	return true
+}
+
+// decodeRetryInfo 解析建议的重试等待时间
+func decodeRetryInfo(detail proto.Message, d *DecodedStatus) bool {
+	ri, ok := detail.(*errdetails.RetryInfo)
+	if !ok {
+		return false
+	}
+	d.retryDelay = ri.GetRetryDelay().AsDuration()
+	return true
+}
+
+// decodeLocalizedMessage 解析服务端拦截器附加的本地化消息，见 UnaryServerLocaleInterceptor
+func decodeLocalizedMessage(detail proto.Message, d *DecodedStatus) bool {
+	lm, ok := detail.(*errdetails.LocalizedMessage)
+	if !ok {
+		return false
+	}
+	d.localized = lm.GetMessage()
+	return true
+}
+
+// decodeStruct 解析 structpb 编码，包括扩展信息放在单独 detail 中的旧版本编码
+func decodeStruct(detail proto.Message, d *DecodedStatus) bool {
+	anyValue, ok := detail.(*anypb.Any)
+	if !ok {
+		return false
+	}
+	var structValue structpb.Struct
+	if err := anyValue.UnmarshalTo(&structValue); err != nil {
+		return true
+	}
+	structMap := structValue.AsMap()
+
+	// 检查是否是业务错误信息
+	bizCode, ok := structMap["business_code"].(float64)
+	if !ok {
+		// 兼容旧版本：扩展信息放在单独的 detail 中
+		for k, v := range structMap {
+			d.AddExtra(k, fmt.Sprintf("%v", v))
+		}
+		return true
+	}
+	if validBusinessCode(bizCode) {
+		d.Code = int32(bizCode)
+	}
+	if bizMsg, ok := structMap["business_msg"].(string); ok {
+		d.Message = bizMsg
+	}
+	if bizReason, ok := structMap["reason"].(string); ok {
+		d.Reason = bizReason
+	}
+	if extra, ok := structMap["extra"].(map[string]interface{}); ok {
+		for k, v := range extra {
+			d.AddExtra(k, fmt.Sprintf("%v", v))
+		}
+	}
+	if retryable, ok := structMap["retryable"].(bool); ok {
+		d.retryable = &retryable
+	}
+	if affectStability, ok := structMap["affect_stability"].(bool); ok {
+		d.affectStability = &affectStability
+	}
+	return true
+}
@@ -0,0 +1,85 @@
+package errors_test
+
+import (
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	"github.com/go-anyway/framework-errors"
+)
+
+// anyStruct 将 m 编码为 Any 包装的 structpb.Struct
+func anyStruct(t *testing.T, m map[string]interface{}) *anypb.Any {
+	t.Helper()
+	s, err := structpb.NewStruct(m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	a, err := anypb.New(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return a
+}
+
+func TestFromGRPCStatusLegacyFormat(t *testing.T) {
+	// 最早的版本将扩展信息与业务错误码放在两个 detail 中
+	st, err := status.New(codes.NotFound, "订单不存在").WithDetails(
+		anyStruct(t, map[string]interface{}{"order_id": "42"}),
+		anyStruct(t, map[string]interface{}{"business_code": 2001, "business_msg": "用户不存在"}),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := errors.FromGRPCStatus(st)
+	if got.Code() != errors.CodeUserNotFound || got.Msg() != "用户不存在" || got.Extra()["order_id"] != "42" {
+		t.Errorf("FromGRPCStatus() = %d %q %v", got.Code(), got.Msg(), got.Extra())
+	}
+}
+
+func TestRegisterDetailDecoder(t *testing.T) {
+	defer errors.ResetDetailDecoders()
+	// 内部旧错误库使用 err_code/err_msg 字段
+	errors.RegisterDetailDecoder(func(detail proto.Message, d *errors.DecodedStatus) bool {
+		a, ok := detail.(*anypb.Any)
+		if !ok {
+			return false
+		}
+		var s structpb.Struct
+		if a.UnmarshalTo(&s) != nil {
+			return false
+		}
+		code, ok := s.AsMap()["err_code"].(float64)
+		if !ok {
+			return false
+		}
+		d.Code = int32(code)
+		d.Message, _ = s.AsMap()["err_msg"].(string)
+		d.AddExtra("legacy", "true")
+		return true
+	})
+
+	st, err := status.New(codes.Unknown, "failed").WithDetails(
+		anyStruct(t, map[string]interface{}{"err_code": 1004, "err_msg": "订单不存在"}),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := errors.FromGRPCStatus(st)
+	if got.Code() != errors.CodeNotFound || got.Msg() != "订单不存在" || got.Extra()["legacy"] != "true" {
+		t.Errorf("FromGRPCStatus() = %d %q %v", got.Code(), got.Msg(), got.Extra())
+	}
+	if _, ok := got.Extra()["err_code"]; ok {
+		t.Errorf("Extra() = %v, 已识别的 detail 不应被内置解码器当作扩展信息", got.Extra())
+	}
+
+	// 当前版本的编码不受影响
+	se := errors.NewWithStatus(errors.CodeConflict, "", errors.Extra("id", "7"))
+	if got := errors.FromGRPCStatus(errors.ToGRPCStatus(se)); got.Code() != errors.CodeConflict || got.Extra()["id"] != "7" {
+		t.Errorf("FromGRPCStatus() = %d %v", got.Code(), got.Extra())
+	}
+}
@@ -0,0 +1,60 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package errors
+
+import "errors"
+
+// DependencyKey 是依赖服务名称在日志中的字段名
+const DependencyKey = "dependency"
+
+// maxDependencyLen 是依赖服务名称的最大长度，超出部分被截断，避免指标标签的基数失控
+const maxDependencyLen = 64
+
+// Dependency 用于记录导致错误的依赖服务，例如 "mysql-orders"、"payment-api"
+// 依赖服务记录到日志中，MetricsSink 实现了 DependencyMetricsSink 时按依赖服务计数，
+// 监控面板可以直接回答 "哪个下游导致了 5xx"，无需检索错误消息.
+// 依赖服务只属于本服务，不会跨服务传递，上游的依赖服务不会被计入本服务的指标.
+// name 应为固定的服务名，不能包含请求相关的内容，超过 64 字节的部分被截断.
+//
+//	return errors.WrapWithStatusOptions(err, errors.CodeDependencyTimeout, "", errors.Dependency("payment-api"))
+func Dependency(name string) Option {
+	return func(ws *withStatus) {
+		if ws == nil || ws.status == nil || name == "" {
+			return
+		}
+		ws.status.dependency = truncateString(name, maxDependencyLen)
+	}
+}
+
+// Dependency 返回导致错误的依赖服务，没有时返回空字符串
+func (e *statusError) Dependency() string {
+	return e.dependency
+}
+
+// Dependency 返回导致错误的依赖服务，没有时返回空字符串
+func (w *withStatus) Dependency() string {
+	return w.status.Dependency()
+}
+
+// DependencyOf 返回 err 记录的依赖服务，没有时返回空字符串
+func DependencyOf(err error) string {
+	var d interface{ Dependency() string }
+	if errors.As(err, &d) {
+		return d.Dependency()
+	}
+	return ""
+}
@@ -0,0 +1,70 @@
+package errors_test
+
+import (
+	"context"
+	errstd "errors"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/go-anyway/framework-errors"
+)
+
+// fakeDependencySink 额外记录按依赖服务统计的错误
+type fakeDependencySink struct {
+	*fakeSink
+	mu           sync.Mutex
+	dependencies map[string]int32
+}
+
+func (s *fakeDependencySink) IncrementDependency(dependency string, code int32) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.dependencies[dependency] = code
+}
+
+func TestDependency(t *testing.T) {
+	err := errors.WrapWithStatusOptions(errstd.New("dial tcp: i/o timeout"), errors.CodeDependencyTimeout, "",
+		errors.Dependency("payment-api"))
+
+	tests := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{name: "本地错误", err: err, want: "payment-api"},
+		{name: "gRPC 往返不传递依赖服务", err: errors.FromGRPCStatus(errors.ToGRPCStatus(err)), want: ""},
+		{name: "上游扩展信息中的同名 key", err: errors.FromGRPCStatus(errors.ToGRPCStatus(
+			errors.NewWithStatus(errors.CodeDependencyTimeout, "", errors.Extra(errors.DependencyKey, "spoofed")))), want: ""},
+		{name: "With 保留依赖服务", err: errors.With(err, errors.Extra("k", "v")), want: "payment-api"},
+		{name: "超长的名称被截断", err: errors.NewWithStatus(errors.CodeDependencyTimeout, "", errors.Dependency(strings.Repeat("x", 100))),
+			want: strings.Repeat("x", 64)},
+		{name: "没有依赖服务", err: errors.NewWithStatus(errors.CodeDependencyTimeout, "", errors.Dependency("")), want: ""},
+		{name: "普通错误", err: errstd.New("boom"), want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := errors.DependencyOf(tt.err); got != tt.want {
+				t.Errorf("DependencyOf() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	if !errors.IsCategory(errors.NewWithStatus(errors.CodeDependencyCircuitOpen, ""), errors.CategoryDependency) {
+		t.Error("CodeDependencyCircuitOpen 应属于 CategoryDependency")
+	}
+}
+
+func TestDependencyMetrics(t *testing.T) {
+	sink := &fakeDependencySink{fakeSink: newFakeSink(), dependencies: map[string]int32{}}
+	errors.SetMetricsSink(sink)
+	defer errors.SetMetricsSink(nil)
+
+	_ = errors.LogAndReturnError(context.Background(),
+		errors.NewWithStatus(errors.CodeDependencyRejected, "", errors.Dependency("inventory")), errors.SkipLog())
+	_ = errors.LogAndReturnError(context.Background(), errors.NewWithStatus(errors.CodeNotFound, ""), errors.SkipLog())
+
+	if len(sink.dependencies) != 1 || sink.dependencies["inventory"] != errors.CodeDependencyRejected {
+		t.Errorf("dependencies = %v", sink.dependencies)
+	}
+}
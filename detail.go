@@ -0,0 +1,98 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package errors
+
+import (
+	"errors"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
+)
+
+// Detail 用于向错误附加任意 proto 消息，例如 RefundDenialReason
+// 附加的消息会通过 ToGRPCStatus 作为 status details 传递，并由 FromGRPCStatus 还原.
+// 接收方需要导入消息所在的 Go 包，使其类型注册到全局 protobuf registry 中.
+func Detail(msg proto.Message) Option {
+	return func(ws *withStatus) {
+		if ws == nil || ws.status == nil || msg == nil {
+			return
+		}
+		ws.status.details = append(ws.status.details, msg)
+	}
+}
+
+// AttachDetail 返回附加了 msg 的新错误，err 本身不会被修改
+//
+//	err = errors.AttachDetail(err, &refundpb.RefundDenialReason{Reason: "expired"})
+func AttachDetail[T proto.Message](err StatusError, msg T) StatusError {
+	return With(err, Detail(msg))
+}
+
+// DetailOf 获取错误中第一个类型为 T 的 proto 消息
+//
+//	if reason, ok := errors.DetailOf[*refundpb.RefundDenialReason](err); ok {
+//		...
+//	}
+func DetailOf[T proto.Message](err error) (T, bool) {
+	for _, d := range detailsOf(err) {
+		if msg, ok := d.(T); ok {
+			return msg, true
+		}
+	}
+	var zero T
+	return zero, false
+}
+
+// Details 以 Any 的形式返回错误中附加的全部 proto 消息
+// 包括 FromGRPCStatus 无法识别类型的 detail（例如未导入的第三方 errdetails），
+// 它们保留原始的类型 URL 与字节，ToGRPCStatus 会原样转发，网关无需理解其内容即可透传.
+func Details(err error) []*anypb.Any {
+	details := detailsOf(err)
+	if len(details) == 0 {
+		return nil
+	}
+	result := make([]*anypb.Any, 0, len(details))
+	for _, d := range details {
+		if raw, ok := d.(*anypb.Any); ok {
+			result = append(result, raw)
+			continue
+		}
+		if anyValue, err := marshalAny(d); err == nil {
+			result = append(result, anyValue)
+		}
+	}
+	return result
+}
+
+// detailsOf 获取错误链中附加的 proto 消息
+func detailsOf(err error) []proto.Message {
+	var d interface{ protoDetails() []proto.Message }
+	if errors.As(err, &d) {
+		return d.protoDetails()
+	}
+	return nil
+}
+
+// protoDetails 返回附加的 proto 消息
+func (e *statusError) protoDetails() []proto.Message {
+	return e.details
+}
+
+// protoDetails 返回附加的 proto 消息
+func (w *withStatus) protoDetails() []proto.Message {
+	return w.status.details
+}
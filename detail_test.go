@@ -0,0 +1,87 @@
+package errors_test
+
+import (
+	"testing"
+
+	"github.com/go-anyway/framework-errors"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+func TestAttachDetail(t *testing.T) {
+	base := errors.NewStatusError(errors.CodeForbidden, "退款被拒绝", map[string]string{"order_id": "42"})
+	reason := &errdetails.QuotaFailure{Violations: []*errdetails.QuotaFailure_Violation{{Subject: "refund", Description: "expired"}}}
+	err := errors.AttachDetail(base, reason)
+	err = errors.AttachDetail(err, wrapperspb.String("note"))
+
+	if _, ok := errors.DetailOf[*errdetails.QuotaFailure](base); ok {
+		t.Error("AttachDetail 不应修改原错误")
+	}
+
+	got, ok := errors.DetailOf[*errdetails.QuotaFailure](err)
+	if !ok || !proto.Equal(got, reason) {
+		t.Fatalf("DetailOf() = %v, %v", got, ok)
+	}
+
+	// 经过 gRPC status 传递后无损还原
+	for _, enc := range []errors.DetailEncoding{errors.DetailEncodingStruct, errors.DetailEncodingBinary} {
+		errors.SetDetailEncoding(enc)
+		decoded := errors.FromGRPCStatus(errors.ToGRPCStatus(err))
+		if decoded.Code() != errors.CodeForbidden || decoded.Extra()["order_id"] != "42" {
+			t.Errorf("FromGRPCStatus() = %d %v", decoded.Code(), decoded.Extra())
+		}
+		got, ok := errors.DetailOf[*errdetails.QuotaFailure](decoded)
+		if !ok || !proto.Equal(got, reason) {
+			t.Errorf("DetailOf() = %v, %v, want %v", got, ok, reason)
+		}
+		note, ok := errors.DetailOf[*wrapperspb.StringValue](decoded)
+		if !ok || note.GetValue() != "note" {
+			t.Errorf("DetailOf[*wrapperspb.StringValue]() = %v, %v", note, ok)
+		}
+	}
+	errors.SetDetailEncoding(errors.DetailEncodingStruct)
+
+	if _, ok := errors.DetailOf[*errdetails.BadRequest](err); ok {
+		t.Error("不存在的类型应返回 false")
+	}
+}
+
+func TestUnknownDetailPreserved(t *testing.T) {
+	// 接收方没有导入的第三方 detail 类型
+	unknown := &anypb.Any{TypeUrl: "type.googleapis.com/thirdparty.v1.Violation", Value: []byte{0x0a, 0x03, 'f', 'o', 'o'}}
+	pb := errors.ToGRPCStatus(errors.NewWithStatus(errors.CodeForbidden, "")).Proto()
+	pb.Details = append(pb.Details, unknown)
+
+	decoded := errors.FromGRPCStatus(status.FromProto(pb))
+	if decoded.Code() != errors.CodeForbidden {
+		t.Fatalf("FromGRPCStatus().Code() = %d, want %d", decoded.Code(), errors.CodeForbidden)
+	}
+	details := errors.Details(decoded)
+	if len(details) != 1 || !proto.Equal(details[0], unknown) {
+		t.Fatalf("Details() = %v, want [%v]", details, unknown)
+	}
+
+	// 网关转发时原样保留
+	forwarded := errors.ToGRPCStatus(decoded).Proto().GetDetails()
+	found := false
+	for _, d := range forwarded {
+		found = found || proto.Equal(d, unknown)
+	}
+	if !found {
+		t.Errorf("ToGRPCStatus().Details = %v, 应原样包含 %v", forwarded, unknown)
+	}
+}
+
+func TestDetailsKnownTypes(t *testing.T) {
+	err := errors.AttachDetail(errors.NewWithStatus(errors.CodeForbidden, ""), wrapperspb.String("note"))
+	details := errors.Details(err)
+	if len(details) != 1 || details[0].GetTypeUrl() != "type.googleapis.com/google.protobuf.StringValue" {
+		t.Errorf("Details() = %v", details)
+	}
+	if got := errors.Details(errors.NewWithStatus(errors.CodeForbidden, "")); got != nil {
+		t.Errorf("Details() = %v, want nil", got)
+	}
+}
@@ -0,0 +1,109 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package errors
+
+import (
+	"errors"
+	"strings"
+)
+
+// volatileExtraKeys 是 Equal 默认忽略的扩展信息，它们随每次发生而变化
+var volatileExtraKeys = map[string]bool{
+	"stack":           true,
+	"timestamp":       true,
+	"time":            true,
+	"retry_attempts":  true,
+	ExtraTruncatedKey: true,
+	RefIDKey:          true,
+	TraceIDKey:        true,
+	RequestIDKey:      true,
+}
+
+// Equal 判断两个错误在语义上是否相同，用于去重和幂等重试检测
+// 比较错误码、规范化后的消息模板（应用 Param 之前的消息，忽略多余空白）和扩展信息.
+// 指定 keys 时只比较这些扩展信息，否则比较除堆栈、时间戳、重试次数、参考编号与链路 ID 等易变信息以外的全部扩展信息.
+// 两个错误都不是 StatusError 时比较 Error() 的结果.
+//
+//	errors.Equal(err1, err2, "order_id")
+func Equal(a, b error, keys ...string) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+
+	var sa, sb StatusError
+	okA, okB := errors.As(a, &sa), errors.As(b, &sb)
+	if !okA || !okB {
+		return !okA && !okB && a.Error() == b.Error()
+	}
+
+	if sa.Code() != sb.Code() || Reason(sa) != Reason(sb) {
+		return false
+	}
+	if normalizeMessage(messageTemplate(sa)) != normalizeMessage(messageTemplate(sb)) {
+		return false
+	}
+
+	extraA, extraB := comparableExtra(sa, keys), comparableExtra(sb, keys)
+	if len(extraA) != len(extraB) {
+		return false
+	}
+	for k, v := range extraA {
+		if w, ok := extraB[k]; !ok || v != w {
+			return false
+		}
+	}
+	return true
+}
+
+// messageTemplate 返回错误的消息模板，没有模板时返回 Msg()
+func messageTemplate(se StatusError) string {
+	var ws *withStatus
+	if errors.As(se, &ws) && ws.status.template != "" {
+		return ws.status.template
+	}
+	var s *statusError
+	if errors.As(se, &s) && s.template != "" {
+		return s.template
+	}
+	return se.Msg()
+}
+
+// normalizeMessage 去掉首尾空白并将连续的空白合并为一个空格
+func normalizeMessage(msg string) string {
+	return strings.Join(strings.Fields(msg), " ")
+}
+
+// comparableExtra 返回参与比较的扩展信息
+func comparableExtra(se StatusError, keys []string) map[string]string {
+	extra := make(map[string]string)
+	if len(keys) > 0 {
+		all := se.Extra()
+		for _, k := range keys {
+			if v, ok := all[k]; ok {
+				extra[k] = v
+			}
+		}
+		return extra
+	}
+	RangeExtra(se, func(k, v string) bool {
+		if !volatileExtraKeys[k] {
+			extra[k] = v
+		}
+		return true
+	})
+	return extra
+}
@@ -0,0 +1,78 @@
+package errors_test
+
+import (
+	errstd "errors"
+	"testing"
+
+	"github.com/go-anyway/framework-errors"
+)
+
+func TestEqual(t *testing.T) {
+	const tmpl = "用户 {name} 不存在"
+	tests := []struct {
+		name string
+		a, b error
+		keys []string
+		want bool
+	}{
+		{name: "都为 nil", want: true},
+		{name: "一个为 nil", a: errstd.New("x"), want: false},
+		{
+			name: "Param 不同但模板相同",
+			a:    errors.NewWithStatus(errors.CodeUserNotFound, tmpl, errors.Param("name", "a")),
+			b:    errors.NewWithStatus(errors.CodeUserNotFound, tmpl, errors.Param("name", "b")),
+			want: true,
+		},
+		{
+			name: "忽略堆栈与空白差异",
+			a:    errors.NewWithStatus(errors.CodeNotFound, "订单  不存在"),
+			b:    errors.NewStatusError(errors.CodeNotFound, " 订单 不存在", map[string]string{"retry_attempts": "3"}),
+			want: true,
+		},
+		{
+			name: "忽略参考编号与链路信息",
+			a:    errors.EnsureRefID(errors.NewWithStatus(errors.CodeInternalError, "", errors.Extra(errors.TraceIDKey, "t1"), errors.Extra(errors.RequestIDKey, "r1"))),
+			b:    errors.NewWithStatus(errors.CodeInternalError, ""),
+			want: true,
+		},
+		{
+			name: "错误码不同",
+			a:    errors.NewStatusError(errors.CodeNotFound, "", nil),
+			b:    errors.NewStatusError(errors.CodeUserNotFound, errors.GetMessage(errors.CodeNotFound, ""), nil),
+			want: false,
+		},
+		{
+			name: "扩展信息不同",
+			a:    errors.NewStatusError(errors.CodeNotFound, "", map[string]string{"order_id": "1"}),
+			b:    errors.NewStatusError(errors.CodeNotFound, "", map[string]string{"order_id": "2"}),
+			want: false,
+		},
+		{
+			name: "只比较指定的扩展信息",
+			a:    errors.NewStatusError(errors.CodeNotFound, "", map[string]string{"order_id": "1", "trace_id": "t1"}),
+			b:    errors.NewStatusError(errors.CodeNotFound, "", map[string]string{"order_id": "1", "trace_id": "t2"}),
+			keys: []string{"order_id"},
+			want: true,
+		},
+		{
+			name: "普通错误比较 Error()",
+			a:    errstd.New("boom"),
+			b:    errstd.New("boom"),
+			want: true,
+		},
+		{
+			name: "StatusError 与普通错误",
+			a:    errors.NewStatusError(errors.CodeNotFound, "", nil),
+			b:    errstd.New(errors.GetMessage(errors.CodeNotFound, "")),
+			want: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := errors.Equal(tt.a, tt.b, tt.keys...); got != tt.want {
+				t.Errorf("Equal() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -1,12 +1,15 @@
 package errors
 
 import (
+	"errors"
 	"fmt"
+	"strconv"
 
+	spb "google.golang.org/genproto/googleapis/rpc/status"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/types/known/anypb"
-	"google.golang.org/protobuf/types/known/structpb"
 )
 
 // StatusError 状态错误接口
@@ -21,6 +24,7 @@ type StatusError interface {
 // Extension 包含了错误的扩展信息
 type Extension struct {
 	IsAffectStability bool
+	Retryable         bool
 	Extra             map[string]string
 }
 
@@ -29,6 +33,19 @@ type statusError struct {
 	statusCode int32
 	message    string
 	ext        Extension
+	// internal 记录通过 InternalExtra 添加、只用于日志的扩展信息 key
+	internal map[string]bool
+	// template 是应用 Param 之前的消息模板，用于 Equal 比较
+	template string
+	// details 是通过 Detail 附加的 proto 消息
+	details []proto.Message
+	// userID 与 tenantID 是通过 UserID、TenantID 记录的身份标识，不会跨服务传递
+	userID   string
+	tenantID string
+	// dependency 是通过 Dependency 记录的依赖服务，不会跨服务传递
+	dependency string
+	// reason 是收到的与本地错误码定义不一致的错误原因，见 Reason
+	reason string
 }
 
 // GetCodeDefinition 获取错误码定义，如果不存在则返回默认定义
@@ -36,6 +53,11 @@ func GetCodeDefinition(code int32) CodeDefinition {
 	if def, ok := CodeDefinitions[code]; ok {
 		return def
 	}
+	if base, ok := baseCode(code); ok {
+		if def, ok := CodeDefinitions[base]; ok {
+			return def
+		}
+	}
 	// 返回默认定义
 	return CodeDefinition{
 		Message:           "未知错误",
@@ -48,7 +70,7 @@ func GetMessage(code int32, customMessage string) string {
 	if customMessage != "" {
 		return customMessage
 	}
-	return GetCodeDefinition(code).Message
+	return defaultMessage(code)
 }
 
 // Error 实现 error 接口
@@ -61,9 +83,25 @@ func (e *statusError) Code() int32 {
 	return e.statusCode
 }
 
-// IsAffectStability 返回是否影响系统稳定性
+// IsAffectStability 返回是否影响系统稳定性，错误码处于维护窗口内时返回 false，见 SetMaintenanceWindow
 func (e *statusError) IsAffectStability() bool {
-	return e.ext.IsAffectStability
+	return e.ext.IsAffectStability && !inMaintenance(e.statusCode)
+}
+
+// IsRetryable 返回是否可以重试
+func (e *statusError) IsRetryable() bool {
+	return e.ext.Retryable
+}
+
+// Timeout 实现 net.Error 接口，错误码定义为超时类错误时返回 true
+func (e *statusError) Timeout() bool {
+	return GetCodeDefinition(e.statusCode).Timeout
+}
+
+// Temporary 实现 net.Error 接口，与 IsRetryable 一致
+// 便于已有的重试逻辑和 HTTP 客户端识别可重试的错误.
+func (e *statusError) Temporary() bool {
+	return e.ext.Retryable
 }
 
 // Msg 返回错误消息
@@ -71,17 +109,78 @@ func (e *statusError) Msg() string {
 	return e.message
 }
 
-// Extra 返回扩展信息
+// Extra 返回扩展信息的副本
+// 错误可能被多个 goroutine 共享，修改返回的 map 不会影响错误本身.
 func (e *statusError) Extra() map[string]string {
-	if e.ext.Extra == nil {
-		return make(map[string]string)
+	extra := make(map[string]string, len(e.ext.Extra))
+	for k, v := range e.ext.Extra {
+		extra[k] = v
+	}
+	return extra
+}
+
+// clone 复制 statusError，包括扩展信息
+func (e *statusError) clone() *statusError {
+	c := *e
+	if len(e.ext.Extra) > 0 {
+		c.ext.Extra = make(map[string]string, len(e.ext.Extra))
+		for k, v := range e.ext.Extra {
+			c.ext.Extra[k] = v
+		}
+	} else {
+		c.ext.Extra = nil
+	}
+	if len(e.internal) > 0 {
+		c.internal = make(map[string]bool, len(e.internal))
+		for k := range e.internal {
+			c.internal[k] = true
+		}
 	}
-	return e.ext.Extra
+	c.details = append([]proto.Message(nil), e.details...)
+	return &c
+}
+
+// RangeExtra 依次对每个扩展信息调用 fn，fn 返回 false 时停止遍历
+func (e *statusError) RangeExtra(fn func(k, v string) bool) {
+	for k, v := range e.ext.Extra {
+		if !fn(k, v) {
+			return
+		}
+	}
+}
+
+// RangeExtra 依次对 err 的每个扩展信息调用 fn，fn 返回 false 时停止遍历
+// 与 Extra() 不同，遍历不会复制扩展信息，适用于日志、序列化等热路径.
+// err 没有实现 RangeExtra 方法时回退到遍历 Extra().
+func RangeExtra(err StatusError, fn func(k, v string) bool) {
+	if err == nil {
+		return
+	}
+	if r, ok := err.(interface{ RangeExtra(func(k, v string) bool) }); ok {
+		r.RangeExtra(fn)
+		return
+	}
+	for k, v := range err.Extra() {
+		if !fn(k, v) {
+			return
+		}
+	}
+}
+
+// hasExtra 判断 err 是否包含扩展信息
+func hasExtra(err StatusError) bool {
+	found := false
+	RangeExtra(err, func(string, string) bool {
+		found = true
+		return false
+	})
+	return found
 }
 
 // NewStatusError 创建状态错误
 // 如果 message 为空，则使用 CodeDefinitions 中定义的默认消息
 func NewStatusError(code int32, message string, data interface{}) StatusError {
+	code = CanonicalCode(code)
 	if message == "" {
 		message = GetMessage(code, "")
 	}
@@ -89,131 +188,152 @@ func NewStatusError(code int32, message string, data interface{}) StatusError {
 	// 获取错误码定义
 	def := GetCodeDefinition(code)
 
-	// 转换 data 为 map[string]string
-	extra := make(map[string]string)
+	// 转换 data 为 map[string]string，没有扩展信息时不分配
+	var extra map[string]string
 	if data != nil {
-		if dataMap, ok := data.(map[string]interface{}); ok {
+		if dataMap, ok := data.(map[string]interface{}); ok && len(dataMap) > 0 {
+			extra = make(map[string]string, len(dataMap))
 			for k, v := range dataMap {
 				extra[k] = fmt.Sprintf("%v", v)
 			}
-		} else if dataMap, ok := data.(map[string]string); ok {
-			extra = dataMap
+		} else if dataMap, ok := data.(map[string]string); ok && len(dataMap) > 0 {
+			// 复制一份，避免调用方后续修改 dataMap 影响错误
+			extra = make(map[string]string, len(dataMap))
+			for k, v := range dataMap {
+				extra[k] = v
+			}
 		}
 	}
 
 	return &statusError{
 		statusCode: code,
 		message:    message,
+		template:   message,
 		ext: Extension{
 			IsAffectStability: def.IsAffectStability,
+			Retryable:         def.Retryable,
 			Extra:             extra,
 		},
 	}
 }
 
+// IsRetryable 判断 err 是否可以重试
+// err 链中任意一个错误实现了 IsRetryable() bool 即以其结果为准，否则返回 false.
+func IsRetryable(err error) bool {
+	var r interface{ IsRetryable() bool }
+	if errors.As(err, &r) {
+		return r.IsRetryable()
+	}
+	return false
+}
+
 // ToGRPCStatus 将 StatusError 转换为 gRPC status，使用 details 传递状态错误信息
 func ToGRPCStatus(err StatusError) *status.Status {
 	if err == nil {
 		return status.New(codes.Internal, "unknown error")
 	}
 
+	// 没有扩展信息、附加消息且使用默认消息的错误复用缓存的 status
+	details := detailsOf(err)
+	cacheable := !hasExtra(err) && len(details) == 0 && isDefaultMessage(err.Code(), err.Msg())
+	if cacheable {
+		if st, ok := cachedGRPCStatus(statusCacheKey(err)); ok {
+			return st
+		}
+	}
+
 	// 根据业务错误码映射到 gRPC codes
-	var grpcCode codes.Code
-	switch err.Code() {
-	case CodeInvalidParam:
-		grpcCode = codes.InvalidArgument
-	case CodeUnauthorized:
-		grpcCode = codes.Unauthenticated
-	case CodeForbidden:
-		grpcCode = codes.PermissionDenied
-	case CodeNotFound:
-		grpcCode = codes.NotFound
-	case CodeAlreadyExists:
-		grpcCode = codes.AlreadyExists
-	default:
-		grpcCode = codes.Internal
-	}
-
-	// 创建包含业务错误信息的 struct
-	st := status.New(grpcCode, err.Msg())
-
-	// 将扩展信息放入 details
-	extra := err.Extra()
-	if len(extra) > 0 {
-		// 转换为 map[string]interface{} 以便使用 structpb
-		extraMap := make(map[string]interface{})
-		for k, v := range extra {
-			extraMap[k] = v
+	grpcCode := GRPCCode(err.Code())
+
+	// 创建包含业务错误信息的 status，details 使用确定性序列化，相同的错误得到相同的字节
+	pb := &spb.Status{Code: int32(grpcCode), Message: ScrubbedMsg(err)}
+
+	// 将业务错误码、消息与扩展信息放入同一个 detail
+	if detail := encodeDetailWithinBudget(err); detail != nil {
+		if anyValue, err := marshalAny(detail); err == nil {
+			pb.Details = append(pb.Details, anyValue)
 		}
-		if structValue, err := structpb.NewStruct(extraMap); err == nil {
-			anyValue, _ := anypb.New(structValue)
-			st, _ = st.WithDetails(anyValue)
+	}
+
+	// 建议的重试等待时间
+	if retryInfo := retryInfoDetail(err); retryInfo != nil {
+		if anyValue, err := marshalAny(retryInfo); err == nil {
+			pb.Details = append(pb.Details, anyValue)
 		}
 	}
 
-	// 将业务错误码也放入 details（使用自定义字段）
-	errorInfo := map[string]interface{}{
-		"business_code": err.Code(),
-		"business_msg":  err.Msg(),
+	// 限流配额
+	if quota := quotaFailureDetail(err); quota != nil {
+		if anyValue, err := marshalAny(quota); err == nil {
+			pb.Details = append(pb.Details, anyValue)
+		}
 	}
-	if structValue, err := structpb.NewStruct(errorInfo); err == nil {
-		anyValue, _ := anypb.New(structValue)
-		st, _ = st.WithDetails(anyValue)
+
+	// 附加的 proto 消息，无法识别类型的 detail 原样转发
+	for _, d := range details {
+		if raw, ok := d.(*anypb.Any); ok {
+			pb.Details = append(pb.Details, raw)
+			continue
+		}
+		if anyValue, err := marshalAny(d); err == nil {
+			pb.Details = append(pb.Details, anyValue)
+		}
 	}
+	st := status.FromProto(pb)
 
+	if cacheable {
+		storeGRPCStatus(statusCacheKey(err), st)
+	}
 	return st
 }
 
 // FromGRPCStatus 从 gRPC status 解析状态错误
+// details 依次经过注册的 DetailDecoder 与内置解码器解析，见 RegisterDetailDecoder.
 func FromGRPCStatus(st *status.Status) StatusError {
-	code := CodeInternalError
-	message := st.Message()
-	var extraData map[string]string
-
-	// 从 details 中提取业务错误信息
-	details := st.Details()
-	for _, detail := range details {
-		if anyValue, ok := detail.(*anypb.Any); ok {
-			var structValue structpb.Struct
-			if err := anyValue.UnmarshalTo(&structValue); err == nil {
-				structMap := structValue.AsMap()
-
-				// 检查是否是业务错误信息
-				if bizCode, ok := structMap["business_code"].(float64); ok {
-					code = int32(bizCode)
-					if bizMsg, ok := structMap["business_msg"].(string); ok {
-						message = bizMsg
-					}
-				} else {
-					// 否则作为扩展数据
-					if extraData == nil {
-						extraData = make(map[string]string)
-					}
-					for k, v := range structMap {
-						extraData[k] = fmt.Sprintf("%v", v)
-					}
-				}
-			}
+	// 从 details 中提取业务错误信息，details 来自调用方，视为不可信输入
+	var d DecodedStatus
+	rawDetails := st.Proto().GetDetails()
+	if len(rawDetails) > maxIncomingDetails {
+		rawDetails = rawDetails[:maxIncomingDetails]
+	}
+	for _, raw := range rawDetails {
+		detail, err := raw.UnmarshalNew()
+		if err != nil {
+			// 类型没有注册到 protobuf registry，保留原始的类型 URL 与字节，见 Details
+			d.attached = append(d.attached, &anypb.Any{TypeUrl: raw.GetTypeUrl(), Value: raw.GetValue()})
+			continue
 		}
+		decodeDetail(detail, &d)
+	}
+
+	message := st.Message()
+	if d.Message != "" {
+		message = d.Message
+	}
+	if d.localized != "" {
+		message = d.localized
 	}
 
 	// 如果没有从 details 中提取到业务错误码，根据 gRPC code 映射
-	if code == CodeInternalError {
-		switch st.Code() {
-		case codes.InvalidArgument:
-			code = CodeInvalidParam
-		case codes.Unauthenticated:
-			code = CodeUnauthorized
-		case codes.PermissionDenied:
-			code = CodeForbidden
-		case codes.NotFound:
-			code = CodeNotFound
-		case codes.AlreadyExists:
-			code = CodeAlreadyExists
-		default:
-			code = CodeInternalError
-		}
+	// 规范编码中的错误码总是可信的，不再根据 gRPC code 重新映射
+	code := d.Code
+	canonical := d.retryable != nil && d.affectStability != nil
+	if code == 0 || (code == CodeInternalError && !canonical) {
+		code = CodeFromGRPC(st.Code())
 	}
 
-	return NewStatusError(code, message, extraData)
+	se := NewStatusError(code, truncateString(message, maxIncomingMessageLen), sanitizeIncomingExtra(d.Extra)).(*statusError)
+	se.reason = remoteReason(CanonicalCode(code), d.Reason)
+	se.details = d.attached
+	if canonical {
+		se.ext.Retryable = *d.retryable
+		se.ext.IsAffectStability = *d.affectStability
+	}
+	if retryDelay := d.retryDelay; retryDelay > 0 && retryDelay <= maxRetryAfter && se.RetryAfter() == 0 {
+		if se.ext.Extra == nil {
+			se.ext.Extra = make(map[string]string)
+		}
+		se.ext.Extra[RetryAfterKey] = strconv.FormatInt(retryDelay.Milliseconds(), 10)
+	}
+	return se
 }
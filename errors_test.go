@@ -2,6 +2,10 @@ package errors_test
 
 import (
 	errstd "errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
 	"testing"
 
 	"google.golang.org/protobuf/types/known/anypb"
@@ -247,6 +251,40 @@ func TestWithStackAlreadyWrapped(t *testing.T) {
 	}
 }
 
+func loadOrder() errors.StatusError {
+	return errors.NewWithStatus(errors.CodeNotFound, "订单不存在")
+}
+
+func TestWrapSites(t *testing.T) {
+	origin := loadOrder()
+	err := errors.WithStack(origin)
+	err = errors.WrapWithStatusOptions(err, errors.CodeInternalError, "")
+	err = errors.Annotate(err, "while paying")
+	err = errors.WrapWithStatusOptions(err, errors.CodeInternalError, "", errors.OverrideCode())
+
+	if got, want := err.(interface{ Stack() string }).Stack(), origin.(interface{ Stack() string }).Stack(); got != want {
+		t.Errorf("Stack() = %s, want origin stack %s", got, want)
+	}
+	sites := errors.WrapSites(err)
+	if len(sites) != 4 {
+		t.Fatalf("WrapSites() = %q, want 4 sites", sites)
+	}
+	for _, site := range sites {
+		if !strings.Contains(site, "TestWrapSites") || !strings.Contains(site, "errors_test.go:") {
+			t.Errorf("WrapSites() = %q, want sites in TestWrapSites", sites)
+		}
+	}
+	if sites := errors.WrapSites(origin); sites != nil {
+		t.Errorf("WrapSites(origin) = %q, want nil", sites)
+	}
+	if sites := errors.WrapSites(errors.WithStack(origin)); len(sites) != 1 {
+		t.Errorf("WithStack 不应修改原错误的包装位置: %q", sites)
+	}
+	if sites := errors.WrapSites(errstd.New("boom")); sites != nil {
+		t.Errorf("WrapSites(普通错误) = %q, want nil", sites)
+	}
+}
+
 func TestNewWithStatus(t *testing.T) {
 	err := errors.NewWithStatus(errors.CodeUserNotFound, "用户不存在")
 
@@ -312,6 +350,82 @@ func TestWrapWithStatusOptions(t *testing.T) {
 	}
 }
 
+func TestWrapWithStatusPreservesCode(t *testing.T) {
+	inner := errors.NewWithStatus(errors.CodeNotFound, "订单不存在", errors.Extra("order_id", "42"))
+
+	tests := []struct {
+		name      string
+		err       errors.StatusError
+		wantCode  int32
+		wantMsg   string
+		wantError string
+	}{
+		{
+			name:      "沿用内层错误码并添加上下文",
+			err:       errors.WrapWithStatusOptions(inner, errors.CodeInternalError, "加载订单失败"),
+			wantCode:  errors.CodeNotFound,
+			wantMsg:   "加载订单失败: 订单不存在",
+			wantError: "加载订单失败: 订单不存在",
+		},
+		{
+			name:      "没有上下文",
+			err:       errors.WrapWithStatusOptions(inner, errors.CodeInternalError, ""),
+			wantCode:  errors.CodeNotFound,
+			wantMsg:   "订单不存在",
+			wantError: "订单不存在",
+		},
+		{
+			name:      "错误链中的 StatusError",
+			err:       errors.WrapWithStatusOptions(fmt.Errorf("load: %w", inner), errors.CodeInternalError, "", errors.Extra("shard", "3")),
+			wantCode:  errors.CodeNotFound,
+			wantMsg:   "订单不存在",
+			wantError: "订单不存在: load: 订单不存在",
+		},
+		{
+			name:      "WrapWithStatus 总是使用新的错误码",
+			err:       errors.WrapWithStatus(fmt.Errorf("load: %w", inner), errors.CodeInternalError, "", nil),
+			wantCode:  errors.CodeInternalError,
+			wantMsg:   "内部服务器错误",
+			wantError: "内部服务器错误: load: 订单不存在",
+		},
+		{
+			name:      "多个 Option 中的 OverrideCode",
+			err:       errors.WrapWithStatusOptions(inner, errors.CodeUnauthorized, "", errors.Extra("shard", "3"), errors.OverrideCode()),
+			wantCode:  errors.CodeUnauthorized,
+			wantMsg:   "未授权",
+			wantError: "未授权: 订单不存在",
+		},
+		{
+			name:      "OverrideCode 使用新的错误码",
+			err:       errors.WrapWithStatusOptions(inner, errors.CodeInternalError, "", errors.OverrideCode()),
+			wantCode:  errors.CodeInternalError,
+			wantMsg:   "内部服务器错误",
+			wantError: "内部服务器错误: 订单不存在",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.err.Code() != tt.wantCode || tt.err.Msg() != tt.wantMsg || tt.err.Error() != tt.wantError {
+				t.Errorf("got (%d, %q, %q), want (%d, %q, %q)",
+					tt.err.Code(), tt.err.Msg(), tt.err.Error(), tt.wantCode, tt.wantMsg, tt.wantError)
+			}
+			if tt.wantCode == errors.CodeNotFound && (tt.err.IsAffectStability() || tt.err.Extra()["order_id"] != "42") {
+				t.Errorf("应沿用内层错误的稳定性与扩展信息: %v", tt.err.Extra())
+			}
+			if !errstd.Is(tt.err, inner) {
+				t.Error("errors.Is(err, inner) = false")
+			}
+		})
+	}
+
+	if err := errors.WrapWithStatusOptions(inner, errors.CodeInternalError, "", errors.Extra("shard", "3")); err.Extra()["shard"] != "3" {
+		t.Errorf("Extra() = %v, want shard", err.Extra())
+	}
+	if inner.Extra()["shard"] != "" {
+		t.Error("包装不应修改内层错误")
+	}
+}
+
 func TestWithStatusUnwrap(t *testing.T) {
 	originalErr := errstd.New("原始错误")
 	wrappedErr := errors.WrapWithStatus(originalErr, errors.CodeInternalError, "包装错误", nil)
@@ -424,3 +538,171 @@ func TestWithStatusCauseErrorMessage(t *testing.T) {
 		t.Errorf("Error() = %s, want %s", wrappedErr.Error(), expectedMsg)
 	}
 }
+
+func TestIsRetryable(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected bool
+	}{
+		{"超时默认可重试", errors.NewStatusError(errors.CodeRequestTimeout, "", nil), true},
+		{"限流默认可重试", errors.NewWithStatus(errors.CodeRateLimitExceeded, ""), true},
+		{"参数错误不可重试", errors.NewStatusError(errors.CodeInvalidParam, "", nil), false},
+		{"通过 Option 覆盖", errors.NewWithStatus(errors.CodeConflict, "", errors.Retryable(true)), true},
+		{"普通错误", errstd.New("plain"), false},
+		{"nil", nil, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := errors.IsRetryable(tt.err); got != tt.expected {
+				t.Errorf("IsRetryable() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestRangeExtra(t *testing.T) {
+	tests := []struct {
+		name string
+		err  errors.StatusError
+		want map[string]string
+	}{
+		{
+			name: "statusError",
+			err:  errors.NewStatusError(errors.CodeNotFound, "", map[string]string{"id": "42"}),
+			want: map[string]string{"id": "42"},
+		},
+		{
+			name: "withStatus 包含堆栈",
+			err:  errors.WithStack(errors.NewStatusError(errors.CodeNotFound, "", map[string]string{"id": "42"})),
+			want: map[string]string{"id": "42", "stack": ""},
+		},
+		{
+			name: "没有扩展信息",
+			err:  errors.NewStatusError(errors.CodeNotFound, "", nil),
+			want: map[string]string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := make(map[string]string)
+			errors.RangeExtra(tt.err, func(k, v string) bool {
+				got[k] = v
+				return true
+			})
+			if len(got) != len(tt.want) {
+				t.Fatalf("RangeExtra() = %v, want keys %v", got, tt.want)
+			}
+			for k, v := range tt.want {
+				if _, ok := got[k]; !ok || (v != "" && got[k] != v) {
+					t.Errorf("RangeExtra()[%s] = %s, want %s", k, got[k], v)
+				}
+			}
+			// 与 Extra() 保持一致
+			if extra := tt.err.Extra(); len(extra) != len(got) {
+				t.Errorf("len(Extra()) = %d, want %d", len(extra), len(got))
+			}
+		})
+	}
+}
+
+func TestRangeExtraStop(t *testing.T) {
+	err := errors.NewWithStatus(errors.CodeNotFound, "", errors.Extra("a", "1"), errors.Extra("b", "2"))
+	calls := 0
+	errors.RangeExtra(err, func(string, string) bool {
+		calls++
+		return false
+	})
+	if calls != 1 {
+		t.Errorf("calls = %d, want 1", calls)
+	}
+}
+
+func TestNewStatusErrorAllocs(t *testing.T) {
+	allocs := testing.AllocsPerRun(100, func() {
+		_ = errors.NewStatusError(errors.CodeUnauthorized, "", nil)
+	})
+	if allocs != 1 {
+		t.Errorf("allocs = %v, want 1", allocs)
+	}
+}
+
+// BenchmarkNewWithStatus 衡量只构造、不输出堆栈的错误，大部分错误属于这种情况
+func BenchmarkNewWithStatus(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = errors.NewWithStatus(errors.CodeNotFound, "")
+	}
+}
+
+// BenchmarkNewWithStatusStack 衡量构造错误并输出堆栈，即符号化的额外开销
+func BenchmarkNewWithStatusStack(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		err := errors.NewWithStatus(errors.CodeNotFound, "")
+		_ = err.Extra()["stack"]
+	}
+}
+
+func BenchmarkToGRPCStatus(b *testing.B) {
+	err := errors.NewStatusError(errors.CodeNotFound, "订单不存在", map[string]string{"order_id": "42"})
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = errors.ToGRPCStatus(err)
+	}
+}
+
+func TestWith(t *testing.T) {
+	base := errors.NewStatusError(errors.CodeNotFound, "订单 {id} 不存在", map[string]string{"tenant": "t1"})
+
+	annotated := errors.With(base, errors.Param("id", "42"), errors.Extra("order_id", "42"))
+	if annotated.Msg() != "订单 42 不存在" || annotated.Extra()["order_id"] != "42" || annotated.Extra()["tenant"] != "t1" {
+		t.Errorf("With() = %s %v", annotated.Msg(), annotated.Extra())
+	}
+	if annotated.Code() != errors.CodeNotFound {
+		t.Errorf("Code() = %d, want %d", annotated.Code(), errors.CodeNotFound)
+	}
+
+	// 原错误不受影响
+	if base.Msg() != "订单 {id} 不存在" {
+		t.Errorf("base.Msg() = %s, 原错误不应被修改", base.Msg())
+	}
+	if _, ok := base.Extra()["order_id"]; ok {
+		t.Error("原错误的扩展信息不应被修改")
+	}
+
+	// 修改 Extra() 返回的 map 不影响错误
+	base.Extra()["tenant"] = "t2"
+	if base.Extra()["tenant"] != "t1" {
+		t.Error("修改 Extra() 的返回值不应影响错误")
+	}
+
+	if errors.With(nil) != nil {
+		t.Error("With(nil) 应返回 nil")
+	}
+}
+
+func TestWithConcurrent(t *testing.T) {
+	shared := errors.NewWithStatus(errors.CodeInternalError, "", errors.Extra("a", "1"))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(2)
+		go func(i int) {
+			defer wg.Done()
+			_ = errors.With(shared, errors.Extra(strconv.Itoa(i), "v"))
+		}(i)
+		go func() {
+			defer wg.Done()
+			errors.RangeExtra(shared, func(string, string) bool { return true })
+			_ = shared.Extra()
+		}()
+	}
+	wg.Wait()
+
+	if extra := shared.Extra(); len(extra) != 2 {
+		t.Errorf("Extra() = %v, 共享的错误不应被修改", extra)
+	}
+}
@@ -0,0 +1,149 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+// Package errtest 提供针对 StatusError 的测试断言.
+package errtest
+
+import (
+	stderrors "errors"
+	"fmt"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/go-anyway/framework-errors"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// AssertCode 断言 err 的业务错误码为 code，失败时返回 false
+//
+//	errtest.AssertCode(t, err, errors.CodeNotFound)
+func AssertCode(t testing.TB, err error, code int32) bool {
+	t.Helper()
+	se, ok := statusError(t, err)
+	if !ok {
+		return false
+	}
+	if se.Code() != code {
+		t.Errorf("错误码 = %d, want %d\n%s", se.Code(), code, describe(se))
+		return false
+	}
+	return true
+}
+
+// AssertRetryable 断言 err 是否可以重试，失败时返回 false
+func AssertRetryable(t testing.TB, err error, want bool) bool {
+	t.Helper()
+	se, ok := statusError(t, err)
+	if !ok {
+		return false
+	}
+	if got := errors.IsRetryable(err); got != want {
+		t.Errorf("IsRetryable() = %v, want %v\n%s", got, want, describe(se))
+		return false
+	}
+	return true
+}
+
+// AssertAffectsStability 断言 err 是否影响系统稳定性，失败时返回 false
+func AssertAffectsStability(t testing.TB, err error, want bool) bool {
+	t.Helper()
+	se, ok := statusError(t, err)
+	if !ok {
+		return false
+	}
+	if got := se.IsAffectStability(); got != want {
+		t.Errorf("IsAffectStability() = %v, want %v\n%s", got, want, describe(se))
+		return false
+	}
+	return true
+}
+
+// AssertExtraContains 断言 err 的扩展信息中 key 的值为 value，失败时返回 false
+func AssertExtraContains(t testing.TB, err error, key, value string) bool {
+	t.Helper()
+	se, ok := statusError(t, err)
+	if !ok {
+		return false
+	}
+	got, exists := se.Extra()[key]
+	switch {
+	case !exists:
+		t.Errorf("扩展信息中缺少 %q\n%s", key, describe(se))
+		return false
+	case got != value:
+		t.Errorf("扩展信息 %q = %q, want %q\n%s", key, got, value, describe(se))
+		return false
+	}
+	return true
+}
+
+// RequireGRPCCode 断言 err 对应的 gRPC code 为 code，失败时立即结束测试
+// err 可以是 gRPC 返回的错误，也可以是尚未转换的 StatusError.
+func RequireGRPCCode(t testing.TB, err error, code codes.Code) {
+	t.Helper()
+	if err == nil {
+		t.Fatalf("err = nil, want gRPC code %s", code)
+		return
+	}
+
+	var st *status.Status
+	var se errors.StatusError
+	if stderrors.As(err, &se) {
+		st = errors.ToGRPCStatus(se)
+	} else {
+		st = status.Convert(err)
+	}
+	if st.Code() != code {
+		t.Fatalf("gRPC code = %s, want %s\nmessage: %s", st.Code(), code, st.Message())
+	}
+}
+
+// statusError 从 err 中取出 StatusError，失败时报告错误
+func statusError(t testing.TB, err error) (errors.StatusError, bool) {
+	t.Helper()
+	if err == nil {
+		t.Errorf("err = nil, want StatusError")
+		return nil, false
+	}
+	var se errors.StatusError
+	if !stderrors.As(err, &se) {
+		t.Errorf("err 不是 StatusError: %T %v", err, err)
+		return nil, false
+	}
+	return se, true
+}
+
+// describe 返回用于失败信息的错误描述，扩展信息按 key 排序，不包含堆栈
+func describe(se errors.StatusError) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "  code:    %d\n  message: %s", se.Code(), se.Msg())
+
+	extra := se.Extra()
+	keys := make([]string, 0, len(extra))
+	for k := range extra {
+		if k != "stack" {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		fmt.Fprintf(&b, "\n  extra:   %s=%s", k, extra[k])
+	}
+	return b.String()
+}
@@ -0,0 +1,142 @@
+package errtest_test
+
+import (
+	stderrors "errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/go-anyway/framework-errors"
+	"github.com/go-anyway/framework-errors/errtest"
+
+	"google.golang.org/grpc/codes"
+)
+
+// recorder 记录断言失败的信息而不终止测试
+type recorder struct {
+	testing.TB
+	failures []string
+	fatal    bool
+}
+
+func (r *recorder) Helper() {}
+
+func (r *recorder) Errorf(format string, args ...interface{}) {
+	r.failures = append(r.failures, fmt.Sprintf(format, args...))
+}
+
+func (r *recorder) Fatalf(format string, args ...interface{}) {
+	r.fatal = true
+	r.Errorf(format, args...)
+}
+
+func TestAssertions(t *testing.T) {
+	err := fmt.Errorf("load order: %w", errors.NewWithStatus(errors.CodeRateLimitExceeded, "", errors.Extra("order_id", "42")))
+
+	tests := []struct {
+		name    string
+		assert  func(tb testing.TB) bool
+		wantOK  bool
+		wantMsg string
+	}{
+		{name: "错误码匹配", assert: func(tb testing.TB) bool { return errtest.AssertCode(tb, err, errors.CodeRateLimitExceeded) }, wantOK: true},
+		{name: "错误码不匹配", assert: func(tb testing.TB) bool { return errtest.AssertCode(tb, err, errors.CodeNotFound) }, wantMsg: "错误码 = 2003, want 1004"},
+		{name: "nil 错误", assert: func(tb testing.TB) bool { return errtest.AssertCode(tb, nil, errors.CodeNotFound) }, wantMsg: "err = nil"},
+		{name: "非 StatusError", assert: func(tb testing.TB) bool { return errtest.AssertCode(tb, stderrors.New("x"), errors.CodeNotFound) }, wantMsg: "不是 StatusError"},
+		{name: "可重试", assert: func(tb testing.TB) bool { return errtest.AssertRetryable(tb, err, true) }, wantOK: true},
+		{name: "可重试不匹配", assert: func(tb testing.TB) bool { return errtest.AssertRetryable(tb, err, false) }, wantMsg: "IsRetryable() = true"},
+		{name: "稳定性", assert: func(tb testing.TB) bool { return errtest.AssertAffectsStability(tb, err, false) }, wantOK: true},
+		{name: "扩展信息匹配", assert: func(tb testing.TB) bool { return errtest.AssertExtraContains(tb, err, "order_id", "42") }, wantOK: true},
+		{name: "扩展信息不匹配", assert: func(tb testing.TB) bool { return errtest.AssertExtraContains(tb, err, "order_id", "43") }, wantMsg: "extra:   order_id=42"},
+		{name: "缺少扩展信息", assert: func(tb testing.TB) bool { return errtest.AssertExtraContains(tb, err, "user_id", "1") }, wantMsg: "缺少 \"user_id\""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &recorder{TB: t}
+			if ok := tt.assert(r); ok != tt.wantOK {
+				t.Errorf("断言结果 = %v, want %v", ok, tt.wantOK)
+			}
+			if tt.wantOK {
+				if len(r.failures) > 0 {
+					t.Errorf("failures = %v", r.failures)
+				}
+				return
+			}
+			if len(r.failures) != 1 || !strings.Contains(r.failures[0], tt.wantMsg) {
+				t.Errorf("failures = %v, want containing %q", r.failures, tt.wantMsg)
+			}
+		})
+	}
+}
+
+func TestRequireGRPCCode(t *testing.T) {
+	se := errors.NewStatusError(errors.CodeNotFound, "", nil)
+
+	r := &recorder{TB: t}
+	errtest.RequireGRPCCode(r, se, codes.NotFound)
+	errtest.RequireGRPCCode(r, errors.ToGRPCError(se), codes.NotFound)
+	if r.fatal {
+		t.Errorf("failures = %v", r.failures)
+	}
+
+	errtest.RequireGRPCCode(r, errors.ToGRPCError(se), codes.Internal)
+	if !r.fatal || !strings.Contains(r.failures[0], "gRPC code = NotFound, want Internal") {
+		t.Errorf("failures = %v", r.failures)
+	}
+}
+
+func TestMatchers(t *testing.T) {
+	// 与 gomock.Matcher 相同的接口
+	var _ interface {
+		Matches(x interface{}) bool
+		String() string
+	} = errtest.MatchCode(errors.CodeNotFound)
+
+	err := fmt.Errorf("wrapped: %w", errors.NewStatusError(errors.CodeNotFound, "订单不存在", nil))
+	tests := []struct {
+		name    string
+		matcher errtest.Matcher
+		x       interface{}
+		want    bool
+	}{
+		{name: "错误码匹配", matcher: errtest.MatchCode(errors.CodeNotFound), x: err, want: true},
+		{name: "错误码不匹配", matcher: errtest.MatchCode(errors.CodeConflict), x: err, want: false},
+		{name: "消息包含", matcher: errtest.MatchMsgContains("不存在"), x: err, want: true},
+		{name: "消息不包含", matcher: errtest.MatchMsgContains("已存在"), x: err, want: false},
+		{name: "非错误参数", matcher: errtest.MatchCode(errors.CodeNotFound), x: "订单不存在", want: false},
+		{name: "nil", matcher: errtest.MatchCode(errors.CodeNotFound), x: nil, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.matcher.Matches(tt.x); got != tt.want {
+				t.Errorf("%s.Matches() = %v, want %v", tt.matcher, got, tt.want)
+			}
+		})
+	}
+
+	r := &recorder{TB: t}
+	if errtest.AssertMatch(r, err, errtest.MatchCode(errors.CodeNotFound), errtest.MatchMsgContains("已存在")) {
+		t.Error("AssertMatch() = true, want false")
+	}
+	if len(r.failures) != 1 || !strings.Contains(r.failures[0], `message containing "已存在"`) {
+		t.Errorf("failures = %v", r.failures)
+	}
+}
+
+func TestAssertGolden(t *testing.T) {
+	err := errors.NewWithStatus(errors.CodeNotFound, "订单 {id} 不存在", errors.Param("id", "42"),
+		errors.Extra("order_id", "42"), errors.Extra("tenant", "t1"))
+	errtest.AssertGolden(t, err, "testdata/not_found.golden.json")
+
+	t.Setenv(errtest.UpdateGoldenEnv, "")
+	r := &recorder{TB: t}
+	changed := errors.NewWithStatus(errors.CodeNotFound, "订单 {id} 不存在", errors.Param("id", "43"))
+	if errtest.AssertGolden(r, changed, "testdata/not_found.golden.json") {
+		t.Error("AssertGolden() = true, want false")
+	}
+	if len(r.failures) != 1 || !strings.Contains(r.failures[0], "不一致") {
+		t.Errorf("failures = %v", r.failures)
+	}
+}
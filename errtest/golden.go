@@ -0,0 +1,133 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package errtest
+
+import (
+	"bytes"
+	"encoding/json"
+	stderrors "errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"google.golang.org/protobuf/encoding/protojson"
+
+	"github.com/go-anyway/framework-errors"
+)
+
+// UpdateGoldenEnv 是更新 golden 文件的环境变量，设置为 1 时 AssertGolden 会覆盖 golden 文件
+//
+//	ERRTEST_UPDATE_GOLDEN=1 go test ./...
+const UpdateGoldenEnv = "ERRTEST_UPDATE_GOLDEN"
+
+type httpSnapshot struct {
+	Status int             `json:"status"`
+	Body   errors.Envelope `json:"body"`
+}
+
+type snapshot struct {
+	// GRPC 是 google.rpc.Status 的 protojson 表示，包括 details 的类型与内容
+	GRPC interface{}  `json:"grpc"`
+	HTTP httpSnapshot `json:"http"`
+}
+
+// Snapshot 将错误分别经过 gRPC 与 HTTP 序列化，返回规范化的 JSON
+// gRPC 部分记录线上传输的 status（protojson 格式），而不是解码后的结果，detail 类型、编码方式等变化都会被发现.
+// 对象的 key 按字典序排序并去掉堆栈，结果可以直接与 golden 文件比较.
+func Snapshot(err error) ([]byte, error) {
+	se := errors.Translate(err)
+	if se == nil {
+		return nil, stderrors.New("errtest: err is nil")
+	}
+
+	// protojson 的输出格式不稳定，重新解析后由 encoding/json 按 key 排序输出
+	wire, e := protojson.MarshalOptions{UseProtoNames: true}.Marshal(errors.ToGRPCStatus(se).Proto())
+	if e != nil {
+		return nil, e
+	}
+	var grpcSnap interface{}
+	if e := json.Unmarshal(wire, &grpcSnap); e != nil {
+		return nil, e
+	}
+	dropStack(grpcSnap)
+
+	snap := snapshot{
+		GRPC: grpcSnap,
+		HTTP: httpSnapshot{
+			Status: errors.HTTPStatus(se.Code()),
+			Body:   errors.NewEnvelope(se),
+		},
+	}
+
+	// encoding/json 按 key 排序输出 map，保证结果稳定
+	data, e := json.MarshalIndent(snap, "", "  ")
+	if e != nil {
+		return nil, e
+	}
+	return append(data, '\n'), nil
+}
+
+// dropStack 删除 JSON 对象中的调用堆栈，堆栈随代码位置变化，不应写入 golden 文件
+func dropStack(v interface{}) {
+	switch node := v.(type) {
+	case map[string]interface{}:
+		delete(node, "stack")
+		for _, child := range node {
+			dropStack(child)
+		}
+	case []interface{}:
+		for _, child := range node {
+			dropStack(child)
+		}
+	}
+}
+
+// AssertGolden 断言 err 的序列化结果与 golden 文件一致，失败时返回 false
+// 设置环境变量 ERRTEST_UPDATE_GOLDEN=1 时会用当前结果覆盖 golden 文件.
+//
+//	errtest.AssertGolden(t, err, "testdata/order_not_found.golden.json")
+func AssertGolden(t testing.TB, err error, path string) bool {
+	t.Helper()
+	got, e := Snapshot(err)
+	if e != nil {
+		t.Errorf("Snapshot() error = %v", e)
+		return false
+	}
+
+	if os.Getenv(UpdateGoldenEnv) == "1" {
+		if e := os.MkdirAll(filepath.Dir(path), 0o755); e != nil {
+			t.Errorf("创建目录失败: %v", e)
+			return false
+		}
+		if e := os.WriteFile(path, got, 0o644); e != nil {
+			t.Errorf("写入 golden 文件失败: %v", e)
+			return false
+		}
+		return true
+	}
+
+	want, e := os.ReadFile(path)
+	if e != nil {
+		t.Errorf("读取 golden 文件失败: %v（设置 %s=1 生成）", e, UpdateGoldenEnv)
+		return false
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("序列化结果与 %s 不一致（设置 %s=1 更新）\ngot:\n%s\nwant:\n%s", path, UpdateGoldenEnv, got, want)
+		return false
+	}
+	return true
+}
@@ -0,0 +1,83 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package errtest
+
+import (
+	stderrors "errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/go-anyway/framework-errors"
+)
+
+// Matcher 匹配 StatusError 类型的参数
+// Matcher 实现了 gomock.Matcher 接口，可以直接作为 gomock 的参数匹配器；
+// 配合 testify 使用时可以传入 mock.MatchedBy(m.Matches).
+//
+//	repo.EXPECT().Save(gomock.Any(), errtest.MatchCode(errors.CodeConflict))
+//	m.On("Report", mock.MatchedBy(errtest.MatchCode(errors.CodeConflict).Matches))
+type Matcher struct {
+	desc  string
+	match func(se errors.StatusError) bool
+}
+
+// Matches 判断 x 是否是满足条件的 StatusError
+func (m Matcher) Matches(x interface{}) bool {
+	err, ok := x.(error)
+	if !ok || err == nil {
+		return false
+	}
+	var se errors.StatusError
+	if !stderrors.As(err, &se) {
+		return false
+	}
+	return m.match(se)
+}
+
+// String 返回匹配条件的描述，用于失败信息
+func (m Matcher) String() string {
+	return m.desc
+}
+
+// MatchCode 匹配业务错误码为 code 的 StatusError
+func MatchCode(code int32) Matcher {
+	return Matcher{
+		desc:  fmt.Sprintf("StatusError with code %d", code),
+		match: func(se errors.StatusError) bool { return se.Code() == code },
+	}
+}
+
+// MatchMsgContains 匹配消息中包含 s 的 StatusError
+func MatchMsgContains(s string) Matcher {
+	return Matcher{
+		desc:  fmt.Sprintf("StatusError with message containing %q", s),
+		match: func(se errors.StatusError) bool { return strings.Contains(se.Msg(), s) },
+	}
+}
+
+// AssertMatch 断言 err 满足所有 matchers，失败时返回 false
+func AssertMatch(t testing.TB, err error, matchers ...Matcher) bool {
+	t.Helper()
+	for _, m := range matchers {
+		if !m.Matches(err) {
+			t.Errorf("err = %v, want %s", err, m)
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,55 @@
+package errors
+
+import "go.uber.org/zap/zapcore"
+
+// Now 导出 now 供外部测试使用
+var Now = now
+
+// ResetScrubbers 清空已注册的 Scrubber 供外部测试使用
+func ResetScrubbers() {
+	scrubbers.Store(nil)
+	grpcStatusCache.Clear()
+}
+
+// LogDedupLen 返回日志去重记录的指纹数量供外部测试使用
+func LogDedupLen() int {
+	logDedupMu.Lock()
+	defer logDedupMu.Unlock()
+	return len(logDedup)
+}
+
+// LogLevel 导出 logLevel 供外部测试使用
+var LogLevel = logLevel
+
+// ResetLogLevels 恢复默认的日志级别配置供外部测试使用
+func ResetLogLevels() {
+	logLevelsMu.Lock()
+	defer logLevelsMu.Unlock()
+	stabilityLogLevel = zapcore.ErrorLevel
+	otherLogLevel = zapcore.WarnLevel
+	codeLogLevels = map[int32]zapcore.Level{}
+	suppressedCodes = map[int32]bool{}
+}
+
+// ResetReporters 清空已注册的 Reporter 供外部测试使用
+func ResetReporters() {
+	reporters.Store(nil)
+	syncReporters.Store(nil)
+}
+
+// ResetTranslators 清空已注册的 Translator 供外部测试使用
+func ResetTranslators() {
+	translatorsMu.Lock()
+	defer translatorsMu.Unlock()
+	translators = nil
+}
+
+// ResetAliases 清空已注册的错误码别名供外部测试使用
+func ResetAliases() {
+	aliases.Store(nil)
+}
+
+// ResetDetailDecoders 清空已注册的 DetailDecoder 供外部测试使用
+func ResetDetailDecoders() {
+	detailDecoders.Store(nil)
+}
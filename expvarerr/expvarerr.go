@@ -0,0 +1,43 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+// Package expvarerr 将按错误码统计的错误数量发布到 expvar.
+// 引入 expvar 会在 http.DefaultServeMux 上注册 /debug/vars，暴露命令行参数与内存统计，
+// 因此放在单独的包中，只有需要的服务才引入.
+package expvarerr
+
+import (
+	"expvar"
+	"sync"
+
+	"github.com/go-anyway/framework-errors"
+)
+
+// Name 是错误计数在 expvar 中发布的名称
+const Name = "framework_errors"
+
+var publishOnce sync.Once
+
+// Publish 开启 errors.EnableErrorCounts，并将错误计数以 framework_errors 发布到 expvar
+// 可以通过 curl /debug/vars 查看，重复调用是安全的.
+func Publish() {
+	errors.EnableErrorCounts(true)
+	publishOnce.Do(func() {
+		expvar.Publish(Name, expvar.Func(func() interface{} {
+			return errors.ErrorCounts()
+		}))
+	})
+}
@@ -0,0 +1,31 @@
+package expvarerr_test
+
+import (
+	"context"
+	"encoding/json"
+	"expvar"
+	"testing"
+
+	"github.com/go-anyway/framework-errors"
+	"github.com/go-anyway/framework-errors/expvarerr"
+)
+
+func TestPublish(t *testing.T) {
+	expvarerr.Publish()
+	expvarerr.Publish()
+	defer errors.EnableErrorCounts(false)
+
+	count := func() int64 {
+		var counts map[string]int64
+		if err := json.Unmarshal([]byte(expvar.Get(expvarerr.Name).String()), &counts); err != nil {
+			t.Fatalf("json.Unmarshal() error = %v", err)
+		}
+		return counts["1004"]
+	}
+
+	before := count()
+	_ = errors.LogAndReturnError(context.Background(), errors.NewStatusError(errors.CodeNotFound, "", nil), errors.SkipLog())
+	if got := count() - before; got != 1 {
+		t.Errorf("framework_errors[1004] 增加了 %d, want 1", got)
+	}
+}
@@ -0,0 +1,114 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package errors
+
+import "context"
+
+// FallbackKey 是降级点名称在扩展信息中的 key，见 Fallback
+const FallbackKey = "fallback"
+
+// FallbackOptions 是 Fallback 的选项
+type FallbackOptions struct {
+	// Name 是降级点的名称，例如 "recommend-cache"，记录在 Extra[FallbackKey] 中，为空时使用 "default"
+	Name string
+	// ShouldFallback 判断主调用的错误是否执行兜底，为 nil 时只对依赖错误（CategoryDependency）与可重试的错误执行兜底
+	ShouldFallback func(err StatusError) bool
+}
+
+// FallbackSource 是 Fallback 返回值的来源
+type FallbackSource int
+
+const (
+	// FromPrimary 表示返回值来自主调用
+	FromPrimary FallbackSource = iota
+	// FromFallback 表示主调用失败，返回值来自兜底
+	FromFallback
+)
+
+// String 返回来源的名称，例如 "fallback"
+func (s FallbackSource) String() string {
+	if s == FromFallback {
+		return "fallback"
+	}
+	return "primary"
+}
+
+// FallbackResult 是 Fallback 的返回值及其来源
+type FallbackResult[T any] struct {
+	Value  T
+	Source FallbackSource
+	// Cause 是导致降级的主调用错误，Extra 中记录了降级点名称，SLO 影响程度为 SLOImpactDegraded；
+	// 没有降级时为 nil
+	Cause StatusError
+}
+
+// Degraded 返回结果是否来自兜底
+func (r FallbackResult[T]) Degraded() bool {
+	return r.Source == FromFallback
+}
+
+// Fallback 执行 primary，失败且错误适合降级时执行 fallback 并返回兜底结果
+// 错误先经过 Translate 分类，默认只有依赖错误与可重试的错误才会降级，参数错误等业务错误直接返回.
+// 兜底成功时主调用的错误只以 SLOImpactDegraded 计入 SLOMetricsSink，不计入错误计数，监控面板可以区分降级与失败；
+// 兜底也失败时返回主调用的错误，兜底的错误记录在内部扩展信息 fallback_error 中. ctx 结束后不再执行兜底.
+//
+//	res, err := errors.Fallback(ctx, func() ([]Item, error) {
+//		return recommendClient.List(ctx, uid)
+//	}, func() ([]Item, error) {
+//		return hotItemsCache.Get(ctx)
+//	}, errors.FallbackOptions{Name: "recommend"})
+func Fallback[T any](ctx context.Context, primary, fallback func() (T, error), opts FallbackOptions) (FallbackResult[T], error) {
+	var res FallbackResult[T]
+	if ctx.Err() != nil {
+		return res, FromContextErr(ctx, nil)
+	}
+
+	v, err := primary()
+	if err == nil {
+		res.Value = v
+		return res, nil
+	}
+
+	se := Translate(err)
+	should := opts.ShouldFallback
+	if should == nil {
+		should = defaultShouldFallback
+	}
+	if !should(se) || ctx.Err() != nil {
+		return res, se
+	}
+
+	name := opts.Name
+	if name == "" {
+		name = "default"
+	}
+	v, fbErr := fallback()
+	if fbErr != nil {
+		return res, With(se, Extra(FallbackKey, name), InternalExtra("fallback_error", fbErr.Error()))
+	}
+
+	res.Value = v
+	res.Source = FromFallback
+	res.Cause = With(se, Extra(FallbackKey, name), Impact(SLOImpactDegraded))
+	countDegraded(res.Cause)
+	return res, nil
+}
+
+// defaultShouldFallback 只对依赖错误与可重试的错误执行兜底
+func defaultShouldFallback(err StatusError) bool {
+	return IsCategory(err, CategoryDependency) || IsRetryable(err)
+}
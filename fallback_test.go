@@ -0,0 +1,141 @@
+package errors_test
+
+import (
+	"context"
+	errstd "errors"
+	"testing"
+
+	"github.com/go-anyway/framework-errors"
+)
+
+func TestFallback(t *testing.T) {
+	fallback := func() (string, error) { return "cached", nil }
+
+	tests := []struct {
+		name       string
+		primaryErr error
+		opts       errors.FallbackOptions
+		want       string
+		wantSource errors.FallbackSource
+		wantCode   int32
+	}{
+		{
+			name:       "主调用成功",
+			want:       "fresh",
+			wantSource: errors.FromPrimary,
+		},
+		{
+			name:       "依赖错误执行兜底",
+			primaryErr: errors.NewWithStatus(errors.CodeDependencyUnavailable, ""),
+			opts:       errors.FallbackOptions{Name: "recommend"},
+			want:       "cached",
+			wantSource: errors.FromFallback,
+		},
+		{
+			name:       "可重试的错误执行兜底",
+			primaryErr: errors.NewWithStatus(errors.CodeRequestTimeout, ""),
+			want:       "cached",
+			wantSource: errors.FromFallback,
+		},
+		{
+			name:       "业务错误不执行兜底",
+			primaryErr: errors.NewWithStatus(errors.CodeNotFound, ""),
+			wantCode:   errors.CodeNotFound,
+		},
+		{
+			name:       "自定义判断",
+			primaryErr: errors.NewWithStatus(errors.CodeNotFound, ""),
+			opts:       errors.FallbackOptions{ShouldFallback: func(err errors.StatusError) bool { return err.Code() == errors.CodeNotFound }},
+			want:       "cached",
+			wantSource: errors.FromFallback,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := errors.Fallback(context.Background(), func() (string, error) {
+				if tt.primaryErr != nil {
+					return "", tt.primaryErr
+				}
+				return "fresh", nil
+			}, fallback, tt.opts)
+
+			if tt.wantCode != 0 {
+				if errors.Translate(err).Code() != tt.wantCode || res.Degraded() {
+					t.Fatalf("Fallback() = %+v, %v, want code %d", res, err, tt.wantCode)
+				}
+				return
+			}
+			if err != nil || res.Value != tt.want || res.Source != tt.wantSource {
+				t.Fatalf("Fallback() = %+v, %v, want %q from %s", res, err, tt.want, tt.wantSource)
+			}
+			if !res.Degraded() {
+				if res.Cause != nil {
+					t.Errorf("Cause = %v, want nil", res.Cause)
+				}
+				return
+			}
+			name := tt.opts.Name
+			if name == "" {
+				name = "default"
+			}
+			if got := res.Cause.Extra()[errors.FallbackKey]; got != name {
+				t.Errorf("Extra[FallbackKey] = %q, want %q", got, name)
+			}
+			if got := errors.SLOImpactOf(res.Cause); got != errors.SLOImpactDegraded {
+				t.Errorf("SLOImpactOf(Cause) = %s, want degraded", got)
+			}
+		})
+	}
+}
+
+func TestFallbackFails(t *testing.T) {
+	res, err := errors.Fallback(context.Background(), func() (int, error) {
+		return 0, errors.NewWithStatus(errors.CodeDependencyTimeout, "")
+	}, func() (int, error) {
+		return 0, errstd.New("cache miss")
+	}, errors.FallbackOptions{Name: "quota"})
+
+	se := errors.Translate(err)
+	if res.Degraded() || se.Code() != errors.CodeDependencyTimeout {
+		t.Fatalf("Fallback() = %+v, %v, want primary error", res, err)
+	}
+	if se.Extra()[errors.FallbackKey] != "quota" || se.Extra()["fallback_error"] != "cache miss" ||
+		!errors.IsInternalExtra(se, "fallback_error") {
+		t.Errorf("Extra() = %v", se.Extra())
+	}
+}
+
+func TestFallbackContextDone(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	called := false
+	_, err := errors.Fallback(ctx, func() (int, error) {
+		cancel()
+		return 0, errors.NewWithStatus(errors.CodeDependencyUnavailable, "")
+	}, func() (int, error) {
+		called = true
+		return 1, nil
+	}, errors.FallbackOptions{})
+
+	if called || errors.Translate(err).Code() != errors.CodeDependencyUnavailable {
+		t.Errorf("Fallback() = %v, fallback called = %v", err, called)
+	}
+}
+
+func TestFallbackMetrics(t *testing.T) {
+	sink := &fakeSLOSink{fakeSink: newFakeSink(), impacts: map[int32]errors.SLOImpact{}}
+	errors.SetMetricsSink(sink)
+	defer errors.SetMetricsSink(nil)
+
+	_, _ = errors.Fallback(context.Background(), func() (int, error) {
+		return 0, errors.NewWithStatus(errors.CodeInternalError, "", errors.Retryable(true))
+	}, func() (int, error) {
+		return 1, nil
+	}, errors.FallbackOptions{})
+
+	if got := sink.impacts[errors.CodeInternalError]; got != errors.SLOImpactDegraded {
+		t.Errorf("impacts = %v, want degraded", sink.impacts)
+	}
+	if len(sink.counts) != 0 {
+		t.Errorf("counts = %v, 降级成功不应计入错误计数", sink.counts)
+	}
+}
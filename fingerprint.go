@@ -0,0 +1,45 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package errors
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strconv"
+)
+
+// Fingerprint 计算错误的指纹，用于聚合同一类错误
+// 指纹由错误码和错误产生的位置（堆栈第一帧的函数名）决定，
+// 没有堆栈信息时使用错误消息代替，返回 16 位十六进制字符串.
+func Fingerprint(err error) string {
+	if err == nil {
+		return ""
+	}
+
+	se := Translate(err)
+	origin := se.Msg()
+	var ws *withStatus
+	if errors.As(err, &ws) {
+		if fn := ws.stack.origin(); fn != "" {
+			origin = fn
+		}
+	}
+
+	sum := sha256.Sum256([]byte(strconv.Itoa(int(se.Code())) + "|" + origin))
+	return hex.EncodeToString(sum[:8])
+}
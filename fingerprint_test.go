@@ -0,0 +1,49 @@
+package errors_test
+
+import (
+	errstd "errors"
+	"testing"
+
+	"github.com/go-anyway/framework-errors"
+)
+
+func newNotFound(id string) errors.StatusError {
+	return errors.NewWithStatus(errors.CodeNotFound, "item {id} not found", errors.Param("id", id))
+}
+
+func TestFingerprint(t *testing.T) {
+	a := errors.Fingerprint(newNotFound("1"))
+	b := errors.Fingerprint(newNotFound("2"))
+	if a == "" || len(a) != 16 {
+		t.Fatalf("Fingerprint() = %q, want 16 hex chars", a)
+	}
+	if a != b {
+		t.Errorf("同一位置产生的同类错误指纹应相同: %s != %s", a, b)
+	}
+
+	c := errors.Fingerprint(errors.NewWithStatus(errors.CodeForbidden, ""))
+	if a == c {
+		t.Error("不同错误码的指纹应不同")
+	}
+
+	if errors.Fingerprint(nil) != "" {
+		t.Error("Fingerprint(nil) 应返回空字符串")
+	}
+}
+
+func TestFingerprintSkipsTranslatorFrames(t *testing.T) {
+	if loadOrders() == loadUsers() {
+		t.Error("不同函数经由 Translate 产生的错误指纹应不同")
+	}
+	if loadOrders() != loadOrders() {
+		t.Error("同一函数经由 Translate 产生的错误指纹应相同")
+	}
+}
+
+func loadOrders() string {
+	return errors.Fingerprint(errors.Translate(errstd.New("boom")))
+}
+
+func loadUsers() string {
+	return errors.Fingerprint(errors.Translate(errstd.New("boom")))
+}
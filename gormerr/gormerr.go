@@ -0,0 +1,129 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+// Package gormerr 将 GORM 返回的错误翻译为 StatusError.
+package gormerr
+
+import (
+	stderrors "errors"
+
+	"github.com/go-anyway/framework-errors"
+
+	"gorm.io/gorm"
+)
+
+// codeMapping 是 GORM 哨兵错误到业务错误码的映射
+// 未列出的 GORM 错误多为调用方使用不当，统一映射为 CodeInternalError
+var codeMapping = []struct {
+	target error
+	code   int32
+}{
+	{gorm.ErrRecordNotFound, errors.CodeNotFound},
+	{gorm.ErrDuplicatedKey, errors.CodeAlreadyExists},
+	{gorm.ErrForeignKeyViolated, errors.CodeInvalidParam},
+	{gorm.ErrCheckConstraintViolated, errors.CodeInvalidParam},
+	{gorm.ErrInvalidTransaction, errors.CodeInternalError},
+	{gorm.ErrNotImplemented, errors.CodeInternalError},
+	{gorm.ErrMissingWhereClause, errors.CodeInternalError},
+	{gorm.ErrUnsupportedRelation, errors.CodeInternalError},
+	{gorm.ErrPrimaryKeyRequired, errors.CodeInternalError},
+	{gorm.ErrModelValueRequired, errors.CodeInternalError},
+	{gorm.ErrModelAccessibleFieldsRequired, errors.CodeInternalError},
+	{gorm.ErrSubQueryRequired, errors.CodeInternalError},
+	{gorm.ErrInvalidData, errors.CodeInternalError},
+	{gorm.ErrUnsupportedDriver, errors.CodeInternalError},
+	{gorm.ErrRegistered, errors.CodeInternalError},
+	{gorm.ErrInvalidField, errors.CodeInternalError},
+	{gorm.ErrEmptySlice, errors.CodeInternalError},
+	{gorm.ErrDryRunModeUnsupported, errors.CodeInternalError},
+	{gorm.ErrInvalidDB, errors.CodeInternalError},
+	{gorm.ErrInvalidValue, errors.CodeInternalError},
+	{gorm.ErrInvalidValueOfLength, errors.CodeInternalError},
+	{gorm.ErrPreloadNotAllowed, errors.CodeInternalError},
+}
+
+// Translate 将 GORM 错误翻译为 StatusError
+// 如果 err 不是 GORM 定义的错误则返回 nil，可直接用于 errors.RegisterTranslator.
+func Translate(err error) errors.StatusError {
+	if err == nil {
+		return nil
+	}
+	for _, m := range codeMapping {
+		if stderrors.Is(err, m.target) {
+			return errors.WrapWithStatusOptions(err, m.code, "", errors.OverrideCode())
+		}
+	}
+	return nil
+}
+
+// Plugin 是一个 GORM 插件，在每次操作结束后将 db.Error 自动包装为 StatusError.
+// 包装后的错误仍然可以通过 errors.Is(err, gorm.ErrRecordNotFound) 判断.
+//
+//	db.Use(gormerr.Plugin{})
+type Plugin struct{}
+
+// Name 实现 gorm.Plugin 接口
+func (Plugin) Name() string {
+	return "framework-errors"
+}
+
+// Initialize 实现 gorm.Plugin 接口，在所有回调之后注册错误翻译回调
+func (p Plugin) Initialize(db *gorm.DB) error {
+	cb := db.Callback()
+	registers := []func() error{
+		func() error { return cb.Create().After("*").Register("errors:translate", translateCallback) },
+		func() error { return cb.Query().After("*").Register("errors:translate", translateCallback) },
+		func() error { return cb.Update().After("*").Register("errors:translate", translateCallback) },
+		func() error { return cb.Delete().After("*").Register("errors:translate", translateCallback) },
+		func() error { return cb.Row().After("*").Register("errors:translate", translateCallback) },
+		func() error { return cb.Raw().After("*").Register("errors:translate", translateCallback) },
+	}
+	for _, register := range registers {
+		if err := register(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// translateCallback 将 db.Error 翻译为 StatusError，并记录出错的表名
+func translateCallback(db *gorm.DB) {
+	if db.Error == nil {
+		return
+	}
+
+	var se errors.StatusError
+	if stderrors.As(db.Error, &se) {
+		return
+	}
+
+	translated := Translate(db.Error)
+	if translated == nil {
+		translated = errors.Translate(db.Error)
+	}
+
+	var opts []errors.Option
+	for k, v := range translated.Extra() {
+		if k != "stack" {
+			opts = append(opts, errors.Extra(k, v))
+		}
+	}
+	if db.Statement != nil && db.Statement.Table != "" {
+		opts = append(opts, errors.Extra("table", db.Statement.Table))
+	}
+
+	db.Error = errors.WrapWithStatusOptions(db.Error, translated.Code(), translated.Msg(), append([]errors.Option{errors.OverrideCode()}, opts...)...)
+}
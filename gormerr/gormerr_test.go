@@ -0,0 +1,97 @@
+package gormerr_test
+
+import (
+	stderrors "errors"
+	"fmt"
+	"testing"
+
+	"github.com/go-anyway/framework-errors"
+	"github.com/go-anyway/framework-errors/gormerr"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+func TestTranslate(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected int32
+	}{
+		{"记录不存在", gorm.ErrRecordNotFound, errors.CodeNotFound},
+		{"主键冲突", gorm.ErrDuplicatedKey, errors.CodeAlreadyExists},
+		{"外键约束", gorm.ErrForeignKeyViolated, errors.CodeInvalidParam},
+		{"无效事务", gorm.ErrInvalidTransaction, errors.CodeInternalError},
+		{"被包装的错误", fmt.Errorf("query user: %w", gorm.ErrRecordNotFound), errors.CodeNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			se := gormerr.Translate(tt.err)
+			if se == nil {
+				t.Fatal("Translate() = nil, want StatusError")
+			}
+			if se.Code() != tt.expected {
+				t.Errorf("Code() = %d, want %d", se.Code(), tt.expected)
+			}
+			if !stderrors.Is(se, tt.err) {
+				t.Error("翻译后的错误应保留原始错误")
+			}
+		})
+	}
+}
+
+func TestTranslateUnknown(t *testing.T) {
+	if se := gormerr.Translate(stderrors.New("other")); se != nil {
+		t.Errorf("Translate() = %v, want nil", se)
+	}
+	if se := gormerr.Translate(nil); se != nil {
+		t.Errorf("Translate(nil) = %v, want nil", se)
+	}
+}
+
+// fakeDialector 是一个不连接数据库的 Dialector，仅用于测试回调
+type fakeDialector struct{}
+
+func (fakeDialector) Name() string                                   { return "fake" }
+func (fakeDialector) Initialize(*gorm.DB) error                      { return nil }
+func (fakeDialector) Migrator(*gorm.DB) gorm.Migrator                { return nil }
+func (fakeDialector) DataTypeOf(*schema.Field) string                { return "" }
+func (fakeDialector) DefaultValueOf(*schema.Field) clause.Expression { return nil }
+func (fakeDialector) BindVarTo(clause.Writer, *gorm.Statement, interface{}) {
+}
+func (fakeDialector) QuoteTo(clause.Writer, string)               {}
+func (fakeDialector) Explain(sql string, _ ...interface{}) string { return sql }
+
+func TestPlugin(t *testing.T) {
+	db, err := gorm.Open(fakeDialector{}, &gorm.Config{})
+	if err != nil {
+		t.Fatalf("gorm.Open() error = %v", err)
+	}
+	if err := db.Use(gormerr.Plugin{}); err != nil {
+		t.Fatalf("db.Use() error = %v", err)
+	}
+	if err := db.Callback().Query().Register("test:fail", func(tx *gorm.DB) {
+		_ = tx.AddError(gorm.ErrRecordNotFound)
+	}); err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+
+	var rows []map[string]interface{}
+	err = db.Table("users").Find(&rows).Error
+
+	var se errors.StatusError
+	if !stderrors.As(err, &se) {
+		t.Fatalf("error = %v, want StatusError", err)
+	}
+	if se.Code() != errors.CodeNotFound {
+		t.Errorf("Code() = %d, want %d", se.Code(), errors.CodeNotFound)
+	}
+	if se.Extra()["table"] != "users" {
+		t.Errorf("Extra[table] = %s, want users", se.Extra()["table"])
+	}
+	if !stderrors.Is(err, gorm.ErrRecordNotFound) {
+		t.Error("应能通过 errors.Is 判断 gorm.ErrRecordNotFound")
+	}
+}
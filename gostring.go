@@ -0,0 +1,85 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package errors
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// GoString 实现 fmt.GoStringer，%#v 与调试器输出可读的错误码、消息与扩展信息，而不是未导出结构体的指针
+//
+//	errors.StatusError{Code: 1004, Reason: "NOT_FOUND", Msg: "资源未找到", Extra: map[string]string{"id": "42"}}
+func (e *statusError) GoString() string {
+	return goString(e, "", nil)
+}
+
+// GoString 实现 fmt.GoStringer，在 statusError 的基础上输出注解与底层错误的类型，不包含调用堆栈
+//
+//	errors.StatusError{Code: 1006, Reason: "INTERNAL_ERROR", Msg: "内部服务器错误", AffectStability: true, Cause: *fs.PathError("open a.txt: no such file or directory")}
+func (w *withStatus) GoString() string {
+	return goString(w.status, w.annotation, w.cause)
+}
+
+// goString 生成 GoString 的输出，扩展信息按 key 排序
+// 消息、扩展信息与底层错误都经过 Scrubber 处理，%#v 常被直接写入日志.
+func goString(e *statusError, annotation string, cause error) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "errors.StatusError{Code: %d", e.statusCode)
+	if reason := e.Reason(); reason != "" {
+		fmt.Fprintf(&b, ", Reason: %q", reason)
+	}
+	fmt.Fprintf(&b, ", Msg: %q", ScrubbedMsg(e))
+	if e.ext.Retryable {
+		b.WriteString(", Retryable: true")
+	}
+	if e.IsAffectStability() {
+		b.WriteString(", AffectStability: true")
+	}
+
+	keys := make([]string, 0, len(e.ext.Extra))
+	values := make(map[string]string, len(e.ext.Extra))
+	for k, v := range e.ext.Extra {
+		if k == "stack" {
+			continue
+		}
+		if v, keep := scrub(k, v); keep {
+			keys = append(keys, k)
+			values[k] = v
+		}
+	}
+	if len(keys) > 0 {
+		sort.Strings(keys)
+		b.WriteString(", Extra: map[string]string{")
+		for i, k := range keys {
+			if i > 0 {
+				b.WriteString(", ")
+			}
+			fmt.Fprintf(&b, "%q: %q", k, values[k])
+		}
+		b.WriteString("}")
+	}
+	if annotation != "" {
+		fmt.Fprintf(&b, ", Annotation: %q", annotation)
+	}
+	if cause != nil {
+		fmt.Fprintf(&b, ", Cause: %T(%q)", cause, scrubbedError(cause))
+	}
+	b.WriteString("}")
+	return b.String()
+}
@@ -0,0 +1,69 @@
+package errors_test
+
+import (
+	errstd "errors"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/go-anyway/framework-errors"
+)
+
+func TestGoString(t *testing.T) {
+	_, openErr := os.Open("/nonexistent/a.txt")
+
+	tests := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{
+			name: "statusError",
+			err:  errors.NewStatusError(errors.CodeNotFound, "", map[string]string{"id": "42", "tenant": "t1"}),
+			want: `errors.StatusError{Code: 1004, Reason: "NOT_FOUND", Msg: "资源未找到", Extra: map[string]string{"id": "42", "tenant": "t1"}}`,
+		},
+		{
+			name: "包装的错误",
+			err:  errors.WrapWithStatusOptions(openErr, errors.CodeInternalError, ""),
+			want: `errors.StatusError{Code: 1006, Reason: "INTERNAL_ERROR", Msg: "内部服务器错误", AffectStability: true, Cause: *fs.PathError("open /nonexistent/a.txt: no such file or directory")}`,
+		},
+		{
+			name: "注解与可重试",
+			err:  errors.Annotate(errors.NewWithStatus(errors.CodeRequestTimeout, "查询超时"), "while loading orders"),
+			want: `errors.StatusError{Code: 1007, Reason: "REQUEST_TIMEOUT", Msg: "while loading orders: 查询超时", Retryable: true, ` +
+				`Annotation: "while loading orders", Cause: *errors.withStatus("查询超时")}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := fmt.Sprintf("%#v", tt.err)
+			if got != tt.want {
+				t.Errorf("%%#v = %s, want %s", got, tt.want)
+			}
+			if strings.Contains(got, "stack") || strings.Contains(got, "0x") {
+				t.Errorf("%%#v 不应包含调用堆栈或指针: %s", got)
+			}
+		})
+	}
+}
+
+func TestGoStringScrubbed(t *testing.T) {
+	defer errors.ResetScrubbers()
+	errors.RegisterScrubber(func(key, value string) (string, bool) {
+		if key == "card" {
+			return "", false
+		}
+		return strings.ReplaceAll(value, "secret", "***"), true
+	})
+
+	err := errors.WrapWithStatusOptions(errstd.New("dial postgres://app:secret@db"), errors.CodeInternalError, "",
+		errors.Extra("dsn", "app:secret@db"), errors.Extra("card", "4111"))
+	got := fmt.Sprintf("%#v", err)
+	if strings.Contains(got, "secret") || strings.Contains(got, "4111") {
+		t.Errorf("%%#v = %s, 应经过 Scrubber 处理", got)
+	}
+	if !strings.Contains(got, `"dsn": "app:***@db"`) || !strings.Contains(got, `*errors.errorString("dial postgres://app:***@db")`) {
+		t.Errorf("%%#v = %s", got)
+	}
+}
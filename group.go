@@ -0,0 +1,182 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package errors
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Aggregate 是多个 StatusError 的集合，由 Group.Wait 返回
+// Aggregate 实现了 Unwrap() []error，errors.Is 与 errors.As 会依次检查其中的每个错误，
+// Translate 返回其中第一个错误.
+type Aggregate []StatusError
+
+// Error 实现 error 接口
+func (a Aggregate) Error() string {
+	if len(a) == 1 {
+		return a[0].Error()
+	}
+	msgs := make([]string, len(a))
+	for i, err := range a {
+		msgs[i] = err.Error()
+	}
+	return fmt.Sprintf("%d errors occurred: %s", len(a), strings.Join(msgs, "; "))
+}
+
+// Unwrap 返回其中的全部错误，用于 errors.Is 与 errors.As
+func (a Aggregate) Unwrap() []error {
+	errs := make([]error, len(a))
+	for i, err := range a {
+		errs[i] = err
+	}
+	return errs
+}
+
+// Group 与 golang.org/x/sync/errgroup.Group 用法相同，但 Wait 返回所有失败的 goroutine 的错误，
+// 每个错误都经过 Translate 转换并带有各自的调用堆栈；goroutine 中的 panic 被恢复并转换为 CodeInternalError.
+// 零值可以直接使用.
+//
+//	g, ctx := errors.GroupWithContext(ctx)
+//	for _, id := range ids {
+//		g.Go(func() error { return load(ctx, id) })
+//	}
+//	if err := g.Wait(); err != nil {
+//		return err // errors.Aggregate
+//	}
+type Group struct {
+	cancel func(error)
+
+	wg  sync.WaitGroup
+	sem chan struct{}
+
+	mu   sync.Mutex
+	next int
+	errs []groupError
+}
+
+// groupError 是某个 goroutine 的错误，index 是该 goroutine 启动的顺序
+type groupError struct {
+	index int
+	err   StatusError
+}
+
+// GroupWithContext 返回新的 Group 以及派生的 context，第一个 goroutine 失败时 context 被取消
+func GroupWithContext(ctx context.Context) (*Group, context.Context) {
+	ctx, cancel := context.WithCancelCause(ctx)
+	return &Group{cancel: cancel}, ctx
+}
+
+// SetLimit 限制同时运行的 goroutine 数量，n 为负数时不限制
+// 必须在调用 Go 之前设置.
+func (g *Group) SetLimit(n int) {
+	if n < 0 {
+		g.sem = nil
+		return
+	}
+	if len(g.sem) != 0 {
+		panic(fmt.Errorf("errors: modify limit while %v goroutines in the group are still active", len(g.sem)))
+	}
+	g.sem = make(chan struct{}, n)
+}
+
+// Go 在新的 goroutine 中执行 f，达到 SetLimit 的限制时阻塞直到有 goroutine 退出
+func (g *Group) Go(f func() error) {
+	if g.sem != nil {
+		g.sem <- struct{}{}
+	}
+	g.start(f)
+}
+
+// TryGo 在没有达到 SetLimit 的限制时在新的 goroutine 中执行 f，返回是否已启动
+func (g *Group) TryGo(f func() error) bool {
+	if g.sem != nil {
+		select {
+		case g.sem <- struct{}{}:
+		default:
+			return false
+		}
+	}
+	g.start(f)
+	return true
+}
+
+// Wait 等待所有 goroutine 退出，有 goroutine 失败时返回按启动顺序排列的 Aggregate
+func (g *Group) Wait() error {
+	g.wg.Wait()
+	if g.cancel != nil {
+		g.cancel(nil)
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if len(g.errs) == 0 {
+		return nil
+	}
+	sort.Slice(g.errs, func(i, j int) bool { return g.errs[i].index < g.errs[j].index })
+	agg := make(Aggregate, len(g.errs))
+	for i, e := range g.errs {
+		agg[i] = e.err
+	}
+	return agg
+}
+
+// start 启动 goroutine 执行 f，记录其错误与 panic
+func (g *Group) start(f func() error) {
+	g.mu.Lock()
+	index := g.next
+	g.next++
+	g.mu.Unlock()
+
+	g.wg.Add(1)
+	go func() {
+		defer g.done()
+		var err StatusError
+		func() {
+			defer func() {
+				if rec := recover(); rec != nil {
+					err = panicError(rec)
+				}
+			}()
+			if e := f(); e != nil {
+				err = Translate(e)
+			}
+		}()
+		if err == nil {
+			return
+		}
+
+		g.mu.Lock()
+		first := len(g.errs) == 0
+		g.errs = append(g.errs, groupError{index: index, err: err})
+		g.mu.Unlock()
+		if first && g.cancel != nil {
+			g.cancel(err)
+		}
+	}()
+}
+
+// done 释放 SetLimit 的名额并标记 goroutine 退出
+func (g *Group) done() {
+	if g.sem != nil {
+		<-g.sem
+	}
+	g.wg.Done()
+}
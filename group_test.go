@@ -0,0 +1,101 @@
+package errors_test
+
+import (
+	"context"
+	errstd "errors"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/go-anyway/framework-errors"
+)
+
+func TestGroup(t *testing.T) {
+	boom := errstd.New("boom")
+	g, ctx := errors.GroupWithContext(context.Background())
+
+	release := make(chan struct{})
+	g.Go(func() error {
+		<-release
+		return errors.NewWithStatus(errors.CodeNotFound, "")
+	})
+	g.Go(func() error { return nil })
+	g.Go(func() error {
+		<-release
+		panic("nil map")
+	})
+	g.Go(func() error {
+		defer close(release)
+		return boom
+	})
+
+	err := g.Wait()
+	if ctx.Err() == nil {
+		t.Error("第一个错误后 context 应被取消")
+	}
+
+	var agg errors.Aggregate
+	if !errstd.As(err, &agg) || len(agg) != 3 {
+		t.Fatalf("Wait() = %v, want Aggregate of 3 errors", err)
+	}
+	wantCodes := []int32{errors.CodeNotFound, errors.CodeInternalError, errors.CodeInternalError}
+	for i, se := range agg {
+		if se.Code() != wantCodes[i] {
+			t.Errorf("agg[%d].Code() = %d, want %d", i, se.Code(), wantCodes[i])
+		}
+		if se.Extra()["stack"] == "" {
+			t.Errorf("agg[%d] 缺少调用堆栈", i)
+		}
+	}
+	if agg[1].Extra()["panic"] != "true" || !strings.Contains(agg[1].Error(), "nil map") {
+		t.Errorf("agg[1] = %v, want recovered panic", agg[1])
+	}
+	if !errstd.Is(err, boom) {
+		t.Error("errors.Is(err, boom) = false")
+	}
+	if got := errors.Translate(err); got.Code() != errors.CodeNotFound {
+		t.Errorf("Translate(err).Code() = %d, want %d", got.Code(), errors.CodeNotFound)
+	}
+	if !strings.HasPrefix(err.Error(), "3 errors occurred: ") {
+		t.Errorf("Error() = %q", err.Error())
+	}
+}
+
+func TestGroupNoError(t *testing.T) {
+	var g errors.Group
+	g.Go(func() error { return nil })
+	if err := g.Wait(); err != nil {
+		t.Errorf("Wait() = %v, want nil", err)
+	}
+}
+
+func TestGroupSetLimit(t *testing.T) {
+	var g errors.Group
+	g.SetLimit(2)
+
+	var active, peak atomic.Int32
+	block := make(chan struct{})
+	for i := 0; i < 2; i++ {
+		g.Go(func() error {
+			n := active.Add(1)
+			for {
+				if p := peak.Load(); n <= p || peak.CompareAndSwap(p, n) {
+					break
+				}
+			}
+			<-block
+			active.Add(-1)
+			return nil
+		})
+	}
+	if g.TryGo(func() error { return nil }) {
+		t.Error("TryGo() = true, want false when limit reached")
+	}
+	close(block)
+	for i := 0; i < 4; i++ {
+		g.Go(func() error { return nil })
+	}
+	if err := g.Wait(); err != nil || peak.Load() > 2 {
+		t.Errorf("Wait() = %v, peak = %d", err, peak.Load())
+	}
+}
@@ -0,0 +1,410 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package errors
+
+import (
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// DetailEncoding 是 ToGRPCStatus 编码 status details 的方式
+type DetailEncoding int32
+
+const (
+	// DetailEncodingStruct 使用 structpb.Struct 编码业务错误信息，默认值
+	DetailEncodingStruct DetailEncoding = iota
+	// DetailEncodingBinary 使用 errdetails.ErrorInfo 编码业务错误信息
+	// Reason 为业务错误码，Metadata 为扩展信息，相比 structpb 体积显著减小，
+	// 适用于每分钟返回大量业务错误的服务. FromGRPCStatus 始终同时支持两种编码.
+	DetailEncodingBinary
+	// DetailEncodingCanonical 是规范编码，保证 ToGRPCStatus 后再 FromGRPCStatus 无损还原
+	// 错误码、消息、扩展信息（包括参考编号）、是否影响稳定性与是否可重试，即使接收方没有注册该错误码，
+	// 适用于经过多跳转发错误的服务. 在 structpb 编码的基础上显式携带两个标记，且不受 SetDetailBudget 裁剪.
+	// 脱敏（Scrubber）、扩展信息白名单、InternalExtra、StackMode 等有意删除信息的策略仍然生效，
+	// 接收方对不可信输入的限制（扩展信息的数量与长度、消息长度、合法的 UTF-8）同样适用；
+	// 附加的本地化消息（LocalizedMessage）会覆盖业务消息.
+	DetailEncodingCanonical
+)
+
+// ErrorInfoDomain 是二进制编码时 errdetails.ErrorInfo 的 Domain
+const ErrorInfoDomain = "errors.go-anyway"
+
+// errorInfoCodeKey 是 errdetails.ErrorInfo 的 Reason 为错误原因时业务错误码在 Metadata 中的 key
+const errorInfoCodeKey = "business_code"
+
+var detailEncoding atomic.Int32
+
+// SetDetailEncoding 设置 ToGRPCStatus 使用的 details 编码方式
+// 切换前应确认所有调用方已升级到支持该编码的版本.
+func SetDetailEncoding(enc DetailEncoding) {
+	detailEncoding.Store(int32(enc))
+	grpcStatusCache.Clear()
+}
+
+// GetDetailEncoding 获取 ToGRPCStatus 使用的 details 编码方式
+func GetDetailEncoding() DetailEncoding {
+	return DetailEncoding(detailEncoding.Load())
+}
+
+// ExtraTruncatedKey 是扩展信息因超出 details 大小限制被裁剪时添加的标记
+const ExtraTruncatedKey = "extra_truncated"
+
+var (
+	detailBudget atomic.Int64
+
+	extraPrioritiesMu sync.RWMutex
+	// extraPriorities 是扩展信息的优先级，未注册的 key 优先级为 0
+	extraPriorities = map[string]int{
+		"stack": -100,
+	}
+)
+
+// SetDetailBudget 设置 ToGRPCStatus 序列化后 details 的最大字节数，0 表示不限制（默认）
+// 超出限制时按优先级从低到高丢弃扩展信息（同一优先级先丢弃较大的值），
+// 并添加 extra_truncated=true 标记，避免 gRPC 因响应头过大而导致整个响应失败.
+func SetDetailBudget(maxBytes int) {
+	detailBudget.Store(int64(maxBytes))
+}
+
+// RegisterExtraPriority 注册扩展信息的优先级，超出 details 大小限制时优先级低的先被丢弃
+// 未注册的 key 优先级为 0，"stack" 的默认优先级为 -100.
+//
+//	errors.RegisterExtraPriority("request_id", 100)
+func RegisterExtraPriority(key string, priority int) {
+	extraPrioritiesMu.Lock()
+	defer extraPrioritiesMu.Unlock()
+	extraPriorities[key] = priority
+}
+
+var (
+	grpcCodesMu sync.RWMutex
+	// grpcCodes 是业务错误码到 gRPC code 的映射
+	grpcCodes = map[int32]codes.Code{
+		CodeInvalidParam:      codes.InvalidArgument,
+		CodeUnauthorized:      codes.Unauthenticated,
+		CodeForbidden:         codes.PermissionDenied,
+		CodeNotFound:          codes.NotFound,
+		CodeAlreadyExists:     codes.AlreadyExists,
+		CodeRequestTimeout:    codes.DeadlineExceeded,
+		CodeClientCanceled:    codes.Canceled,
+		CodeRateLimitExceeded: codes.ResourceExhausted,
+
+		CodeDependencyUnavailable: codes.Unavailable,
+		CodeDependencyTimeout:     codes.DeadlineExceeded,
+		CodeDependencyDNSFailure:  codes.Unavailable,
+		CodeDependencyTLSFailure:  codes.Unavailable,
+		CodeDependencyRejected:    codes.Unknown,
+		CodeDependencyBadResponse: codes.Internal,
+		CodeDependencyRateLimited: codes.ResourceExhausted,
+		CodeDependencyCircuitOpen: codes.Unavailable,
+	}
+	// businessCodes 是 gRPC code 到业务错误码的反向映射，
+	// 多个业务错误码映射到同一个 gRPC code 时，内置错误码在此显式指定，注册的错误码以最先注册的为准
+	businessCodes = map[codes.Code]int32{
+		codes.InvalidArgument:   CodeInvalidParam,
+		codes.Unauthenticated:   CodeUnauthorized,
+		codes.PermissionDenied:  CodeForbidden,
+		codes.NotFound:          CodeNotFound,
+		codes.AlreadyExists:     CodeAlreadyExists,
+		codes.DeadlineExceeded:  CodeRequestTimeout,
+		codes.Canceled:          CodeClientCanceled,
+		codes.ResourceExhausted: CodeRateLimitExceeded,
+		codes.Unavailable:       CodeDependencyUnavailable,
+		codes.Internal:          CodeInternalError,
+	}
+
+	// grpcStatusCache 缓存只包含错误码和默认消息的 gRPC status，
+	// key 为 grpcStatusKey，value 为 *status.Status. status.Status 不可变，可以安全共享.
+	// 规范编码会序列化可重试与稳定性标记，因此二者也是 key 的一部分.
+	grpcStatusCache sync.Map
+)
+
+type grpcStatusKey struct {
+	code            int32
+	message         string
+	retryable       bool
+	affectStability bool
+}
+
+// statusCacheKey 返回 err 在 grpcStatusCache 中的 key
+func statusCacheKey(err StatusError) grpcStatusKey {
+	return grpcStatusKey{
+		code:            err.Code(),
+		message:         err.Msg(),
+		retryable:       IsRetryable(err),
+		affectStability: err.IsAffectStability(),
+	}
+}
+
+func cachedGRPCStatus(key grpcStatusKey) (*status.Status, bool) {
+	v, ok := grpcStatusCache.Load(key)
+	if !ok {
+		return nil, false
+	}
+	return v.(*status.Status), true
+}
+
+func storeGRPCStatus(key grpcStatusKey, st *status.Status) {
+	grpcStatusCache.Store(key, st)
+}
+
+// RegisterGRPCCode 注册业务错误码与 gRPC code 的映射
+// ToGRPCStatus 与 FromGRPCStatus 共用此映射表. 如果该 gRPC code 还没有对应的业务错误码，
+// 同时注册反向映射，否则 FromGRPCStatus 仍返回最先注册的业务错误码.
+//
+//	errors.RegisterGRPCCode(CodeOrderClosed, codes.FailedPrecondition)
+func RegisterGRPCCode(code int32, grpcCode codes.Code) {
+	grpcCodesMu.Lock()
+	defer grpcCodesMu.Unlock()
+	grpcCodes[code] = grpcCode
+	if _, ok := businessCodes[grpcCode]; !ok {
+		businessCodes[grpcCode] = code
+	}
+	grpcStatusCache.Clear()
+}
+
+// GRPCCode 获取业务错误码对应的 gRPC code，未定义时返回 codes.Internal
+func GRPCCode(code int32) codes.Code {
+	grpcCodesMu.RLock()
+	defer grpcCodesMu.RUnlock()
+	if grpcCode, ok := grpcCodes[code]; ok {
+		return grpcCode
+	}
+	if base, ok := baseCode(code); ok {
+		if grpcCode, ok := grpcCodes[base]; ok {
+			return grpcCode
+		}
+	}
+	return codes.Internal
+}
+
+// CodeFromGRPC 获取 gRPC code 对应的业务错误码，未定义时返回 CodeInternalError
+func CodeFromGRPC(grpcCode codes.Code) int32 {
+	grpcCodesMu.RLock()
+	defer grpcCodesMu.RUnlock()
+	if code, ok := businessCodes[grpcCode]; ok {
+		return code
+	}
+	return CodeInternalError
+}
+
+// structDetail 将业务错误信息编码为 structpb.Struct
+func structDetail(err StatusError) *anypb.Any {
+	errorInfo := map[string]interface{}{
+		"business_code": err.Code(),
+		"business_msg":  ScrubbedMsg(err),
+	}
+	if reason := Reason(err); reason != "" {
+		errorInfo["reason"] = reason
+	}
+	if GetDetailEncoding() == DetailEncodingCanonical {
+		errorInfo["retryable"] = IsRetryable(err)
+		errorInfo["affect_stability"] = err.IsAffectStability()
+	}
+
+	// 转换为 map[string]interface{} 以便使用 structpb
+	var extraMap map[string]interface{}
+	RangePublicExtra(err, func(k, v string) bool {
+		if extraMap == nil {
+			extraMap = make(map[string]interface{})
+		}
+		extraMap[k] = compressExtra(v)
+		return true
+	})
+	if len(extraMap) > 0 {
+		errorInfo["extra"] = extraMap
+	}
+
+	structValue, e := structpb.NewStruct(errorInfo)
+	if e != nil {
+		return nil
+	}
+	anyValue, e := marshalAny(structValue)
+	if e != nil {
+		return nil
+	}
+	return anyValue
+}
+
+// marshalAny 使用确定性序列化将 m 包装为 Any，map 按 key 排序
+func marshalAny(m proto.Message) (*anypb.Any, error) {
+	anyValue := new(anypb.Any)
+	if err := anypb.MarshalFrom(anyValue, m, proto.MarshalOptions{Deterministic: true}); err != nil {
+		return nil, err
+	}
+	return anyValue, nil
+}
+
+// errorInfoDetail 将业务错误信息编码为 errdetails.ErrorInfo
+// 业务消息与 status 的 message 相同，不再重复编码. 错误码定义了错误原因时 Reason 为错误原因，
+// 业务错误码记录在 Metadata["business_code"] 中，否则 Reason 为业务错误码.
+func errorInfoDetail(err StatusError) *errdetails.ErrorInfo {
+	info := &errdetails.ErrorInfo{
+		Reason: strconv.Itoa(int(err.Code())),
+		Domain: ErrorInfoDomain,
+	}
+	if reason := Reason(err); reason != "" {
+		info.Reason = reason
+		info.Metadata = map[string]string{errorInfoCodeKey: strconv.Itoa(int(err.Code()))}
+	}
+	RangePublicExtra(err, func(k, v string) bool {
+		if k == errorInfoCodeKey {
+			return true
+		}
+		if info.Metadata == nil {
+			info.Metadata = make(map[string]string)
+		}
+		info.Metadata[k] = compressExtra(v)
+		return true
+	})
+	return info
+}
+
+// encodeDetail 按当前的编码方式将业务错误信息编码为 detail
+func encodeDetail(err StatusError) proto.Message {
+	if GetDetailEncoding() == DetailEncodingBinary {
+		return errorInfoDetail(err)
+	}
+	if detail := structDetail(err); detail != nil {
+		return detail
+	}
+	return nil
+}
+
+// encodeDetailWithinBudget 编码 detail，超出 SetDetailBudget 设置的大小时裁剪扩展信息
+func encodeDetailWithinBudget(err StatusError) proto.Message {
+	detail := encodeDetail(err)
+	budget := int(detailBudget.Load())
+	if budget <= 0 || detail == nil || GetDetailEncoding() == DetailEncodingCanonical || proto.Size(detail) <= budget {
+		return detail
+	}
+
+	type entry struct {
+		key, value string
+		priority   int
+	}
+	var entries []entry
+	extraPrioritiesMu.RLock()
+	RangePublicExtra(err, func(k, v string) bool {
+		entries = append(entries, entry{key: k, value: v, priority: extraPriorities[k]})
+		return true
+	})
+	extraPrioritiesMu.RUnlock()
+
+	// 按丢弃顺序排序：优先级低的在前，同一优先级值较大的在前
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].priority != entries[j].priority {
+			return entries[i].priority < entries[j].priority
+		}
+		if len(entries[i].value) != len(entries[j].value) {
+			return len(entries[i].value) > len(entries[j].value)
+		}
+		return entries[i].key < entries[j].key
+	})
+
+	trimmed := &statusError{
+		statusCode: err.Code(),
+		message:    err.Msg(),
+		ext:        Extension{Extra: make(map[string]string, len(entries)+1)},
+	}
+	for _, e := range entries {
+		trimmed.ext.Extra[e.key] = e.value
+	}
+	trimmed.ext.Extra[ExtraTruncatedKey] = "true"
+
+	for _, e := range entries {
+		delete(trimmed.ext.Extra, e.key)
+		detail = encodeDetail(trimmed)
+		if detail == nil || proto.Size(detail) <= budget {
+			break
+		}
+	}
+	return detail
+}
+
+const (
+	// maxIncomingDetails 是 FromGRPCStatus 处理的 details 的最大数量
+	maxIncomingDetails = 32
+	// maxIncomingExtras 是 FromGRPCStatus 保留的扩展信息的最大数量
+	maxIncomingExtras = 128
+	// maxIncomingKeyLen 是扩展信息 key 的最大长度，超出的 key 会被丢弃
+	maxIncomingKeyLen = 256
+	// maxIncomingMessageLen 是错误消息的最大长度，超出部分会被截断
+	maxIncomingMessageLen = 16 << 10
+)
+
+// validBusinessCode 判断 details 中的 business_code 是否是合法的业务错误码
+func validBusinessCode(code float64) bool {
+	return code == math.Trunc(code) && code > 0 && code <= math.MaxInt32
+}
+
+// sanitizeIncomingExtra 解压并限制来自调用方的扩展信息，见 limitIncomingExtra
+func sanitizeIncomingExtra(extra map[string]string) map[string]string {
+	return limitIncomingExtra(extra, decompressExtra)
+}
+
+// limitIncomingExtra 限制来自调用方的扩展信息，decode 用于还原编码过的值（如解压），结果最多读取 limit 字节
+// 按 key 排序后保留前 maxIncomingExtras 个，与身份标识同名的 key 会被丢弃（见 UserID）.
+// 所有值还原后的总长度不超过 maxDecompressedExtraSize，超出时剩余的扩展信息被丢弃并添加 ExtraTruncatedKey.
+func limitIncomingExtra(extra map[string]string, decode func(v string, limit int) string) map[string]string {
+	if len(extra) == 0 {
+		return nil
+	}
+
+	keys := make([]string, 0, len(extra))
+	for k := range extra {
+		if k != "" && len(k) <= maxIncomingKeyLen && !isIdentityKey(k) {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	if len(keys) > maxIncomingExtras {
+		keys = keys[:maxIncomingExtras]
+	}
+
+	sanitized := make(map[string]string, len(keys))
+	budget := maxDecompressedExtraSize
+	for _, k := range keys {
+		if budget <= 0 {
+			sanitized[ExtraTruncatedKey] = "true"
+			break
+		}
+		v := truncateString(decode(extra[k], budget), budget)
+		budget -= len(v)
+		sanitized[k] = v
+	}
+	return sanitized
+}
+
+// truncateString 将 s 截断到不超过 n 字节，并保证结果是合法的 UTF-8
+func truncateString(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return strings.ToValidUTF8(s[:n], "")
+}
@@ -0,0 +1,495 @@
+package errors_test
+
+import (
+	"bytes"
+	"fmt"
+	"math/rand"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+	"testing/quick"
+	"time"
+	"unicode/utf8"
+
+	"github.com/go-anyway/framework-errors"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	spb "google.golang.org/genproto/googleapis/rpc/status"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+func TestFromGRPCStatusCodeMapping(t *testing.T) {
+	tests := []struct {
+		name     string
+		grpcCode codes.Code
+		want     int32
+	}{
+		{name: "ResourceExhausted", grpcCode: codes.ResourceExhausted, want: errors.CodeRateLimitExceeded},
+		{name: "DeadlineExceeded", grpcCode: codes.DeadlineExceeded, want: errors.CodeRequestTimeout},
+		{name: "Canceled", grpcCode: codes.Canceled, want: errors.CodeClientCanceled},
+		{name: "Unauthenticated", grpcCode: codes.Unauthenticated, want: errors.CodeUnauthorized},
+		{name: "未映射的 code", grpcCode: codes.DataLoss, want: errors.CodeInternalError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := errors.FromGRPCStatus(status.New(tt.grpcCode, "msg"))
+			if err.Code() != tt.want {
+				t.Errorf("Code() = %d, want %d", err.Code(), tt.want)
+			}
+		})
+	}
+}
+
+func TestTimeoutCodesRoundTrip(t *testing.T) {
+	tests := []struct {
+		name     string
+		code     int32
+		grpcCode codes.Code
+	}{
+		{name: "请求超时", code: errors.CodeRequestTimeout, grpcCode: codes.DeadlineExceeded},
+		{name: "请求已取消", code: errors.CodeClientCanceled, grpcCode: codes.Canceled},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			st := errors.ToGRPCStatus(errors.NewStatusError(tt.code, "", nil))
+			if st.Code() != tt.grpcCode {
+				t.Errorf("gRPC code = %v, want %v", st.Code(), tt.grpcCode)
+			}
+
+			// 没有业务错误码的 status（例如由 gRPC 框架产生）同样映射回超时类错误码
+			for _, s := range []*status.Status{st, status.New(tt.grpcCode, "context deadline exceeded")} {
+				se := errors.FromGRPCStatus(s)
+				if se.Code() != tt.code {
+					t.Errorf("Code() = %d, want %d", se.Code(), tt.code)
+				}
+				if se.IsAffectStability() {
+					t.Error("调用方导致的超时与取消不应影响稳定性")
+				}
+			}
+		})
+	}
+}
+
+func TestGRPCCodeDefaults(t *testing.T) {
+	tests := []struct {
+		name     string
+		code     int32
+		grpcCode codes.Code
+		back     int32
+	}{
+		{name: "依赖不可用", code: errors.CodeDependencyUnavailable, grpcCode: codes.Unavailable, back: errors.CodeDependencyUnavailable},
+		{name: "依赖熔断", code: errors.CodeDependencyCircuitOpen, grpcCode: codes.Unavailable, back: errors.CodeDependencyUnavailable},
+		{name: "依赖超时", code: errors.CodeDependencyTimeout, grpcCode: codes.DeadlineExceeded, back: errors.CodeRequestTimeout},
+		{name: "依赖限流", code: errors.CodeDependencyRateLimited, grpcCode: codes.ResourceExhausted, back: errors.CodeRateLimitExceeded},
+		{name: "依赖响应无效", code: errors.CodeDependencyBadResponse, grpcCode: codes.Internal, back: errors.CodeInternalError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := errors.GRPCCode(tt.code); got != tt.grpcCode {
+				t.Errorf("GRPCCode() = %v, want %v", got, tt.grpcCode)
+			}
+			// 多个错误码共用同一个 gRPC code 时，反向映射固定为显式指定的错误码
+			if got := errors.CodeFromGRPC(tt.grpcCode); got != tt.back {
+				t.Errorf("CodeFromGRPC() = %d, want %d", got, tt.back)
+			}
+		})
+	}
+}
+
+func TestRegisterGRPCCode(t *testing.T) {
+	const codeOrderClosed int32 = 9101
+	const codeOrderLocked int32 = 9102
+	errors.RegisterGRPCCode(codeOrderClosed, codes.FailedPrecondition)
+	errors.RegisterGRPCCode(codeOrderLocked, codes.FailedPrecondition)
+
+	st := errors.ToGRPCStatus(errors.NewStatusError(codeOrderLocked, "订单已锁定", nil))
+	if st.Code() != codes.FailedPrecondition {
+		t.Errorf("gRPC code = %v, want %v", st.Code(), codes.FailedPrecondition)
+	}
+
+	// 反向映射以最先注册的为准
+	if got := errors.CodeFromGRPC(codes.FailedPrecondition); got != codeOrderClosed {
+		t.Errorf("CodeFromGRPC() = %d, want %d", got, codeOrderClosed)
+	}
+
+	// 携带业务错误码的 status 不受反向映射影响
+	if got := errors.FromGRPCStatus(st).Code(); got != codeOrderLocked {
+		t.Errorf("FromGRPCStatus().Code() = %d, want %d", got, codeOrderLocked)
+	}
+}
+
+func TestToGRPCStatusCache(t *testing.T) {
+	first := errors.ToGRPCStatus(errors.NewStatusError(errors.CodeUnauthorized, "", nil))
+	second := errors.ToGRPCStatus(errors.NewStatusError(errors.CodeUnauthorized, "", nil))
+	if first != second {
+		t.Error("只包含错误码的错误应复用缓存的 status")
+	}
+
+	custom := errors.ToGRPCStatus(errors.NewStatusError(errors.CodeUnauthorized, "签名无效", nil))
+	if custom == first || custom.Message() != "签名无效" {
+		t.Errorf("自定义消息不应使用缓存, Message() = %s", custom.Message())
+	}
+
+	withExtra := errors.ToGRPCStatus(errors.NewWithStatus(errors.CodeUnauthorized, "", errors.Extra("uid", "42")))
+	if withExtra == first || errors.FromGRPCStatus(withExtra).Extra()["uid"] != "42" {
+		t.Error("带扩展信息的错误不应使用缓存")
+	}
+
+	if got := errors.FromGRPCStatus(second).Code(); got != errors.CodeUnauthorized {
+		t.Errorf("FromGRPCStatus().Code() = %d, want %d", got, errors.CodeUnauthorized)
+	}
+}
+
+func TestGRPCStatusSingleDetail(t *testing.T) {
+	err := errors.NewStatusError(errors.CodeNotFound, "订单不存在", map[string]string{"order_id": "42"})
+	st := errors.ToGRPCStatus(err)
+	if len(st.Details()) != 1 {
+		t.Fatalf("len(Details()) = %d, want 1", len(st.Details()))
+	}
+
+	got := errors.FromGRPCStatus(st)
+	if got.Code() != errors.CodeNotFound || got.Msg() != "订单不存在" || got.Extra()["order_id"] != "42" {
+		t.Errorf("FromGRPCStatus() = %d %s %v", got.Code(), got.Msg(), got.Extra())
+	}
+}
+
+func TestFromGRPCStatusLegacyDetails(t *testing.T) {
+	// 旧版本分别使用两个 detail 传递扩展信息和业务错误信息
+	st := status.New(codes.NotFound, "订单不存在")
+	extra, _ := structpb.NewStruct(map[string]interface{}{"order_id": "42"})
+	info, _ := structpb.NewStruct(map[string]interface{}{"business_code": errors.CodeNotFound, "business_msg": "订单不存在"})
+	extraAny, _ := anypb.New(extra)
+	infoAny, _ := anypb.New(info)
+	st, _ = st.WithDetails(extraAny, infoAny)
+
+	got := errors.FromGRPCStatus(st)
+	if got.Code() != errors.CodeNotFound || got.Msg() != "订单不存在" || got.Extra()["order_id"] != "42" {
+		t.Errorf("FromGRPCStatus() = %d %s %v", got.Code(), got.Msg(), got.Extra())
+	}
+}
+
+func TestDetailEncodingBinary(t *testing.T) {
+	err := errors.NewStatusError(errors.CodeNotFound, "订单不存在", map[string]string{"order_id": "42", "tenant": "t1"})
+	structSize := proto.Size(errors.ToGRPCStatus(err).Proto())
+
+	errors.SetDetailEncoding(errors.DetailEncodingBinary)
+	defer errors.SetDetailEncoding(errors.DetailEncodingStruct)
+
+	st := errors.ToGRPCStatus(err)
+	if _, ok := st.Details()[0].(*errdetails.ErrorInfo); !ok {
+		t.Fatalf("Details()[0] = %T, want *errdetails.ErrorInfo", st.Details()[0])
+	}
+	if binarySize := proto.Size(st.Proto()); binarySize >= structSize {
+		t.Errorf("二进制编码大小 %d 应小于 structpb 编码大小 %d", binarySize, structSize)
+	}
+
+	got := errors.FromGRPCStatus(st)
+	if got.Code() != errors.CodeNotFound || got.Msg() != "订单不存在" || got.Extra()["order_id"] != "42" {
+		t.Errorf("FromGRPCStatus() = %d %s %v", got.Code(), got.Msg(), got.Extra())
+	}
+
+	// 缓存的 status 不受编码方式切换影响
+	cached := errors.ToGRPCStatus(errors.NewStatusError(errors.CodeForbidden, "", nil))
+	if _, ok := cached.Details()[0].(*errdetails.ErrorInfo); !ok {
+		t.Errorf("Details()[0] = %T, want *errdetails.ErrorInfo", cached.Details()[0])
+	}
+}
+
+func TestDetailBudget(t *testing.T) {
+	errors.SetDetailBudget(256)
+	defer errors.SetDetailBudget(0)
+	errors.RegisterExtraPriority("request_id", 100)
+
+	err := errors.NewWithStatus(errors.CodeInternalError, "",
+		errors.Extra("request_id", "req-1"),
+		errors.Extra("sql", strings.Repeat("SELECT 1;", 20)),
+		errors.Extra("table", "orders"),
+	)
+	st := errors.ToGRPCStatus(err)
+
+	size := 0
+	for _, d := range st.Proto().GetDetails() {
+		size += proto.Size(d)
+	}
+	if size > 300 {
+		t.Errorf("details 大小 = %d, 超出限制", size)
+	}
+
+	extra := errors.FromGRPCStatus(st).Extra()
+	if extra[errors.ExtraTruncatedKey] != "true" {
+		t.Errorf("Extra[%s] = %s, want true", errors.ExtraTruncatedKey, extra[errors.ExtraTruncatedKey])
+	}
+	if _, ok := extra["stack"]; ok {
+		t.Error("stack 优先级最低，应最先被丢弃")
+	}
+	if _, ok := extra["sql"]; ok {
+		t.Error("同一优先级应先丢弃较大的值")
+	}
+	if extra["request_id"] != "req-1" || extra["table"] != "orders" {
+		t.Errorf("Extra() = %v, 高优先级的扩展信息应被保留", extra)
+	}
+}
+
+func TestDetailBudgetNotExceeded(t *testing.T) {
+	errors.SetDetailBudget(4096)
+	defer errors.SetDetailBudget(0)
+
+	st := errors.ToGRPCStatus(errors.NewStatusError(errors.CodeNotFound, "", map[string]string{"id": "42"}))
+	extra := errors.FromGRPCStatus(st).Extra()
+	if _, ok := extra[errors.ExtraTruncatedKey]; ok || extra["id"] != "42" {
+		t.Errorf("Extra() = %v, 未超出限制时不应裁剪", extra)
+	}
+}
+
+func TestFromGRPCStatusUntrustedDetails(t *testing.T) {
+	tests := []struct {
+		name string
+		info map[string]interface{}
+		want int32
+	}{
+		{name: "负数错误码", info: map[string]interface{}{"business_code": -1}, want: errors.CodeNotFound},
+		{name: "小数错误码", info: map[string]interface{}{"business_code": 1001.5}, want: errors.CodeNotFound},
+		{name: "超出 int32 范围", info: map[string]interface{}{"business_code": 1 << 40}, want: errors.CodeNotFound},
+		{name: "合法错误码", info: map[string]interface{}{"business_code": errors.CodeUserNotFound}, want: errors.CodeUserNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			info, _ := structpb.NewStruct(tt.info)
+			infoAny, _ := anypb.New(info)
+			st, _ := status.New(codes.NotFound, "msg").WithDetails(infoAny)
+			if got := errors.FromGRPCStatus(st).Code(); got != tt.want {
+				t.Errorf("Code() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+
+	// 扩展信息数量和 key 长度受限
+	extra := make(map[string]interface{})
+	for i := 0; i < 1000; i++ {
+		extra[fmt.Sprintf("k%04d", i)] = "v"
+	}
+	extra[strings.Repeat("x", 1024)] = "v"
+	info, _ := structpb.NewStruct(map[string]interface{}{"business_code": errors.CodeNotFound, "extra": extra})
+	infoAny, _ := anypb.New(info)
+	st, _ := status.New(codes.NotFound, strings.Repeat("消", 10000)).WithDetails(infoAny)
+	got := errors.FromGRPCStatus(st)
+	if n := len(got.Extra()); n != 128 {
+		t.Errorf("len(Extra()) = %d, want 128", n)
+	}
+	if _, ok := got.Extra()["k0000"]; !ok {
+		t.Error("应按 key 排序保留扩展信息")
+	}
+	if len(got.Msg()) > 16<<10 || !utf8.ValidString(got.Msg()) {
+		t.Errorf("len(Msg()) = %d, 消息应被截断为合法的 UTF-8", len(got.Msg()))
+	}
+
+	if got := errors.FromGRPCStatus(nil); got.Code() != errors.CodeInternalError {
+		t.Errorf("FromGRPCStatus(nil).Code() = %d, want %d", got.Code(), errors.CodeInternalError)
+	}
+}
+
+func FuzzFromGRPCStatus(f *testing.F) {
+	seeds := []errors.StatusError{
+		errors.NewStatusError(errors.CodeNotFound, "", nil),
+		errors.NewStatusError(errors.CodeInternalError, "boom", map[string]string{"sql": "SELECT 1"}),
+		errors.AttachDetail(errors.NewStatusError(errors.CodeForbidden, "", nil), &errdetails.ErrorInfo{Reason: "x"}),
+	}
+	for _, se := range seeds {
+		data, _ := proto.Marshal(errors.ToGRPCStatus(se).Proto())
+		f.Add(data)
+	}
+	errors.SetDetailEncoding(errors.DetailEncodingBinary)
+	data, _ := proto.Marshal(errors.ToGRPCStatus(seeds[1]).Proto())
+	errors.SetDetailEncoding(errors.DetailEncodingStruct)
+	f.Add(data)
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		var pb spb.Status
+		if err := proto.Unmarshal(data, &pb); err != nil {
+			return
+		}
+		se := errors.FromGRPCStatus(status.FromProto(&pb))
+		if se == nil {
+			t.Fatal("FromGRPCStatus() = nil")
+		}
+		if se.Code() <= 0 {
+			t.Errorf("Code() = %d, 错误码应为正数", se.Code())
+		}
+		if n := len(se.Extra()); n > 128 {
+			t.Errorf("len(Extra()) = %d, 超出限制", n)
+		}
+		if len(se.Msg()) > 16<<10 {
+			t.Errorf("len(Msg()) = %d, 超出限制", len(se.Msg()))
+		}
+	})
+}
+
+func TestToGRPCStatusDeterministic(t *testing.T) {
+	for _, enc := range []errors.DetailEncoding{errors.DetailEncodingStruct, errors.DetailEncodingBinary} {
+		errors.SetDetailEncoding(enc)
+
+		var first []byte
+		for i := 0; i < 20; i++ {
+			extra := make(map[string]string)
+			for j := 0; j < 20; j++ {
+				extra[fmt.Sprintf("key%02d", j)] = strconv.Itoa(j)
+			}
+			data, err := proto.Marshal(errors.ToGRPCStatus(errors.NewStatusError(errors.CodeNotFound, "", extra)).Proto())
+			if err != nil {
+				t.Fatalf("proto.Marshal() error = %v", err)
+			}
+			if first == nil {
+				first = data
+			} else if !bytes.Equal(first, data) {
+				t.Fatalf("编码方式 %d: 相同的错误序列化结果不一致", enc)
+			}
+		}
+	}
+	errors.SetDetailEncoding(errors.DetailEncodingStruct)
+}
+
+// canonicalCase 是规范编码往返测试随机生成的错误
+type canonicalCase struct {
+	code      int32
+	msg       string
+	extra     map[string]string
+	retryable bool
+}
+
+// Generate 实现 quick.Generator 接口
+func (canonicalCase) Generate(r *rand.Rand, size int) reflect.Value {
+	codes := []int32{
+		errors.CodeInvalidParam, errors.CodeNotFound, errors.CodeConflict, errors.CodeInternalError,
+		errors.CodeRequestTimeout, errors.CodeRateLimitExceeded, 4000 + r.Int31n(1000),
+	}
+	c := canonicalCase{
+		code:      codes[r.Intn(len(codes))],
+		msg:       randomString(r, 1+r.Intn(size+1)),
+		retryable: r.Intn(2) == 0,
+	}
+	if n := r.Intn(8); n > 0 {
+		c.extra = make(map[string]string, n)
+		for i := 0; i < n; i++ {
+			c.extra["k_"+strconv.Itoa(i)] = randomString(r, r.Intn(size+1))
+		}
+	}
+	return reflect.ValueOf(c)
+}
+
+// randomString 生成由随机 Unicode 字符组成的字符串
+func randomString(r *rand.Rand, n int) string {
+	runes := make([]rune, n)
+	for i := range runes {
+		runes[i] = rune(r.Int31n(utf8.MaxRune + 1))
+	}
+	return string(runes)
+}
+
+func TestDetailEncodingCanonicalRoundTrip(t *testing.T) {
+	errors.SetDetailEncoding(errors.DetailEncodingCanonical)
+	defer errors.SetDetailEncoding(errors.DetailEncodingStruct)
+	errors.SetDetailBudget(64)
+	defer errors.SetDetailBudget(0)
+
+	roundTrip := func(c canonicalCase) bool {
+		opts := []errors.Option{errors.Retryable(c.retryable)}
+		for k, v := range c.extra {
+			opts = append(opts, errors.Extra(k, v))
+		}
+		err := errors.EnsureRefID(errors.NewWithStatus(c.code, c.msg, opts...))
+
+		// 经过多跳转发
+		got := err
+		for i := 0; i < 3; i++ {
+			got = errors.FromGRPCStatus(errors.ToGRPCStatus(got))
+		}
+
+		if got.Code() != err.Code() || got.Msg() != err.Msg() ||
+			errors.IsRetryable(got) != errors.IsRetryable(err) || got.IsAffectStability() != err.IsAffectStability() ||
+			errors.RefID(got) != errors.RefID(err) || !reflect.DeepEqual(got.Extra(), err.Extra()) {
+			t.Logf("sent %d %q %v retryable=%v stability=%v", err.Code(), err.Msg(), err.Extra(), errors.IsRetryable(err), err.IsAffectStability())
+			t.Logf("got  %d %q %v retryable=%v stability=%v", got.Code(), got.Msg(), got.Extra(), errors.IsRetryable(got), got.IsAffectStability())
+			return false
+		}
+		return true
+	}
+	if err := quick.Check(roundTrip, &quick.Config{MaxCount: 500}); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestDetailEncodingCanonicalCachedStatus(t *testing.T) {
+	errors.SetDetailEncoding(errors.DetailEncodingCanonical)
+	defer errors.SetDetailEncoding(errors.DetailEncodingStruct)
+
+	// 先缓存默认标记的 status，再发送只有标记不同的错误
+	_ = errors.ToGRPCStatus(errors.NewWithStatus(errors.CodeNotFound, ""))
+	_ = errors.ToGRPCStatus(errors.NewWithStatus(errors.CodeInternalError, ""))
+
+	tests := []struct {
+		name          string
+		err           func(t *testing.T) errors.StatusError
+		wantRetryable bool
+		wantStability bool
+	}{
+		{
+			name: "Retryable(true)",
+			err: func(*testing.T) errors.StatusError {
+				return errors.NewWithStatus(errors.CodeNotFound, "", errors.Retryable(true))
+			},
+			wantRetryable: true,
+		},
+		{
+			name: "维护窗口内的错误码",
+			err: func(t *testing.T) errors.StatusError {
+				cancel := errors.SetMaintenanceWindow(time.Now().Add(-time.Minute), time.Now().Add(time.Hour), errors.CodeInternalError)
+				t.Cleanup(cancel)
+				return errors.NewWithStatus(errors.CodeInternalError, "")
+			},
+		},
+		{
+			name: "默认标记",
+			err: func(*testing.T) errors.StatusError {
+				return errors.NewWithStatus(errors.CodeInternalError, "", errors.Retryable(false))
+			},
+			wantStability: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := errors.FromGRPCStatus(errors.ToGRPCStatus(tt.err(t)))
+			if errors.IsRetryable(got) != tt.wantRetryable || got.IsAffectStability() != tt.wantStability {
+				t.Errorf("retryable=%v stability=%v, want %v %v",
+					errors.IsRetryable(got), got.IsAffectStability(), tt.wantRetryable, tt.wantStability)
+			}
+		})
+	}
+}
+
+func TestDetailEncodingCanonicalTrustsCode(t *testing.T) {
+	errors.SetDetailEncoding(errors.DetailEncodingCanonical)
+	defer errors.SetDetailEncoding(errors.DetailEncodingStruct)
+
+	// 非规范编码中 CodeInternalError 会根据 gRPC code 重新映射
+	st := status.New(codes.NotFound, "boom")
+	info, _ := structpb.NewStruct(map[string]interface{}{
+		"business_code":    float64(errors.CodeInternalError),
+		"business_msg":     "boom",
+		"retryable":        true,
+		"affect_stability": false,
+	})
+	anyValue, _ := anypb.New(info)
+	st, _ = st.WithDetails(anyValue)
+
+	got := errors.FromGRPCStatus(st)
+	if got.Code() != errors.CodeInternalError || !errors.IsRetryable(got) || got.IsAffectStability() {
+		t.Errorf("got code=%d retryable=%v stability=%v", got.Code(), errors.IsRetryable(got), got.IsAffectStability())
+	}
+}
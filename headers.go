@@ -0,0 +1,123 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package errors
+
+import (
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// 消息头编码使用的 key
+const (
+	HeaderErrorCode           = "X-Error-Code"
+	HeaderErrorReason         = "X-Error-Reason"
+	HeaderErrorMsg            = "X-Error-Msg"
+	HeaderErrorRetryable      = "X-Error-Retryable"
+	HeaderErrorIdempotentSafe = "X-Error-Idempotent-Safe"
+	HeaderErrorFingerprint    = "X-Error-Fingerprint"
+	HeaderErrorRetryCount     = "X-Error-Retry-Count"
+	HeaderErrorExtraPrefix    = "X-Error-Extra-"
+)
+
+// EncodeHeaders 将错误编码为与传输层无关的消息头，可用于 NATS、RabbitMQ、HTTP 等
+// 无法携带 gRPC status 的异步链路. 消息和扩展信息的值经过百分号编码，保证是合法的 HTTP 头.
+// 非 StatusError 会先经过 Translate 转换，调用堆栈不会被编码.
+func EncodeHeaders(err error) map[string]string {
+	if err == nil {
+		return nil
+	}
+
+	se := Translate(err)
+	headers := map[string]string{
+		HeaderErrorCode:           strconv.Itoa(int(se.Code())),
+		HeaderErrorMsg:            url.PathEscape(ScrubbedMsg(se)),
+		HeaderErrorRetryable:      strconv.FormatBool(IsRetryable(se)),
+		HeaderErrorIdempotentSafe: strconv.FormatBool(IsIdempotentSafe(se)),
+		HeaderErrorFingerprint:    Fingerprint(err),
+	}
+	if reason := Reason(se); reason != "" {
+		headers[HeaderErrorReason] = reason
+	}
+	RangePublicExtra(se, func(k, v string) bool {
+		switch k {
+		case "stack", IdempotentSafeKey:
+		case "retry_attempts":
+			headers[HeaderErrorRetryCount] = v
+		default:
+			headers[HeaderErrorExtraPrefix+k] = url.PathEscape(v)
+		}
+		return true
+	})
+	return headers
+}
+
+// DecodeHeaders 从消息头中还原 StatusError，没有错误码时返回 nil
+// key 的匹配不区分大小写（兼容 HTTP 头的规范化），因此扩展信息的 key 会被转换为小写.
+// 指纹和重试次数分别记录在 Extra["fingerprint"] 与 Extra["retry_attempts"] 中.
+// 消息与扩展信息的数量和长度限制与 FromGRPCStatus 相同.
+func DecodeHeaders(headers map[string]string) StatusError {
+	lower := make(map[string]string, len(headers))
+	for k, v := range headers {
+		lower[strings.ToLower(k)] = v
+	}
+
+	code, err := strconv.ParseInt(lower[strings.ToLower(HeaderErrorCode)], 10, 32)
+	if err != nil {
+		return nil
+	}
+
+	msg, err := url.PathUnescape(lower[strings.ToLower(HeaderErrorMsg)])
+	if err != nil {
+		msg = lower[strings.ToLower(HeaderErrorMsg)]
+	}
+	msg = truncateString(msg, maxIncomingMessageLen)
+
+	var opts []Option
+	if v, err := strconv.ParseBool(lower[strings.ToLower(HeaderErrorRetryable)]); err == nil {
+		opts = append(opts, Retryable(v))
+	}
+	if v, err := strconv.ParseBool(lower[strings.ToLower(HeaderErrorIdempotentSafe)]); err == nil &&
+		v != GetCodeDefinition(int32(code)).IdempotentSafe {
+		opts = append(opts, IdempotentSafe(v))
+	}
+	if reason := remoteReason(int32(code), lower[strings.ToLower(HeaderErrorReason)]); reason != "" {
+		opts = append(opts, func(ws *withStatus) { ws.status.reason = reason })
+	}
+	if v := lower[strings.ToLower(HeaderErrorFingerprint)]; v != "" {
+		opts = append(opts, Extra("fingerprint", truncateString(v, maxIncomingKeyLen)))
+	}
+	if v := lower[strings.ToLower(HeaderErrorRetryCount)]; v != "" {
+		opts = append(opts, Extra("retry_attempts", truncateString(v, maxIncomingKeyLen)))
+	}
+
+	prefix := strings.ToLower(HeaderErrorExtraPrefix)
+	extra := make(map[string]string)
+	for k, v := range lower {
+		if key, ok := strings.CutPrefix(k, prefix); ok {
+			if unescaped, err := url.PathUnescape(v); err == nil {
+				v = unescaped
+			}
+			extra[key] = v
+		}
+	}
+	for k, v := range limitIncomingExtra(extra, truncateString) {
+		opts = append(opts, Extra(k, v))
+	}
+
+	return NewWithStatus(int32(code), msg, opts...)
+}
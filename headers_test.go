@@ -0,0 +1,97 @@
+package errors_test
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/go-anyway/framework-errors"
+)
+
+func TestEncodeDecodeHeaders(t *testing.T) {
+	err := errors.NewWithStatus(
+		errors.CodeUserNotFound,
+		"用户 {id} 不存在",
+		errors.Param("id", "42"),
+		errors.Extra("order_id", "a b/c"),
+	)
+
+	headers := errors.EncodeHeaders(err)
+	if headers[errors.HeaderErrorCode] != "2001" {
+		t.Errorf("%s = %s, want 2001", errors.HeaderErrorCode, headers[errors.HeaderErrorCode])
+	}
+	if _, ok := headers[errors.HeaderErrorExtraPrefix+"stack"]; ok {
+		t.Error("不应编码堆栈信息")
+	}
+
+	se := errors.DecodeHeaders(headers)
+	if se == nil {
+		t.Fatal("DecodeHeaders() = nil, want StatusError")
+	}
+	if se.Code() != errors.CodeUserNotFound {
+		t.Errorf("Code() = %d, want %d", se.Code(), errors.CodeUserNotFound)
+	}
+	if se.Msg() != "用户 42 不存在" {
+		t.Errorf("Msg() = %s, want 用户 42 不存在", se.Msg())
+	}
+	if se.Extra()["order_id"] != "a b/c" {
+		t.Errorf("Extra[order_id] = %s, want a b/c", se.Extra()["order_id"])
+	}
+	if se.Extra()["fingerprint"] != errors.Fingerprint(err) {
+		t.Errorf("Extra[fingerprint] = %s, want %s", se.Extra()["fingerprint"], errors.Fingerprint(err))
+	}
+}
+
+func TestDecodeHeadersFromHTTP(t *testing.T) {
+	h := http.Header{}
+	for k, v := range errors.EncodeHeaders(errors.NewWithStatus(errors.CodeForbidden, "", errors.Extra("scope", "admin"))) {
+		h.Set(k, v)
+	}
+
+	flat := make(map[string]string, len(h))
+	for k := range h {
+		flat[k] = h.Get(k)
+	}
+
+	se := errors.DecodeHeaders(flat)
+	if se.Code() != errors.CodeForbidden {
+		t.Errorf("Code() = %d, want %d", se.Code(), errors.CodeForbidden)
+	}
+	if se.Extra()["scope"] != "admin" {
+		t.Errorf("Extra[scope] = %s, want admin", se.Extra()["scope"])
+	}
+}
+
+func TestDecodeHeadersWithoutCode(t *testing.T) {
+	if se := errors.DecodeHeaders(map[string]string{"X-Trace-Id": "abc"}); se != nil {
+		t.Errorf("DecodeHeaders() = %v, want nil", se)
+	}
+	if errors.EncodeHeaders(nil) != nil {
+		t.Error("EncodeHeaders(nil) 应返回 nil")
+	}
+}
+
+func TestDecodeHeadersLimits(t *testing.T) {
+	headers := map[string]string{
+		errors.HeaderErrorCode: "1004",
+		errors.HeaderErrorMsg:  strings.Repeat("m", 64<<10),
+	}
+	for i := 0; i < 1000; i++ {
+		headers[fmt.Sprintf("%sk%04d", errors.HeaderErrorExtraPrefix, i)] = "v"
+	}
+	headers[errors.HeaderErrorExtraPrefix+strings.Repeat("x", 1024)] = "long key"
+
+	se := errors.DecodeHeaders(headers)
+	if len(se.Msg()) > 16<<10 {
+		t.Errorf("len(Msg()) = %d, 应被截断", len(se.Msg()))
+	}
+	if n := len(se.Extra()); n > 130 {
+		t.Errorf("len(Extra()) = %d, 扩展信息数量应受限制", n)
+	}
+	for k := range se.Extra() {
+		if len(k) > 256 {
+			t.Errorf("超长的 key 应被丢弃: %d 字节", len(k))
+		}
+	}
+}
@@ -18,10 +18,13 @@ package errors
 
 import (
 	"context"
+	"fmt"
+	"sort"
 
 	"github.com/go-anyway/framework-log"
 
 	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
 )
 
 // ToGRPCError 将 StatusError 转换为 gRPC error
@@ -35,37 +38,144 @@ func ToGRPCError(err StatusError) error {
 
 // LogAndReturnError 记录错误日志并返回 gRPC error
 // 如果 err 是 StatusError，会自动记录包含错误码、消息和扩展信息的日志
-// logger 从 ctx 中通过 log.FromContext 获取
-func LogAndReturnError(ctx context.Context, err StatusError) error {
+// 影响稳定性的错误会生成参考编号（见 EnsureRefID），同时出现在日志与返回的错误中，
+// ctx 中的链路 ID 与请求 ID 会被添加到返回的错误的扩展信息中
+// logger 从 ctx 中通过 log.FromContext 获取，可以通过 LogOption 定制日志消息与字段
+func LogAndReturnError(ctx context.Context, err StatusError, opts ...LogOption) error {
 	if err == nil {
 		return nil
 	}
 
-	// 从 context 中获取 logger
+	ref := withLogContext(ctx, EnsureRefID(err))
+	logError(ctx, ref, opts...)
+	recordRefID(ctx, ref)
+	report(ctx, ref)
+	countError(ref)
+
+	// 转换为 gRPC error，转换后不再引用 err，可以归还对象池
+	grpcErr := ToGRPCError(ref)
+	Release(err)
+	return grpcErr
+}
+
+// 日志关联字段在扩展信息中的 key
+const (
+	TraceIDKey   = "trace_id"
+	RequestIDKey = "request_id"
+)
+
+// withLogContext 将 ctx 中 framework-log 的关联字段（链路 ID 与请求 ID）添加到 err 的扩展信息中
+// 返回给调用方的错误与日志携带相同的关联信息，err 中已有的值保持不变.
+func withLogContext(ctx context.Context, err StatusError) StatusError {
+	if ctx == nil {
+		return err
+	}
+	var opts []Option
+	if traceID := log.TraceIDFromContext(ctx); traceID != "" && !hasExtraKey(err, TraceIDKey) {
+		opts = append(opts, Extra(TraceIDKey, traceID))
+	}
+	if requestID := log.RequestIDFromContext(ctx); requestID != "" && !hasExtraKey(err, RequestIDKey) {
+		opts = append(opts, Extra(RequestIDKey, requestID))
+	}
+	if len(opts) == 0 {
+		return err
+	}
+	return With(err, opts...)
+}
+
+// hasExtraKey 判断 err 的扩展信息中是否包含 key
+func hasExtraKey(err StatusError, key string) bool {
+	found := false
+	RangeExtra(err, func(k, _ string) bool {
+		found = k == key
+		return !found
+	})
+	return found
+}
+
+// logError 记录包含错误码、消息和扩展信息的日志
+// 日志级别默认根据是否影响稳定性选择 Error 或 Warn，可以通过 SetLogLevel 等配置.
+func logError(ctx context.Context, err StatusError, opts ...LogOption) {
+	cfg := newLogConfig(opts)
+	if cfg.skip {
+		return
+	}
+	level, ok := logLevel(err)
+	if !ok {
+		return
+	}
+
+	// 从 context 中获取 logger，级别未开启时不构建日志字段
 	logger := log.FromContext(ctx)
+	msg := cfg.message
+	if msg == "" {
+		msg = "业务错误"
+		if err.IsAffectStability() {
+			msg = "业务错误（影响稳定性）"
+		}
+	}
+	ce := logger.Check(level, msg)
+	if ce == nil {
+		return
+	}
+
+	// 去重窗口内相同指纹的错误只记录一次
+	if logDedupWindow.Load() > 0 && !dedupLog(logger, level, err.Code(), Fingerprint(err)) {
+		return
+	}
 
 	// 构建日志字段
 	fields := []zap.Field{
 		zap.Int32("error_code", err.Code()),
-		zap.String("error_msg", err.Msg()),
+		zap.String("error_msg", ScrubbedMsg(err)),
 		zap.Bool("affect_stability", err.IsAffectStability()),
 	}
+	if ref := RefID(err); ref != "" {
+		fields = append(fields, zap.String(RefIDKey, ref))
+	}
+	if id := UserIDOf(err); id != "" {
+		fields = append(fields, zap.String(UserIDKey, id))
+	}
+	if id := TenantIDOf(err); id != "" {
+		fields = append(fields, zap.String(TenantIDKey, id))
+	}
+	if dep := DependencyOf(err); dep != "" {
+		fields = append(fields, zap.String(DependencyKey, dep))
+	}
 
-	// 添加扩展信息
-	extra := err.Extra()
-	if len(extra) > 0 {
-		fields = append(fields, zap.Any("extra", extra))
+	// 调用堆栈使用单独的结构化字段，避免在 extra 中记录过长的字符串
+	frames := StackFrames(err)
+	if len(frames) > 0 {
+		fields = append(fields, zap.Array("stack", stackFrames(frames)))
 	}
 
-	// 根据是否影响稳定性选择日志级别
-	if err.IsAffectStability() {
-		logger.Error("业务错误（影响稳定性）", fields...)
-	} else {
-		logger.Warn("业务错误", fields...)
+	// 添加扩展信息，直接遍历避免复制
+	if hasExtra(err) {
+		fields = append(fields, zap.Object("extra", zapcore.ObjectMarshalerFunc(func(enc zapcore.ObjectEncoder) error {
+			// 按 key 排序输出，保证日志内容稳定
+			var pairs [][2]string
+			rangeScrubbedExtra(func(fn func(k, v string) bool) { RangeExtra(err, fn) }, func(k, v string) bool {
+				if k != "stack" || len(frames) == 0 {
+					pairs = append(pairs, [2]string{k, v})
+				}
+				return true
+			})
+			sort.Slice(pairs, func(i, j int) bool { return pairs[i][0] < pairs[j][0] })
+			for _, p := range pairs {
+				enc.AddString(p[0], p[1])
+			}
+			return nil
+		})))
 	}
 
-	// 转换为 gRPC error
-	return ToGRPCError(err)
+	if cfg.causes {
+		if causes := Chain(err)[1:]; len(causes) > 0 {
+			fields = append(fields, zap.Array("causes", causeChain(causes)))
+		}
+	}
+	fields = append(fields, cfg.fields...)
+
+	ce.Write(fields...)
 }
 
 // WrapAndLogError 包装普通 error 为 StatusError，记录日志并返回 gRPC error
@@ -89,3 +199,42 @@ func NewAndLogError(ctx context.Context, code int32, message string, opts ...Opt
 	// 记录日志并返回
 	return LogAndReturnError(ctx, statusErr)
 }
+
+// causeChain 将错误链编码为结构化的数组，每一项包含错误的类型、消息以及错误码（如果有）
+// 便于在日志中按根因类型（例如 *pgconn.PgError）过滤. 消息与 error_msg 一样经过 Scrubber 处理.
+type causeChain []error
+
+// MarshalLogArray 实现 zapcore.ArrayMarshaler 接口
+func (c causeChain) MarshalLogArray(enc zapcore.ArrayEncoder) error {
+	for _, cause := range c {
+		if err := enc.AppendObject(zapcore.ObjectMarshalerFunc(func(enc zapcore.ObjectEncoder) error {
+			enc.AddString("type", fmt.Sprintf("%T", cause))
+			enc.AddString("message", scrubbedError(cause))
+			if se, ok := cause.(StatusError); ok {
+				enc.AddInt32("code", se.Code())
+			}
+			return nil
+		})); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// stackFrames 将调用堆栈编码为 {func, file, line} 对象的数组
+type stackFrames []Frame
+
+// MarshalLogArray 实现 zapcore.ArrayMarshaler 接口
+func (s stackFrames) MarshalLogArray(enc zapcore.ArrayEncoder) error {
+	for _, frame := range s {
+		if err := enc.AppendObject(zapcore.ObjectMarshalerFunc(func(enc zapcore.ObjectEncoder) error {
+			enc.AddString("func", frame.Func)
+			enc.AddString("file", frame.File)
+			enc.AddInt("line", frame.Line)
+			return nil
+		})); err != nil {
+			return err
+		}
+	}
+	return nil
+}
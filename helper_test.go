@@ -0,0 +1,50 @@
+package errors_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-anyway/framework-log"
+	"google.golang.org/grpc/status"
+
+	"github.com/go-anyway/framework-errors"
+)
+
+func TestLogContextBridging(t *testing.T) {
+	ctx := log.ContextWithTraceID(context.Background(), "trace-1")
+	ctx = log.ContextWithRequestID(ctx, "req-1")
+
+	tests := []struct {
+		name  string
+		extra func(err errors.StatusError) map[string]string
+	}{
+		{name: "gRPC", extra: func(err errors.StatusError) map[string]string {
+			st, _ := status.FromError(errors.LogAndReturnError(ctx, err))
+			return errors.FromGRPCStatus(st).Extra()
+		}},
+		{name: "HTTP", extra: func(err errors.StatusError) map[string]string {
+			rec := httptest.NewRecorder()
+			errors.WriteError(rec, httptest.NewRequest(http.MethodGet, "/", nil).WithContext(ctx), err)
+			var env errors.Envelope
+			_ = json.Unmarshal(rec.Body.Bytes(), &env)
+			return env.Extra
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			extra := tt.extra(errors.NewWithStatus(errors.CodeNotFound, ""))
+			if extra[errors.TraceIDKey] != "trace-1" || extra[errors.RequestIDKey] != "req-1" {
+				t.Errorf("Extra = %v, 应包含链路 ID 与请求 ID", extra)
+			}
+
+			// 上游传递的链路 ID 保持不变
+			extra = tt.extra(errors.NewWithStatus(errors.CodeNotFound, "", errors.Extra(errors.TraceIDKey, "upstream")))
+			if extra[errors.TraceIDKey] != "upstream" {
+				t.Errorf("Extra[trace_id] = %q, want upstream", extra[errors.TraceIDKey])
+			}
+		})
+	}
+}
@@ -0,0 +1,73 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+// Package hertzerr 为 CloudWeGo Hertz 提供错误渲染中间件.
+package hertzerr
+
+import (
+	"context"
+
+	"github.com/go-anyway/framework-errors"
+
+	"github.com/cloudwego/hertz/pkg/app"
+)
+
+// Middleware 返回一个 Hertz 中间件，在 handler 执行结束后渲染通过 c.Error 记录的最后一个错误
+// 错误会被转换为标准 JSON 结构（errors.Envelope），HTTP 状态码由 errors.HTTPStatus 决定，
+// 消息根据 Accept-Language 头进行本地化.
+//
+//	h := server.Default()
+//	h.Use(hertzerr.Middleware())
+func Middleware() app.HandlerFunc {
+	return func(ctx context.Context, c *app.RequestContext) {
+		c.Next(ctx)
+
+		last := c.Errors.Last()
+		if last == nil {
+			return
+		}
+		Render(c, last.Err)
+	}
+}
+
+// Abort 记录错误并中断后续 handler 的执行，由 Middleware 负责渲染
+//
+//	if err != nil {
+//		hertzerr.Abort(c, err)
+//		return
+//	}
+func Abort(c *app.RequestContext, err error) {
+	if err == nil {
+		return
+	}
+	_ = c.Error(err)
+	c.Abort()
+}
+
+// Render 立即将错误渲染为标准 JSON 结构
+func Render(c *app.RequestContext, err error) {
+	se := errors.Translate(err)
+	if se == nil {
+		return
+	}
+
+	locale := errors.MatchLocale(string(c.GetHeader("Accept-Language")))
+	env := errors.NewEnvelope(se)
+	env.Message = errors.Localize(se, locale)
+
+	c.Header("Content-Language", locale)
+	c.JSON(errors.HTTPStatus(se.Code()), env)
+}
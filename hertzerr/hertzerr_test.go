@@ -0,0 +1,68 @@
+package hertzerr_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/go-anyway/framework-errors"
+	"github.com/go-anyway/framework-errors/hertzerr"
+
+	"github.com/cloudwego/hertz/pkg/app"
+	"github.com/cloudwego/hertz/pkg/common/config"
+	"github.com/cloudwego/hertz/pkg/common/ut"
+	"github.com/cloudwego/hertz/pkg/route"
+)
+
+func newEngine() *route.Engine {
+	engine := route.NewEngine(config.NewOptions(nil))
+	engine.Use(hertzerr.Middleware())
+	engine.GET("/user", func(ctx context.Context, c *app.RequestContext) {
+		hertzerr.Abort(c, errors.NewWithStatus(errors.CodeUserNotFound, "", errors.Extra("user_id", "42")))
+	})
+	engine.GET("/ok", func(ctx context.Context, c *app.RequestContext) {
+		c.String(http.StatusOK, "ok")
+	})
+	return engine
+}
+
+func TestMiddleware(t *testing.T) {
+	w := ut.PerformRequest(newEngine(), http.MethodGet, "/user", nil)
+	resp := w.Result()
+
+	if resp.StatusCode() != http.StatusNotFound {
+		t.Errorf("status = %d, want %d", resp.StatusCode(), http.StatusNotFound)
+	}
+
+	var env errors.Envelope
+	if err := json.Unmarshal(resp.Body(), &env); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+	if env.Code != errors.CodeUserNotFound || env.Message != "用户不存在" {
+		t.Errorf("Envelope = %+v", env)
+	}
+	if env.Extra["user_id"] != "42" {
+		t.Errorf("Extra[user_id] = %s, want 42", env.Extra["user_id"])
+	}
+}
+
+func TestMiddlewareLocale(t *testing.T) {
+	w := ut.PerformRequest(newEngine(), http.MethodGet, "/user", nil,
+		ut.Header{Key: "Accept-Language", Value: "en-US,en;q=0.9"})
+
+	var env errors.Envelope
+	if err := json.Unmarshal(w.Result().Body(), &env); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+	if env.Message != "user not found" {
+		t.Errorf("Message = %s, want user not found", env.Message)
+	}
+}
+
+func TestMiddlewareNoError(t *testing.T) {
+	w := ut.PerformRequest(newEngine(), http.MethodGet, "/ok", nil)
+	if w.Result().StatusCode() != http.StatusOK || string(w.Result().Body()) != "ok" {
+		t.Errorf("response = %d %s", w.Result().StatusCode(), w.Result().Body())
+	}
+}
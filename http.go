@@ -0,0 +1,95 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package errors
+
+import "net/http"
+
+// HTTPStatusCodes 是业务错误码到 HTTP 状态码的映射
+// 业务可以在自己的包中通过 init() 函数向此 map 添加自定义的映射
+var HTTPStatusCodes = map[int32]int{
+	CodeSuccess:               http.StatusOK,
+	CodeInvalidParam:          http.StatusBadRequest,
+	CodeUnauthorized:          http.StatusUnauthorized,
+	CodeForbidden:             http.StatusForbidden,
+	CodeNotFound:              http.StatusNotFound,
+	CodeAlreadyExists:         http.StatusConflict,
+	CodeInternalError:         http.StatusInternalServerError,
+	CodeRequestTimeout:        http.StatusGatewayTimeout,
+	CodeConflict:              http.StatusConflict,
+	CodeCacheMiss:             http.StatusNotFound,
+	CodeClientCanceled:        499, // nginx 约定的 Client Closed Request
+	CodeUserNotFound:          http.StatusNotFound,
+	CodeUserAlreadyExist:      http.StatusConflict,
+	CodeRateLimitExceeded:     http.StatusTooManyRequests,
+	CodeTokenExpired:          http.StatusUnauthorized,
+	CodeDependencyUnavailable: http.StatusServiceUnavailable,
+	CodeDependencyTimeout:     http.StatusGatewayTimeout,
+	CodeDependencyDNSFailure:  http.StatusBadGateway,
+	CodeDependencyTLSFailure:  http.StatusBadGateway,
+	CodeDependencyRejected:    http.StatusBadGateway,
+	CodeDependencyBadResponse: http.StatusBadGateway,
+	CodeDependencyRateLimited: http.StatusServiceUnavailable,
+	CodeDependencyCircuitOpen: http.StatusServiceUnavailable,
+}
+
+// HTTPStatus 获取业务错误码对应的 HTTP 状态码，未定义时返回 500
+func HTTPStatus(code int32) int {
+	if status, ok := HTTPStatusCodes[code]; ok {
+		return status
+	}
+	if base, ok := baseCode(code); ok {
+		if status, ok := HTTPStatusCodes[base]; ok {
+			return status
+		}
+	}
+	return http.StatusInternalServerError
+}
+
+// Envelope 是 HTTP 接口返回错误时使用的标准 JSON 结构
+//
+//	{"code": 1004, "message": "资源未找到", "extra": {"id": "42"}}
+type Envelope struct {
+	Code    int32             `json:"code"`
+	Reason  string            `json:"reason,omitempty"`
+	Message string            `json:"message"`
+	Extra   map[string]string `json:"extra,omitempty"`
+	// Debug 只在开启调试模式时输出，见 Config.DebugDetails 与 WithDebugErrors
+	Debug *DebugInfo `json:"debug,omitempty"`
+}
+
+// NewEnvelope 根据 StatusError 构建标准 JSON 结构，调用堆栈不会被包含在内
+func NewEnvelope(err StatusError) Envelope {
+	if err == nil {
+		return Envelope{Code: CodeInternalError, Message: GetMessage(CodeInternalError, "")}
+	}
+
+	env := Envelope{Code: err.Code(), Reason: Reason(err), Message: ScrubbedMsg(err)}
+	RangePublicExtra(err, func(k, v string) bool {
+		if k == "stack" {
+			return true
+		}
+		if env.Extra == nil {
+			env.Extra = make(map[string]string)
+		}
+		env.Extra[k] = v
+		return true
+	})
+	if d, ok := err.(interface{ debugInfo() *DebugInfo }); ok {
+		env.Debug = d.debugInfo()
+	}
+	return env
+}
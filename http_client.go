@@ -0,0 +1,169 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package errors
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+)
+
+// maxErrorBodySize 是解析错误响应体时读取的最大字节数
+const maxErrorBodySize = 1 << 20
+
+// Transport 是一个 http.RoundTripper，将 4xx、5xx 响应转换为 StatusError
+// 使内部 HTTP 客户端可以像 gRPC 客户端一样拿到带错误码的错误.
+// 返回的错误会被 http.Client 包装为 *url.Error，可通过 errors.As 取出 StatusError.
+//
+//	client := &http.Client{Transport: &errors.Transport{}}
+type Transport struct {
+	// Base 是实际发送请求的 RoundTripper，为 nil 时使用 http.DefaultTransport
+	Base http.RoundTripper
+	// Decoder 用于解析第三方接口的错误响应体，为 nil 时使用标准的解析逻辑
+	Decoder *ResponseDecoder
+}
+
+// RoundTrip 实现 http.RoundTripper 接口
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	resp, err := base.RoundTrip(req)
+	if err != nil {
+		return nil, Translate(err)
+	}
+	check := CheckResponse
+	if t.Decoder != nil {
+		check = t.Decoder.CheckResponse
+	}
+	if se := check(resp); se != nil {
+		return nil, se
+	}
+	return resp, nil
+}
+
+// CheckResponse 检查 HTTP 响应，状态码小于 400 时返回 nil，重定向与 304 Not Modified 不视为错误
+// 4xx、5xx 时读取并关闭响应体，依次尝试解析标准 JSON 结构（Envelope）和
+// Problem Details（RFC 7807），都无法解析时根据 HTTP 状态码映射业务错误码.
+// Retry-After 响应头会被还原为建议的重试等待时间，见 BackoffHint；X-RateLimit-* 响应头会被还原为限流配额，见 RateLimit.
+func CheckResponse(resp *http.Response) StatusError {
+	if resp == nil {
+		return nil
+	}
+	if resp.StatusCode < http.StatusBadRequest {
+		return nil
+	}
+
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBodySize))
+	_ = resp.Body.Close()
+
+	return withRateLimitHeaders(withRetryAfterHeader(FromHTTPResponse(resp.StatusCode, body), resp.Header), resp.Header)
+}
+
+// httpErrorBody 同时兼容 Envelope 与 Problem Details 两种结构
+type httpErrorBody struct {
+	Code    int32                  `json:"code"`
+	Reason  string                 `json:"reason"`
+	Message string                 `json:"message"`
+	Extra   map[string]interface{} `json:"extra"`
+
+	// Problem Details（RFC 7807）字段
+	Type     string `json:"type"`
+	Title    string `json:"title"`
+	Status   int    `json:"status"`
+	Detail   string `json:"detail"`
+	Instance string `json:"instance"`
+}
+
+// FromHTTPResponse 将 HTTP 状态码与响应体解析为 StatusError，状态码小于 400 时返回 nil
+// 适用于包装第三方 REST 接口的调用结果. 响应体为标准 JSON 结构（Envelope）或
+// Problem Details 时使用其中的错误信息，否则根据 HTTP 状态码映射业务错误码，
+// 例如 404→CodeNotFound、429→CodeRateLimitExceeded、503→CodeDependencyUnavailable.
+// 消息与扩展信息的数量和长度限制与 FromGRPCStatus 相同.
+func FromHTTPResponse(status int, body []byte) StatusError {
+	if status < http.StatusBadRequest {
+		return nil
+	}
+
+	extra := map[string]interface{}{"http_status": strconv.Itoa(status)}
+
+	var b httpErrorBody
+	if len(body) > 0 && json.Unmarshal(body, &b) == nil {
+		bodyExtra := make(map[string]string, len(b.Extra))
+		for k, v := range b.Extra {
+			bodyExtra[k] = fmt.Sprintf("%v", v)
+		}
+		for k, v := range limitIncomingExtra(bodyExtra, truncateString) {
+			extra[k] = v
+		}
+
+		switch {
+		case b.Title != "" || b.Detail != "":
+			// Problem Details，code 作为扩展字段是可选的
+			code := b.Code
+			if code == 0 {
+				code = codeFromHTTPStatus(status)
+			}
+			message := b.Detail
+			if message == "" {
+				message = b.Title
+			}
+			if b.Type != "" {
+				extra["problem_type"] = truncateString(b.Type, maxIncomingMessageLen)
+			}
+			if b.Instance != "" {
+				extra["problem_instance"] = truncateString(b.Instance, maxIncomingMessageLen)
+			}
+			return NewStatusError(code, truncateString(message, maxIncomingMessageLen), extra)
+		case b.Code != 0:
+			// 标准 Envelope
+			se := NewStatusError(b.Code, truncateString(b.Message, maxIncomingMessageLen), extra).(*statusError)
+			se.reason = remoteReason(b.Code, b.Reason)
+			return se
+		}
+	}
+
+	return NewStatusError(codeFromHTTPStatus(status), "", extra)
+}
+
+// codeFromHTTPStatus 根据 HTTP 状态码推断业务错误码
+func codeFromHTTPStatus(status int) int32 {
+	switch status {
+	case http.StatusBadRequest, http.StatusUnprocessableEntity:
+		return CodeInvalidParam
+	case http.StatusUnauthorized:
+		return CodeUnauthorized
+	case http.StatusForbidden:
+		return CodeForbidden
+	case http.StatusNotFound:
+		return CodeNotFound
+	case http.StatusConflict:
+		return CodeConflict
+	case http.StatusTooManyRequests:
+		return CodeRateLimitExceeded
+	case http.StatusRequestTimeout, http.StatusGatewayTimeout:
+		return CodeRequestTimeout
+	case http.StatusBadGateway, http.StatusServiceUnavailable:
+		return CodeDependencyUnavailable
+	default:
+		return CodeInternalError
+	}
+}
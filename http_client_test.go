@@ -0,0 +1,202 @@
+package errors_test
+
+import (
+	"encoding/json"
+	errstd "errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-anyway/framework-errors"
+)
+
+func TestTransport(t *testing.T) {
+	tests := []struct {
+		name        string
+		status      int
+		contentType string
+		body        string
+		code        int32
+		msg         string
+	}{
+		{
+			name:        "标准 Envelope",
+			status:      http.StatusNotFound,
+			contentType: "application/json",
+			body:        `{"code":2001,"message":"用户不存在","extra":{"user_id":42}}`,
+			code:        errors.CodeUserNotFound,
+			msg:         "用户不存在",
+		},
+		{
+			name:        "Problem Details",
+			status:      http.StatusForbidden,
+			contentType: "application/problem+json",
+			body:        `{"type":"https://example.com/probs/forbidden","title":"Forbidden","status":403,"detail":"no access"}`,
+			code:        errors.CodeForbidden,
+			msg:         "no access",
+		},
+		{
+			name:   "无结构化响应体",
+			status: http.StatusTooManyRequests,
+			body:   "slow down",
+			code:   errors.CodeRateLimitExceeded,
+			msg:    "请求过于频繁",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if tt.contentType != "" {
+					w.Header().Set("Content-Type", tt.contentType)
+				}
+				w.WriteHeader(tt.status)
+				_, _ = w.Write([]byte(tt.body))
+			}))
+			defer srv.Close()
+
+			client := &http.Client{Transport: &errors.Transport{}}
+			resp, err := client.Get(srv.URL)
+			if resp != nil {
+				_ = resp.Body.Close()
+			}
+
+			var se errors.StatusError
+			if !errstd.As(err, &se) {
+				t.Fatalf("error = %v, want StatusError", err)
+			}
+			if se.Code() != tt.code {
+				t.Errorf("Code() = %d, want %d", se.Code(), tt.code)
+			}
+			if se.Msg() != tt.msg {
+				t.Errorf("Msg() = %s, want %s", se.Msg(), tt.msg)
+			}
+		})
+	}
+}
+
+func TestTransportSuccess(t *testing.T) {
+	tests := []struct {
+		name   string
+		status int
+	}{
+		{name: "200", status: http.StatusOK},
+		{name: "301 重定向", status: http.StatusMovedPermanently},
+		{name: "304 未修改", status: http.StatusNotModified},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Location", "/other")
+				w.WriteHeader(tt.status)
+			}))
+			defer srv.Close()
+
+			req, _ := http.NewRequest(http.MethodGet, srv.URL, nil)
+			resp, err := (&errors.Transport{}).RoundTrip(req)
+			if err != nil {
+				t.Fatalf("RoundTrip() error = %v", err)
+			}
+			defer resp.Body.Close()
+			if resp.StatusCode != tt.status {
+				t.Errorf("StatusCode = %d, want %d", resp.StatusCode, tt.status)
+			}
+		})
+	}
+}
+
+func TestCheckResponseExtra(t *testing.T) {
+	rec := httptest.NewRecorder()
+	rec.WriteHeader(http.StatusBadRequest)
+	_, _ = rec.WriteString(`{"code":1001,"message":"参数无效","extra":{"field":"name"}}`)
+
+	se := errors.CheckResponse(rec.Result())
+	extra := se.Extra()
+	if extra["field"] != "name" {
+		t.Errorf("Extra[field] = %s, want name", extra["field"])
+	}
+	if extra["http_status"] != "400" {
+		t.Errorf("Extra[http_status] = %s, want 400", extra["http_status"])
+	}
+}
+
+func TestFromHTTPResponse(t *testing.T) {
+	tests := []struct {
+		name   string
+		status int
+		body   string
+		want   int32
+	}{
+		{name: "404 无响应体", status: http.StatusNotFound, want: errors.CodeNotFound},
+		{name: "429 无响应体", status: http.StatusTooManyRequests, want: errors.CodeRateLimitExceeded},
+		{name: "503 非 JSON 响应体", status: http.StatusServiceUnavailable, body: "<html>busy</html>", want: errors.CodeDependencyUnavailable},
+		{name: "结构化响应体优先", status: http.StatusNotFound, body: `{"code":2001,"message":"用户不存在"}`, want: errors.CodeUserNotFound},
+		{name: "未知状态码", status: http.StatusTeapot, want: errors.CodeInternalError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			se := errors.FromHTTPResponse(tt.status, []byte(tt.body))
+			if se == nil {
+				t.Fatal("FromHTTPResponse() = nil")
+			}
+			if se.Code() != tt.want {
+				t.Errorf("Code() = %d, want %d", se.Code(), tt.want)
+			}
+		})
+	}
+
+	if se := errors.FromHTTPResponse(http.StatusNoContent, nil); se != nil {
+		t.Errorf("FromHTTPResponse(204) = %v, want nil", se)
+	}
+}
+
+func TestFromHTTPResponseLimits(t *testing.T) {
+	extra := make(map[string]string, 1000)
+	for i := 0; i < 1000; i++ {
+		extra[fmt.Sprintf("k%04d", i)] = "v"
+	}
+	body, _ := json.Marshal(map[string]interface{}{
+		"code":    errors.CodeNotFound,
+		"message": strings.Repeat("m", 64<<10),
+		"extra":   extra,
+	})
+
+	se := errors.FromHTTPResponse(http.StatusNotFound, body)
+	if len(se.Msg()) > 16<<10 {
+		t.Errorf("len(Msg()) = %d, 应被截断", len(se.Msg()))
+	}
+	if n := len(se.Extra()); n > 130 {
+		t.Errorf("len(Extra()) = %d, 扩展信息数量应受限制", n)
+	}
+
+	dec := &errors.ResponseDecoder{CodePath: "code", MessagePath: "message"}
+	if se = dec.Decode(http.StatusNotFound, body); len(se.Msg()) > 16<<10 {
+		t.Errorf("Decode() len(Msg()) = %d, 应被截断", len(se.Msg()))
+	}
+}
+
+func TestHTTPStatus(t *testing.T) {
+	if got := errors.HTTPStatus(errors.CodeNotFound); got != http.StatusNotFound {
+		t.Errorf("HTTPStatus() = %d, want %d", got, http.StatusNotFound)
+	}
+	if got := errors.HTTPStatus(99999); got != http.StatusInternalServerError {
+		t.Errorf("HTTPStatus() = %d, want %d", got, http.StatusInternalServerError)
+	}
+}
+
+func TestNewEnvelope(t *testing.T) {
+	env := errors.NewEnvelope(errors.NewWithStatus(errors.CodeNotFound, "", errors.Extra("id", "42")))
+	if env.Code != errors.CodeNotFound || env.Message != "资源未找到" {
+		t.Errorf("Envelope = %+v", env)
+	}
+	if _, ok := env.Extra["stack"]; ok {
+		t.Error("Envelope 不应包含堆栈信息")
+	}
+	if env.Extra["id"] != "42" {
+		t.Errorf("Extra[id] = %s, want 42", env.Extra["id"])
+	}
+}
@@ -0,0 +1,170 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package errors
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// ResponseDecoder 按字段路径从第三方或遗留 HTTP 接口的错误响应体中提取错误信息.
+// 路径使用 "." 分隔，数组下标使用数字，例如 "error.code"、"errors.0.message".
+//
+//	dec := &errors.ResponseDecoder{
+//		CodePath:    "errno",
+//		MessagePath: "errmsg",
+//		CodeMapping: map[string]int32{"40001": errors.CodeInvalidParam},
+//	}
+//	client := &http.Client{Transport: &errors.Transport{Decoder: dec}}
+type ResponseDecoder struct {
+	// CodePath 是错误码字段的路径
+	CodePath string
+	// MessagePath 是错误消息字段的路径
+	MessagePath string
+	// ExtraPaths 将响应体中的字段提取到 Extra，key 为 Extra 的键，value 为字段路径
+	ExtraPaths map[string]string
+	// CodeMapping 将第三方错误码（字符串形式）映射为业务错误码
+	// 未命中映射时，已定义的数字错误码原样使用，其他错误码根据 HTTP 状态码推断
+	CodeMapping map[string]int32
+}
+
+// CheckResponse 与包级别的 CheckResponse 相同，但使用 d 解析响应体
+func (d *ResponseDecoder) CheckResponse(resp *http.Response) StatusError {
+	if resp == nil {
+		return nil
+	}
+	if resp.StatusCode < http.StatusBadRequest {
+		return nil
+	}
+
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBodySize))
+	_ = resp.Body.Close()
+
+	return withRateLimitHeaders(withRetryAfterHeader(d.Decode(resp.StatusCode, body), resp.Header), resp.Header)
+}
+
+// Decode 将 HTTP 状态码与响应体解析为 StatusError，状态码小于 400 时返回 nil
+// 响应体不是 JSON 或找不到 CodePath 字段时，回退到标准的解析逻辑.
+// 消息与扩展信息的数量和长度限制与 FromGRPCStatus 相同.
+func (d *ResponseDecoder) Decode(status int, body []byte) StatusError {
+	if status < http.StatusBadRequest {
+		return nil
+	}
+	var doc interface{}
+	if d == nil || len(body) == 0 || json.Unmarshal(body, &doc) != nil {
+		return FromHTTPResponse(status, body)
+	}
+
+	rawCode, ok := lookupPath(doc, d.CodePath)
+	if !ok {
+		return FromHTTPResponse(status, body)
+	}
+
+	extra := map[string]interface{}{"http_status": strconv.Itoa(status)}
+	upstreamCode := truncateString(stringify(rawCode), maxIncomingKeyLen)
+
+	code, ok := d.CodeMapping[upstreamCode]
+	switch {
+	case ok:
+		extra["upstream_code"] = upstreamCode
+	case isDefinedCode(upstreamCode):
+		n, _ := strconv.ParseInt(upstreamCode, 10, 32)
+		code = int32(n)
+	default:
+		code = codeFromHTTPStatus(status)
+		extra["upstream_code"] = upstreamCode
+	}
+
+	var message string
+	if v, ok := lookupPath(doc, d.MessagePath); ok {
+		message = truncateString(stringify(v), maxIncomingMessageLen)
+	}
+
+	paths := make(map[string]string, len(d.ExtraPaths))
+	for key, path := range d.ExtraPaths {
+		if v, ok := lookupPath(doc, path); ok {
+			paths[key] = stringify(v)
+		}
+	}
+	for k, v := range limitIncomingExtra(paths, truncateString) {
+		extra[k] = v
+	}
+
+	return NewStatusError(code, message, extra)
+}
+
+// lookupPath 按 "." 分隔的路径在 JSON 文档中查找字段
+func lookupPath(doc interface{}, path string) (interface{}, bool) {
+	if path == "" {
+		return nil, false
+	}
+
+	cur := doc
+	for _, seg := range strings.Split(path, ".") {
+		switch node := cur.(type) {
+		case map[string]interface{}:
+			v, ok := node[seg]
+			if !ok {
+				return nil, false
+			}
+			cur = v
+		case []interface{}:
+			i, err := strconv.Atoi(seg)
+			if err != nil || i < 0 || i >= len(node) {
+				return nil, false
+			}
+			cur = node[i]
+		default:
+			return nil, false
+		}
+	}
+	return cur, cur != nil
+}
+
+// stringify 将 JSON 值转换为字符串，数字不使用科学计数法
+func stringify(v interface{}) string {
+	switch val := v.(type) {
+	case string:
+		return val
+	case float64:
+		return strconv.FormatFloat(val, 'f', -1, 64)
+	default:
+		return fmt.Sprintf("%v", val)
+	}
+}
+
+// isDefinedCode 判断 s 是否为已定义的业务错误码
+// 第三方接口自己的数字错误码可能与本服务的错误码重叠，只有已定义的错误码才能原样使用.
+func isDefinedCode(s string) bool {
+	n, err := strconv.ParseInt(s, 10, 32)
+	if err != nil {
+		return false
+	}
+	if _, ok := CodeDefinitions[int32(n)]; ok {
+		return true
+	}
+	base, ok := baseCode(int32(n))
+	if !ok {
+		return false
+	}
+	_, ok = CodeDefinitions[base]
+	return ok
+}
@@ -0,0 +1,126 @@
+package errors_test
+
+import (
+	errstd "errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-anyway/framework-errors"
+)
+
+func TestResponseDecoder(t *testing.T) {
+	dec := &errors.ResponseDecoder{
+		CodePath:    "error.errno",
+		MessagePath: "error.errmsg",
+		ExtraPaths:  map[string]string{"field": "error.details.0.field"},
+		CodeMapping: map[string]int32{"E_PARAM": errors.CodeInvalidParam},
+	}
+
+	tests := []struct {
+		name   string
+		status int
+		body   string
+		code   int32
+		msg    string
+		extra  map[string]string
+	}{
+		{
+			name:   "映射字符串错误码",
+			status: http.StatusBadRequest,
+			body:   `{"error":{"errno":"E_PARAM","errmsg":"bad name","details":[{"field":"name"}]}}`,
+			code:   errors.CodeInvalidParam,
+			msg:    "bad name",
+			extra:  map[string]string{"field": "name", "upstream_code": "E_PARAM"},
+		},
+		{
+			name:   "数字错误码原样使用",
+			status: http.StatusNotFound,
+			body:   `{"error":{"errno":2001,"errmsg":"no user"}}`,
+			code:   errors.CodeUserNotFound,
+			msg:    "no user",
+		},
+		{
+			name:   "未定义的数字错误码按状态码推断",
+			status: http.StatusTooManyRequests,
+			body:   `{"error":{"errno":429001,"errmsg":"slow down"}}`,
+			code:   errors.CodeRateLimitExceeded,
+			msg:    "slow down",
+			extra:  map[string]string{"upstream_code": "429001"},
+		},
+		{
+			name:   "未知字符串错误码按状态码推断",
+			status: http.StatusServiceUnavailable,
+			body:   `{"error":{"errno":"E_BUSY"}}`,
+			code:   errors.CodeDependencyUnavailable,
+			msg:    "依赖服务不可用",
+			extra:  map[string]string{"upstream_code": "E_BUSY"},
+		},
+		{
+			name:   "缺少错误码字段时回退",
+			status: http.StatusNotFound,
+			body:   `{"code":1004,"message":"资源未找到"}`,
+			code:   errors.CodeNotFound,
+			msg:    "资源未找到",
+		},
+		{
+			name:   "非 JSON 响应体",
+			status: http.StatusUnauthorized,
+			body:   `<html>denied</html>`,
+			code:   errors.CodeUnauthorized,
+			msg:    "未授权",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			se := dec.Decode(tt.status, []byte(tt.body))
+			if se == nil {
+				t.Fatal("Decode() = nil")
+			}
+			if se.Code() != tt.code {
+				t.Errorf("Code() = %d, want %d", se.Code(), tt.code)
+			}
+			if se.Msg() != tt.msg {
+				t.Errorf("Msg() = %s, want %s", se.Msg(), tt.msg)
+			}
+			for k, v := range tt.extra {
+				if se.Extra()[k] != v {
+					t.Errorf("Extra[%s] = %s, want %s", k, se.Extra()[k], v)
+				}
+			}
+		})
+	}
+}
+
+func TestResponseDecoderSuccess(t *testing.T) {
+	dec := &errors.ResponseDecoder{CodePath: "errno", MessagePath: "errmsg"}
+	for _, status := range []int{http.StatusOK, http.StatusCreated, http.StatusNotModified} {
+		if se := dec.Decode(status, []byte(`{"errno":0,"errmsg":"ok"}`)); se != nil {
+			t.Errorf("Decode(%d) = %v, want nil", status, se)
+		}
+	}
+}
+
+func TestTransportWithDecoder(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(`{"errno":1001,"errmsg":"参数错误"}`))
+	}))
+	defer srv.Close()
+
+	dec := &errors.ResponseDecoder{CodePath: "errno", MessagePath: "errmsg"}
+	client := &http.Client{Transport: &errors.Transport{Decoder: dec}}
+	resp, err := client.Get(srv.URL)
+	if resp != nil {
+		_ = resp.Body.Close()
+	}
+
+	var se errors.StatusError
+	if !errstd.As(err, &se) {
+		t.Fatalf("error = %v, want StatusError", err)
+	}
+	if se.Code() != errors.CodeInvalidParam || se.Msg() != "参数错误" {
+		t.Errorf("StatusError = (%d, %s), want (%d, 参数错误)", se.Code(), se.Msg(), errors.CodeInvalidParam)
+	}
+}
@@ -0,0 +1,90 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package errors
+
+import (
+	"net/http"
+)
+
+// HandlerFunc 是可以直接返回错误的 HTTP handler
+type HandlerFunc func(w http.ResponseWriter, r *http.Request) error
+
+// ServeHTTP 实现 http.Handler 接口，handler 返回错误时记录日志并渲染为标准 JSON 结构
+func (fn HandlerFunc) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if err := fn(w, r); err != nil {
+		WriteError(w, r, err)
+	}
+}
+
+// Handler 将返回错误的函数转换为 http.Handler，适用于 net/http、chi、gorilla/mux 等路由
+//
+//	r.Method("GET", "/users/{id}", errors.Handler(func(w http.ResponseWriter, r *http.Request) error {
+//		u, err := svc.GetUser(r.Context(), chi.URLParam(r, "id"))
+//		if err != nil {
+//			return err
+//		}
+//		return json.NewEncoder(w).Encode(u)
+//	}))
+func Handler(fn func(w http.ResponseWriter, r *http.Request) error) http.Handler {
+	return HandlerFunc(fn)
+}
+
+// Middleware 是与路由无关的 HTTP 中间件，捕获后续 handler 中的 panic，
+// 将其转换为带堆栈的 CodeInternalError 并渲染，可直接用于 chi 或 gorilla/mux 的 Use
+func Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		defer func() {
+			if rec := recover(); rec != nil {
+				if rec == http.ErrAbortHandler {
+					panic(rec)
+				}
+				WriteError(w, r, panicError(rec))
+			}
+		}()
+		next.ServeHTTP(w, r)
+	})
+}
+
+// WriteError 记录错误日志并通过 DefaultRenderer 渲染错误
+// 消息根据 LocaleMiddleware 解析的语言或 Accept-Language 头进行本地化，默认渲染为标准 JSON 结构（Envelope）.
+// 影响稳定性的错误会生成参考编号（见 EnsureRefID），同时出现在日志与响应中.
+// 请求的 context 开启了调试模式（见 WithDebugErrors）时附带诊断信息.
+func WriteError(w http.ResponseWriter, r *http.Request, err error) {
+	se := Translate(err)
+	if se == nil {
+		return
+	}
+	// 参考编号与原错误共享调用堆栈等信息，归还对象池的仍是原错误.
+	// 只归还 err 本身，Translate 从包装链中取出的错误仍被 err 引用
+	ref := withLogContext(r.Context(), EnsureRefID(se))
+	logError(r.Context(), ref)
+	recordRefID(r.Context(), ref)
+	report(r.Context(), ref)
+	countError(ref)
+	setObservedCode(r.Context(), ref.Code())
+
+	locale := LocaleFromContext(r.Context())
+	if locale == "" {
+		locale = MatchLocale(r.Header.Get("Accept-Language"))
+	}
+	w.Header().Set("Content-Language", locale)
+	setRetryAfterHeader(w.Header(), ref)
+	setRateLimitHeaders(w.Header(), ref)
+	setInsufficientScopeHeader(w.Header(), ref)
+	DefaultRenderer.Render(w, r, publicView(ref, locale, DebugErrorsFromContext(r.Context())))
+	Release(err)
+}
@@ -0,0 +1,68 @@
+package errors_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-anyway/framework-errors"
+)
+
+func TestHandler(t *testing.T) {
+	h := errors.Handler(func(w http.ResponseWriter, r *http.Request) error {
+		return errors.NewWithStatus(errors.CodeForbidden, "", errors.Extra("scope", "admin"))
+	})
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	if rec.Code != http.StatusForbidden {
+		t.Errorf("status = %d, want %d", rec.Code, http.StatusForbidden)
+	}
+	var env errors.Envelope
+	if err := json.Unmarshal(rec.Body.Bytes(), &env); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+	if env.Code != errors.CodeForbidden || env.Message != "禁止访问" {
+		t.Errorf("Envelope = %+v", env)
+	}
+	if env.Extra["scope"] != "admin" {
+		t.Errorf("Extra[scope] = %s, want admin", env.Extra["scope"])
+	}
+}
+
+func TestHandlerNoError(t *testing.T) {
+	h := errors.Handler(func(w http.ResponseWriter, r *http.Request) error {
+		w.WriteHeader(http.StatusNoContent)
+		return nil
+	})
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	if rec.Code != http.StatusNoContent {
+		t.Errorf("status = %d, want %d", rec.Code, http.StatusNoContent)
+	}
+}
+
+func TestMiddlewareRecoversPanic(t *testing.T) {
+	h := errors.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic("boom")
+	}))
+
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Accept-Language", "en")
+	h.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusInternalServerError {
+		t.Errorf("status = %d, want %d", rec.Code, http.StatusInternalServerError)
+	}
+	var env errors.Envelope
+	if err := json.Unmarshal(rec.Body.Bytes(), &env); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+	if env.Code != errors.CodeInternalError || env.Message != "internal server error" {
+		t.Errorf("Envelope = %+v", env)
+	}
+}
@@ -0,0 +1,180 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package errors
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// DefaultLocale 是 CodeDefinitions 中默认消息使用的语言
+var DefaultLocale = "zh-CN"
+
+var (
+	messagesMu sync.RWMutex
+	// messages 按语言存储错误码对应的消息，key 为小写的语言标签
+	messages = map[string]map[int32]string{
+		"en": {
+			CodeSuccess:               "success",
+			CodeInvalidParam:          "invalid parameter",
+			CodeUnauthorized:          "unauthorized",
+			CodeForbidden:             "forbidden",
+			CodeNotFound:              "resource not found",
+			CodeAlreadyExists:         "resource already exists",
+			CodeInternalError:         "internal server error",
+			CodeRequestTimeout:        "request timeout",
+			CodeConflict:              "resource conflict",
+			CodeCacheMiss:             "cache miss",
+			CodeClientCanceled:        "request canceled",
+			CodeUserNotFound:          "user not found",
+			CodeUserAlreadyExist:      "user already exists",
+			CodeRateLimitExceeded:     "too many requests",
+			CodeTokenExpired:          "token expired",
+			CodeDependencyUnavailable: "dependency unavailable",
+			CodeDependencyTimeout:     "dependency timeout",
+			CodeDependencyDNSFailure:  "dependency DNS resolution failed",
+			CodeDependencyTLSFailure:  "dependency TLS handshake failed",
+			CodeDependencyRejected:    "dependency rejected the request",
+			CodeDependencyBadResponse: "dependency returned an invalid response",
+			CodeDependencyRateLimited: "dependency rate limited",
+			CodeDependencyCircuitOpen: "dependency circuit open",
+		},
+	}
+)
+
+// RegisterMessages 注册某个语言下错误码对应的消息，已存在的消息会被覆盖
+// 业务可以在 init() 中为自定义错误码注册多语言消息.
+//
+//	errors.RegisterMessages("en", map[int32]string{CodeOrderClosed: "order closed"})
+func RegisterMessages(locale string, msgs map[int32]string) {
+	locale = strings.ToLower(locale)
+	messagesMu.Lock()
+	defer messagesMu.Unlock()
+	if messages[locale] == nil {
+		messages[locale] = make(map[int32]string, len(msgs))
+	}
+	for code, msg := range msgs {
+		messages[locale][code] = msg
+	}
+}
+
+// registeredLocales 返回注册了消息的语言，按字母顺序排列
+func registeredLocales() []string {
+	messagesMu.RLock()
+	defer messagesMu.RUnlock()
+	locales := make([]string, 0, len(messages))
+	for locale := range messages {
+		locales = append(locales, locale)
+	}
+	sort.Strings(locales)
+	return locales
+}
+
+// LocalizedMessage 获取错误码在指定语言下的消息
+// 依次尝试完整的语言标签（如 en-US）和主语言（如 en），都不存在时返回 false.
+func LocalizedMessage(code int32, locale string) (string, bool) {
+	locale = strings.ToLower(locale)
+	messagesMu.RLock()
+	defer messagesMu.RUnlock()
+
+	if msg, ok := localeMessage(locale, code); ok {
+		return msg, true
+	}
+	if base, ok := baseCode(code); ok {
+		return localeMessage(locale, base)
+	}
+	return "", false
+}
+
+// localeMessage 依次在完整的语言标签和主语言下查找消息，运行时覆盖的消息优先，调用方需持有 messagesMu
+func localeMessage(locale string, code int32) (string, bool) {
+	base, _, hasBase := strings.Cut(locale, "-")
+	for _, m := range []map[string]map[int32]string{messageOverrides, messages} {
+		if msg, ok := m[locale][code]; ok {
+			return msg, true
+		}
+		if hasBase {
+			if msg, ok := m[base][code]; ok {
+				return msg, true
+			}
+		}
+	}
+	return "", false
+}
+
+// Localize 返回错误在指定语言下的消息
+// 只有当错误使用的是错误码的默认消息时才会被替换，
+// 自定义消息（例如经过 Param 格式化的消息）保持不变.
+func Localize(err StatusError, locale string) string {
+	if err == nil {
+		return ""
+	}
+	if locale == "" || strings.EqualFold(locale, DefaultLocale) {
+		return err.Msg()
+	}
+	if !isDefaultMessage(err.Code(), err.Msg()) {
+		return err.Msg()
+	}
+	if msg, ok := LocalizedMessage(err.Code(), locale); ok {
+		return msg
+	}
+	return err.Msg()
+}
+
+// MatchLocale 根据 Accept-Language 头选择最合适的语言
+// 只会返回 DefaultLocale 或已通过 RegisterMessages 注册的语言.
+func MatchLocale(acceptLanguage string) string {
+	type candidate struct {
+		tag string
+		q   float64
+	}
+
+	var candidates []candidate
+	for _, part := range strings.Split(acceptLanguage, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		if tag == "" || tag == "*" {
+			continue
+		}
+		q := 1.0
+		if v, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			if parsed, err := strconv.ParseFloat(v, 64); err == nil {
+				q = parsed
+			}
+		}
+		candidates = append(candidates, candidate{tag: tag, q: q})
+	}
+	sort.SliceStable(candidates, func(i, j int) bool { return candidates[i].q > candidates[j].q })
+
+	messagesMu.RLock()
+	defer messagesMu.RUnlock()
+	for _, c := range candidates {
+		lower := strings.ToLower(c.tag)
+		base, _, _ := strings.Cut(lower, "-")
+		if strings.EqualFold(c.tag, DefaultLocale) || strings.EqualFold(base, strings.Split(DefaultLocale, "-")[0]) {
+			return DefaultLocale
+		}
+		if _, ok := messages[lower]; ok {
+			return c.tag
+		}
+		if _, ok := messages[base]; ok {
+			return base
+		}
+	}
+	return DefaultLocale
+}
@@ -0,0 +1,57 @@
+package errors_test
+
+import (
+	"testing"
+
+	"github.com/go-anyway/framework-errors"
+)
+
+func TestLocalize(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      errors.StatusError
+		locale   string
+		expected string
+	}{
+		{"默认语言", errors.NewWithStatus(errors.CodeNotFound, ""), "zh-CN", "资源未找到"},
+		{"英文", errors.NewWithStatus(errors.CodeNotFound, ""), "en", "resource not found"},
+		{"区域回退到主语言", errors.NewWithStatus(errors.CodeNotFound, ""), "en-US", "resource not found"},
+		{"自定义消息保持不变", errors.NewWithStatus(errors.CodeNotFound, "订单不存在"), "en", "订单不存在"},
+		{"未注册语言", errors.NewWithStatus(errors.CodeNotFound, ""), "fr", "资源未找到"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := errors.Localize(tt.err, tt.locale); got != tt.expected {
+				t.Errorf("Localize() = %s, want %s", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestRegisterMessages(t *testing.T) {
+	errors.RegisterMessages("ja", map[int32]string{errors.CodeNotFound: "見つかりません"})
+
+	msg, ok := errors.LocalizedMessage(errors.CodeNotFound, "ja-JP")
+	if !ok || msg != "見つかりません" {
+		t.Errorf("LocalizedMessage() = (%s, %v), want 見つかりません", msg, ok)
+	}
+}
+
+func TestMatchLocale(t *testing.T) {
+	tests := []struct {
+		header   string
+		expected string
+	}{
+		{"", "zh-CN"},
+		{"en-US,en;q=0.9", "en"},
+		{"fr-FR,zh;q=0.8,en;q=0.5", "zh-CN"},
+		{"de, en;q=0.1", "en"},
+	}
+
+	for _, tt := range tests {
+		if got := errors.MatchLocale(tt.header); got != tt.expected {
+			t.Errorf("MatchLocale(%q) = %s, want %s", tt.header, got, tt.expected)
+		}
+	}
+}
@@ -0,0 +1,57 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package errors
+
+import (
+	"errors"
+	"strconv"
+)
+
+// IdempotentSafeKey 是幂等安全标记在扩展信息中的 key
+// 只有通过 IdempotentSafe 覆盖了错误码定义时才会出现，随扩展信息传递给调用方.
+const IdempotentSafeKey = "idempotent_safe"
+
+// IdempotentSafe 用于覆盖错误码定义中的幂等安全标记，见 CodeDefinition.IdempotentSafe
+// 例如支付接口确认扣款请求未发出时，可以将 CodeDependencyUnavailable 标记为可以安全重试.
+func IdempotentSafe(safe bool) Option {
+	return Extra(IdempotentSafeKey, strconv.FormatBool(safe))
+}
+
+// IsIdempotentSafe 返回失败的操作是否可以在没有副作用的情况下重试
+func (e *statusError) IsIdempotentSafe() bool {
+	if safe, err := strconv.ParseBool(e.ext.Extra[IdempotentSafeKey]); err == nil {
+		return safe
+	}
+	return GetCodeDefinition(e.statusCode).IdempotentSafe
+}
+
+// IsIdempotentSafe 返回失败的操作是否可以在没有副作用的情况下重试
+func (w *withStatus) IsIdempotentSafe() bool {
+	return w.status.IsIdempotentSafe()
+}
+
+// IsIdempotentSafe 判断 err 对应的操作是否可以在没有副作用的情况下重试
+// 与 IsRetryable 不同，IsRetryable 只表示稍后重试可能成功，支付等非幂等接口
+// 还需要确认失败的操作没有产生副作用. err 链中任意一个错误实现了
+// IsIdempotentSafe() bool 即以其结果为准，否则返回 false.
+func IsIdempotentSafe(err error) bool {
+	var r interface{ IsIdempotentSafe() bool }
+	if errors.As(err, &r) {
+		return r.IsIdempotentSafe()
+	}
+	return false
+}
@@ -0,0 +1,55 @@
+package errors_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-anyway/framework-errors"
+)
+
+func TestIsIdempotentSafe(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{name: "限流", err: errors.NewWithStatus(errors.CodeRateLimitExceeded, ""), want: true},
+		{name: "超时结果未知", err: errors.NewWithStatus(errors.CodeRequestTimeout, ""), want: false},
+		{name: "覆盖错误码定义", err: errors.NewWithStatus(errors.CodeDependencyUnavailable, "", errors.IdempotentSafe(true)), want: true},
+		{name: "gRPC 传递覆盖", err: errors.FromGRPCStatus(errors.ToGRPCStatus(errors.NewWithStatus(errors.CodeNotFound, "", errors.IdempotentSafe(false)))), want: false},
+		{name: "消息头传递覆盖", err: errors.DecodeHeaders(errors.EncodeHeaders(errors.NewWithStatus(errors.CodeDependencyUnavailable, "", errors.IdempotentSafe(true)))), want: true},
+		{name: "普通错误", err: context.Canceled, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := errors.IsIdempotentSafe(tt.err); got != tt.want {
+				t.Errorf("IsIdempotentSafe() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRetryRequireIdempotentSafe(t *testing.T) {
+	tests := []struct {
+		name      string
+		err       error
+		wantCalls int
+	}{
+		{name: "可以安全重试", err: errors.NewWithStatus(errors.CodeRateLimitExceeded, ""), wantCalls: 3},
+		{name: "可重试但结果未知", err: errors.NewWithStatus(errors.CodeDependencyTimeout, ""), wantCalls: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policy := fastPolicy(3)
+			policy.RequireIdempotentSafe = true
+			calls := 0
+			_ = errors.Retry(context.Background(), policy, func() error {
+				calls++
+				return tt.err
+			})
+			if calls != tt.wantCalls {
+				t.Errorf("calls = %d, want %d", calls, tt.wantCalls)
+			}
+		})
+	}
+}
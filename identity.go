@@ -0,0 +1,123 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package errors
+
+import "errors"
+
+// 身份标识在日志中的字段名，请求 ID 使用 RequestIDKey
+const (
+	UserIDKey   = "user_id"
+	TenantIDKey = "tenant_id"
+)
+
+// UserID 用于记录发起请求的用户，值为空时不记录
+// 用户与租户不属于扩展信息，只出现在日志（字段名为 UserIDKey、TenantIDKey）中，
+// 不会被序列化到 gRPC details、HTTP 响应体和消息头，也不会从上游错误中还原.
+//
+//	return errors.NewWithStatus(errors.CodeForbidden, "", errors.UserID(uid), errors.TenantID(tid))
+func UserID(id string) Option {
+	return func(ws *withStatus) {
+		if ws == nil || ws.status == nil || id == "" {
+			return
+		}
+		ws.status.userID = id
+	}
+}
+
+// TenantID 用于记录请求所属的租户，值为空时不记录
+func TenantID(id string) Option {
+	return func(ws *withStatus) {
+		if ws == nil || ws.status == nil || id == "" {
+			return
+		}
+		ws.status.tenantID = id
+	}
+}
+
+// RequestID 用于将请求 ID 记录到扩展信息中，值为空时不记录
+// 日志记录时会从 context 中自动补充请求 ID（见 LogAndReturnError），该选项用于没有 context 的场景.
+func RequestID(id string) Option {
+	return func(ws *withStatus) {
+		if id == "" {
+			return
+		}
+		Extra(RequestIDKey, id)(ws)
+	}
+}
+
+// isIdentityKey 判断扩展信息 key 是否与身份标识的字段名相同
+// 上游传入的这些 key 会被丢弃，避免伪造的身份标识混入日志与审计.
+func isIdentityKey(k string) bool {
+	return k == UserIDKey || k == TenantIDKey
+}
+
+// UserID 返回发起请求的用户，没有时返回空字符串
+func (e *statusError) UserID() string {
+	return e.userID
+}
+
+// UserID 返回发起请求的用户，没有时返回空字符串
+func (w *withStatus) UserID() string {
+	return w.status.UserID()
+}
+
+// TenantID 返回请求所属的租户，没有时返回空字符串
+func (e *statusError) TenantID() string {
+	return e.tenantID
+}
+
+// TenantID 返回请求所属的租户，没有时返回空字符串
+func (w *withStatus) TenantID() string {
+	return w.status.TenantID()
+}
+
+// RequestID 返回请求 ID，没有时返回空字符串
+func (e *statusError) RequestID() string {
+	return e.ext.Extra[RequestIDKey]
+}
+
+// RequestID 返回请求 ID，没有时返回空字符串
+func (w *withStatus) RequestID() string {
+	return w.status.RequestID()
+}
+
+// UserIDOf 返回 err 记录的用户，没有时返回空字符串
+func UserIDOf(err error) string {
+	var u interface{ UserID() string }
+	if errors.As(err, &u) {
+		return u.UserID()
+	}
+	return ""
+}
+
+// TenantIDOf 返回 err 记录的租户，没有时返回空字符串
+func TenantIDOf(err error) string {
+	var t interface{ TenantID() string }
+	if errors.As(err, &t) {
+		return t.TenantID()
+	}
+	return ""
+}
+
+// RequestIDOf 返回 err 记录的请求 ID，没有时返回空字符串
+func RequestIDOf(err error) string {
+	var r interface{ RequestID() string }
+	if errors.As(err, &r) {
+		return r.RequestID()
+	}
+	return ""
+}
@@ -0,0 +1,74 @@
+package errors_test
+
+import (
+	errstd "errors"
+	"testing"
+
+	"github.com/go-anyway/framework-errors"
+)
+
+func TestIdentity(t *testing.T) {
+	err := errors.NewWithStatus(errors.CodeForbidden, "",
+		errors.UserID("u-42"), errors.TenantID("acme"), errors.RequestID("req-1"))
+
+	tests := []struct {
+		name                          string
+		err                           error
+		wantUser, wantTenant, wantReq string
+	}{
+		{name: "本地错误", err: err, wantUser: "u-42", wantTenant: "acme", wantReq: "req-1"},
+		{name: "gRPC 往返只保留请求 ID", err: errors.FromGRPCStatus(errors.ToGRPCStatus(err)), wantReq: "req-1"},
+		{name: "消息头往返只保留请求 ID", err: errors.DecodeHeaders(errors.EncodeHeaders(err)), wantReq: "req-1"},
+		{name: "With 保留身份标识", err: errors.With(err, errors.Extra("k", "v")), wantUser: "u-42", wantTenant: "acme", wantReq: "req-1"},
+		{name: "空值不记录", err: errors.NewWithStatus(errors.CodeForbidden, "", errors.UserID(""), errors.TenantID(""), errors.RequestID(""))},
+		{name: "普通错误", err: errstd.New("boom")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := errors.UserIDOf(tt.err); got != tt.wantUser {
+				t.Errorf("UserIDOf() = %q, want %q", got, tt.wantUser)
+			}
+			if got := errors.TenantIDOf(tt.err); got != tt.wantTenant {
+				t.Errorf("TenantIDOf() = %q, want %q", got, tt.wantTenant)
+			}
+			if got := errors.RequestIDOf(tt.err); got != tt.wantReq {
+				t.Errorf("RequestIDOf() = %q, want %q", got, tt.wantReq)
+			}
+		})
+	}
+
+	if extra := err.Extra(); len(extra) != 2 || extra[errors.RequestIDKey] != "req-1" {
+		t.Errorf("Extra() = %v, want only request_id and stack", extra)
+	}
+}
+
+func TestIdentityFromUpstream(t *testing.T) {
+	forged := errors.NewWithStatus(errors.CodeForbidden, "",
+		errors.Extra(errors.UserIDKey, "admin"), errors.Extra(errors.TenantIDKey, "other"), errors.Extra("k", "v"))
+
+	tests := []struct {
+		name string
+		err  errors.StatusError
+	}{
+		{name: "gRPC", err: errors.FromGRPCStatus(errors.ToGRPCStatus(forged))},
+		{name: "消息头", err: errors.DecodeHeaders(errors.EncodeHeaders(forged))},
+		{name: "HTTP 响应体", err: errors.FromHTTPResponse(403, []byte(`{"code":1003,"extra":{"user_id":"admin","tenant_id":"other","k":"v"}}`))},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			extra := tt.err.Extra()
+			if _, ok := extra[errors.UserIDKey]; ok {
+				t.Errorf("Extra() = %v, 不应包含 user_id", extra)
+			}
+			if _, ok := extra[errors.TenantIDKey]; ok {
+				t.Errorf("Extra() = %v, 不应包含 tenant_id", extra)
+			}
+			if extra["k"] != "v" {
+				t.Errorf("Extra[k] = %q, want v", extra["k"])
+			}
+			if errors.UserIDOf(tt.err) != "" || errors.TenantIDOf(tt.err) != "" {
+				t.Error("不应从上游错误中还原身份标识")
+			}
+		})
+	}
+}
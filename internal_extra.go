@@ -0,0 +1,133 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package errors
+
+import (
+	"errors"
+	"sync/atomic"
+)
+
+// extraAllowlist 不为 nil 时只有其中的扩展信息可以跨服务传递
+var extraAllowlist atomic.Pointer[map[string]bool]
+
+// EnableExtraAllowlist 开启白名单模式，只有 keys 中的扩展信息会被序列化到 gRPC details、
+// HTTP 响应体和消息头中，其余扩展信息只出现在日志中. 适用于不允许向客户端泄露附带元数据的合规环境.
+// 重复调用会替换白名单.
+//
+//	errors.EnableExtraAllowlist("order_id", "field")
+func EnableExtraAllowlist(keys ...string) {
+	allowlist := make(map[string]bool, len(keys))
+	for _, k := range keys {
+		allowlist[k] = true
+	}
+	extraAllowlist.Store(&allowlist)
+}
+
+// DisableExtraAllowlist 关闭白名单模式（默认），除 InternalExtra 外的扩展信息都会跨服务传递
+func DisableExtraAllowlist() {
+	extraAllowlist.Store(nil)
+}
+
+// isPublicExtra 判断扩展信息 key 是否可以跨服务传递
+func isPublicExtra(m interface{ isInternalExtra(key string) bool }, key string) bool {
+	if m != nil && m.isInternalExtra(key) {
+		return false
+	}
+	if key == "stack" && getStackMode() != StackModeFull {
+		return false
+	}
+	if allowlist := extraAllowlist.Load(); allowlist != nil {
+		// 裁剪标记由本包添加，始终可以传递
+		return (*allowlist)[key] || key == ExtraTruncatedKey
+	}
+	return true
+}
+
+// InternalExtra 用于添加只出现在日志中的扩展信息，例如 SQL 语句、内部主机名
+// 这些信息不会被序列化到 gRPC details、HTTP 响应体和消息头中.
+func InternalExtra(k, v string) Option {
+	return func(ws *withStatus) {
+		if ws == nil || ws.status == nil {
+			return
+		}
+		if ws.status.ext.Extra == nil {
+			ws.status.ext.Extra = make(map[string]string)
+		}
+		ws.status.ext.Extra[k] = v
+		if ws.status.internal == nil {
+			ws.status.internal = make(map[string]bool)
+		}
+		ws.status.internal[k] = true
+	}
+}
+
+// IsInternalExtra 判断 err 的扩展信息 key 是否只用于日志
+func IsInternalExtra(err StatusError, key string) bool {
+	var m interface{ isInternalExtra(key string) bool }
+	if errors.As(err, &m) {
+		return m.isInternalExtra(key)
+	}
+	return false
+}
+
+// RangePublicExtra 依次对可以跨服务传递的扩展信息调用 fn，fn 返回 false 时停止遍历
+// 通过 InternalExtra 添加的扩展信息以及白名单模式下不在白名单中的扩展信息会被跳过，
+// 值经过 RegisterScrubber 注册的 Scrubber 处理，
+// 传输层序列化错误时应使用此函数而不是 Extra().
+func RangePublicExtra(err StatusError, fn func(k, v string) bool) {
+	var m interface{ isInternalExtra(key string) bool }
+	if err != nil && !errors.As(err, &m) {
+		m = nil
+	}
+	rangeScrubbedExtra(func(fn func(k, v string) bool) {
+		if m == nil && extraAllowlist.Load() == nil && getStackMode() == StackModeFull {
+			RangeExtra(err, fn)
+			return
+		}
+		RangeExtra(err, func(k, v string) bool {
+			if !isPublicExtra(m, k) {
+				return true
+			}
+			return fn(k, v)
+		})
+	}, fn)
+}
+
+// isInternalExtra 判断扩展信息 key 是否只用于日志
+func (e *statusError) isInternalExtra(key string) bool {
+	return e.internal[key]
+}
+
+// isInternalExtra 判断扩展信息 key 是否只用于日志
+func (w *withStatus) isInternalExtra(key string) bool {
+	return w.status.internal[key]
+}
+
+// internalKeysOf 返回 err 中只用于日志的扩展信息 key
+func internalKeysOf(err StatusError) map[string]bool {
+	var internal map[string]bool
+	RangeExtra(err, func(k, _ string) bool {
+		if IsInternalExtra(err, k) {
+			if internal == nil {
+				internal = make(map[string]bool)
+			}
+			internal[k] = true
+		}
+		return true
+	})
+	return internal
+}
@@ -0,0 +1,81 @@
+package errors_test
+
+import (
+	"testing"
+
+	"github.com/go-anyway/framework-errors"
+)
+
+func TestInternalExtra(t *testing.T) {
+	err := errors.NewWithStatus(errors.CodeInternalError, "",
+		errors.Extra("order_id", "42"),
+		errors.InternalExtra("sql", "SELECT * FROM orders"),
+		errors.InternalExtra("db_host", "10.0.0.1"),
+	)
+
+	// 日志中可见
+	if extra := err.Extra(); extra["sql"] == "" || extra["db_host"] == "" {
+		t.Errorf("Extra() = %v, 应包含内部扩展信息", extra)
+	}
+	if !errors.IsInternalExtra(err, "sql") || errors.IsInternalExtra(err, "order_id") {
+		t.Error("IsInternalExtra() 结果错误")
+	}
+
+	// 复制后的错误保留标记
+	annotated := errors.Annotate(errors.With(err, errors.Extra("user_id", "7")), "while paying")
+
+	tests := []struct {
+		name  string
+		extra func(se errors.StatusError) map[string]string
+	}{
+		{name: "gRPC details", extra: func(se errors.StatusError) map[string]string {
+			return errors.FromGRPCStatus(errors.ToGRPCStatus(se)).Extra()
+		}},
+		{name: "HTTP 响应体", extra: func(se errors.StatusError) map[string]string {
+			return errors.NewEnvelope(se).Extra
+		}},
+		{name: "消息头", extra: func(se errors.StatusError) map[string]string {
+			return errors.DecodeHeaders(errors.EncodeHeaders(se)).Extra()
+		}},
+	}
+
+	for _, tt := range tests {
+		for _, se := range []errors.StatusError{err, annotated} {
+			t.Run(tt.name, func(t *testing.T) {
+				extra := tt.extra(se)
+				if _, ok := extra["sql"]; ok {
+					t.Errorf("Extra = %v, 不应包含 sql", extra)
+				}
+				if _, ok := extra["db_host"]; ok {
+					t.Errorf("Extra = %v, 不应包含 db_host", extra)
+				}
+				if extra["order_id"] != "42" {
+					t.Errorf("Extra[order_id] = %s, want 42", extra["order_id"])
+				}
+			})
+		}
+	}
+}
+
+func TestExtraAllowlist(t *testing.T) {
+	errors.EnableExtraAllowlist("order_id")
+	defer errors.DisableExtraAllowlist()
+
+	err := errors.NewWithStatus(errors.CodeNotFound, "", errors.Extra("order_id", "42"), errors.Extra("tenant", "t1"))
+	if extra := err.Extra(); extra["tenant"] != "t1" {
+		t.Errorf("Extra() = %v, 日志中应包含全部扩展信息", extra)
+	}
+
+	wire := errors.FromGRPCStatus(errors.ToGRPCStatus(err)).Extra()
+	if len(wire) != 1 || wire["order_id"] != "42" {
+		t.Errorf("gRPC Extra() = %v, 只应包含白名单中的扩展信息", wire)
+	}
+	if env := errors.NewEnvelope(err); len(env.Extra) != 1 || env.Extra["order_id"] != "42" {
+		t.Errorf("Envelope.Extra = %v, 只应包含白名单中的扩展信息", env.Extra)
+	}
+
+	errors.DisableExtraAllowlist()
+	if wire := errors.FromGRPCStatus(errors.ToGRPCStatus(err)).Extra(); wire["tenant"] != "t1" {
+		t.Errorf("gRPC Extra() = %v, 关闭白名单后应传递全部扩展信息", wire)
+	}
+}
@@ -0,0 +1,96 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package errors
+
+import (
+	"errors"
+
+	"google.golang.org/grpc/status"
+)
+
+// 预定义错误码的哨兵错误，用于 Is 与标准库 errors.Is 按错误码匹配
+var (
+	ErrInvalidParam          = newCodeSentinel(CodeInvalidParam)
+	ErrUnauthorized          = newCodeSentinel(CodeUnauthorized)
+	ErrForbidden             = newCodeSentinel(CodeForbidden)
+	ErrNotFound              = newCodeSentinel(CodeNotFound)
+	ErrAlreadyExists         = newCodeSentinel(CodeAlreadyExists)
+	ErrInternal              = newCodeSentinel(CodeInternalError)
+	ErrRequestTimeout        = newCodeSentinel(CodeRequestTimeout)
+	ErrConflict              = newCodeSentinel(CodeConflict)
+	ErrRateLimitExceeded     = newCodeSentinel(CodeRateLimitExceeded)
+	ErrDependencyUnavailable = newCodeSentinel(CodeDependencyUnavailable)
+)
+
+// codeSentinel 标记本包预定义的哨兵错误，只有它们按错误码匹配
+// 业务自己定义的 StatusError 哨兵仍按标识匹配，错误码相同的两个哨兵不会被视为同一错误.
+type codeSentinel struct {
+	*statusError
+}
+
+func newCodeSentinel(code int32) StatusError {
+	return &codeSentinel{statusError: NewStatusError(code, "", nil).(*statusError)}
+}
+
+// Unwrap 返回哨兵内部的 statusError，WrapWithStatus 等函数可以像普通 statusError 一样处理哨兵
+func (s *codeSentinel) Unwrap() error {
+	return s.statusError
+}
+
+// Is 实现 errors.Is 的匹配规则，target 为预定义的哨兵错误时按错误码匹配
+func (e *statusError) Is(target error) bool {
+	t, ok := target.(*codeSentinel)
+	return ok && t.statusCode == e.statusCode
+}
+
+// Is 实现 errors.Is 的匹配规则，target 为预定义的哨兵错误时按错误码匹配
+func (w *withStatus) Is(target error) bool {
+	return w.status.Is(target)
+}
+
+// Is 判断 err 是否与 target 匹配，target 为预定义的哨兵错误（如 ErrNotFound）时按错误码匹配
+// 与标准库 errors.Is 不同，err 链中没有 StatusError 但包含 gRPC status 时
+// （例如没有使用本包拦截器的客户端直接返回的错误），会按需解析 status details 后比较错误码.
+//
+//	if errors.Is(err, errors.ErrNotFound) { ... }
+func Is(err, target error) bool {
+	if errors.Is(err, target) {
+		return true
+	}
+	t, ok := target.(*codeSentinel)
+	if !ok {
+		return false
+	}
+	return IsCode(err, t.statusCode)
+}
+
+// IsCode 判断 err 的错误码是否为 code，别名会被转换为新错误码（见 RegisterAlias）
+// err 链中没有 StatusError 时尝试将其作为 gRPC status 解析.
+func IsCode(err error, code int32) bool {
+	if err == nil {
+		return false
+	}
+	code = CanonicalCode(code)
+	var se StatusError
+	if errors.As(err, &se) {
+		return se.Code() == code
+	}
+	if st, ok := status.FromError(err); ok {
+		return FromGRPCStatus(st).Code() == code
+	}
+	return false
+}
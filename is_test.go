@@ -0,0 +1,71 @@
+package errors_test
+
+import (
+	errstd "errors"
+	"fmt"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/go-anyway/framework-errors"
+)
+
+func TestIs(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{name: "StatusError", err: errors.NewWithStatus(errors.CodeNotFound, "订单不存在"), want: true},
+		{name: "包装后", err: fmt.Errorf("get order: %w", errors.NewStatusError(errors.CodeNotFound, "", nil)), want: true},
+		{name: "错误码不同", err: errors.NewWithStatus(errors.CodeConflict, ""), want: false},
+		{name: "经过转换的 gRPC error", err: errors.ToGRPCError(errors.NewWithStatus(errors.CodeNotFound, "")), want: true},
+		{name: "grpc-go 直接返回的 status", err: status.Error(codes.NotFound, "not found"), want: true},
+		{name: "包装的 grpc-go status", err: fmt.Errorf("call: %w", status.Error(codes.NotFound, "not found")), want: true},
+		{name: "其他 gRPC code", err: status.Error(codes.Unavailable, "down"), want: false},
+		{name: "普通错误", err: errstd.New("boom"), want: false},
+		{name: "nil", err: nil, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := errors.Is(tt.err, errors.ErrNotFound); got != tt.want {
+				t.Errorf("Is() = %v, want %v", got, tt.want)
+			}
+			if got := errors.IsCode(tt.err, errors.CodeNotFound); got != tt.want {
+				t.Errorf("IsCode() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStdErrorsIs(t *testing.T) {
+	err := fmt.Errorf("get order: %w", errors.NewWithStatus(errors.CodeNotFound, "订单不存在"))
+	if !errstd.Is(err, errors.ErrNotFound) {
+		t.Error("errors.Is() 应按错误码匹配")
+	}
+	if errstd.Is(err, errors.ErrConflict) {
+		t.Error("errors.Is() 错误码不同时不应匹配")
+	}
+
+	sentinel := errstd.New("sentinel")
+	if !errors.Is(fmt.Errorf("wrap: %w", sentinel), sentinel) {
+		t.Error("Is() 应兼容普通哨兵错误")
+	}
+}
+
+func TestUserSentinelsMatchByIdentity(t *testing.T) {
+	errUserNotFound := errors.NewStatusError(errors.CodeNotFound, "用户不存在", nil)
+	errOrderNotFound := errors.NewStatusError(errors.CodeNotFound, "订单不存在", nil)
+
+	err := fmt.Errorf("get user: %w", errUserNotFound)
+	if !errstd.Is(err, errUserNotFound) || !errors.Is(err, errUserNotFound) {
+		t.Error("Is() 应匹配同一个哨兵")
+	}
+	if errstd.Is(err, errOrderNotFound) || errors.Is(err, errOrderNotFound) {
+		t.Error("错误码相同的不同哨兵不应匹配")
+	}
+	if !errstd.Is(err, errors.ErrNotFound) {
+		t.Error("预定义的哨兵应按错误码匹配")
+	}
+}
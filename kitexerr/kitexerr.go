@@ -0,0 +1,112 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+// Package kitexerr 让 StatusError 通过 Kitex 的业务错误通道（TTHeader）传递.
+package kitexerr
+
+import (
+	stderrors "errors"
+	"strconv"
+
+	"github.com/go-anyway/framework-errors"
+
+	"github.com/cloudwego/kitex/pkg/kerrors"
+)
+
+// extraRetryable 是在 BizExtra 中传递可重试标记使用的 key
+const extraRetryable = "retryable"
+
+// BizError 是 StatusError 到 kerrors.BizStatusErrorIface 的适配器
+// Kitex 服务端只会把实现了 BizStatusErrorIface 的错误通过 TTHeader 传给调用方，
+// 其他错误会被包装为 kerrors.ErrBiz.
+type BizError struct {
+	errors.StatusError
+}
+
+var _ kerrors.BizStatusErrorIface = (*BizError)(nil)
+
+// ToBizStatusError 将错误转换为 Kitex 业务错误，在 Kitex handler 中返回
+// 非 StatusError 会先经过 errors.Translate 转换.
+//
+//	func (h *Handler) GetUser(ctx context.Context, req *api.Req) (*api.Resp, error) {
+//		u, err := h.svc.GetUser(ctx, req.Id)
+//		if err != nil {
+//			return nil, kitexerr.ToBizStatusError(err)
+//		}
+//		return &api.Resp{User: u}, nil
+//	}
+func ToBizStatusError(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &BizError{StatusError: errors.Translate(err)}
+}
+
+// BizStatusCode 实现 kerrors.BizStatusErrorIface 接口
+func (e *BizError) BizStatusCode() int32 {
+	return e.Code()
+}
+
+// BizMessage 实现 kerrors.BizStatusErrorIface 接口
+func (e *BizError) BizMessage() string {
+	return errors.ScrubbedMsg(e.StatusError)
+}
+
+// BizExtra 实现 kerrors.BizStatusErrorIface 接口
+// 返回不含堆栈的扩展信息，并附带可重试标记.
+func (e *BizError) BizExtra() map[string]string {
+	extra := make(map[string]string)
+	errors.RangePublicExtra(e.StatusError, func(k, v string) bool {
+		if k != "stack" {
+			extra[k] = v
+		}
+		return true
+	})
+	extra[extraRetryable] = strconv.FormatBool(errors.IsRetryable(e.StatusError))
+	return extra
+}
+
+// Unwrap 返回被适配的 StatusError
+func (e *BizError) Unwrap() error {
+	return e.StatusError
+}
+
+// FromBizStatusError 从 Kitex 客户端返回的错误中还原 StatusError
+// err 不是 Kitex 业务错误时返回 nil.
+func FromBizStatusError(err error) errors.StatusError {
+	var adapted *BizError
+	if stderrors.As(err, &adapted) {
+		return adapted.StatusError
+	}
+
+	bizErr, ok := kerrors.FromBizStatusError(err)
+	if !ok {
+		return nil
+	}
+
+	var opts []errors.Option
+	for k, v := range bizErr.BizExtra() {
+		if k == extraRetryable {
+			if retryable, parseErr := strconv.ParseBool(v); parseErr == nil {
+				opts = append(opts, errors.Retryable(retryable))
+			}
+			continue
+		}
+		opts = append(opts, errors.Extra(k, v))
+	}
+
+	return errors.NewWithStatus(bizErr.BizStatusCode(), bizErr.BizMessage(), opts...)
+}
@@ -0,0 +1,69 @@
+package kitexerr_test
+
+import (
+	stderrors "errors"
+	"testing"
+
+	"github.com/go-anyway/framework-errors"
+	"github.com/go-anyway/framework-errors/kitexerr"
+
+	"github.com/cloudwego/kitex/pkg/kerrors"
+)
+
+func TestToBizStatusError(t *testing.T) {
+	err := kitexerr.ToBizStatusError(errors.NewWithStatus(
+		errors.CodeRateLimitExceeded,
+		"",
+		errors.Extra("limit", "100"),
+	))
+
+	bizErr, ok := kerrors.FromBizStatusError(err)
+	if !ok {
+		t.Fatalf("error = %v, want BizStatusErrorIface", err)
+	}
+	if bizErr.BizStatusCode() != errors.CodeRateLimitExceeded {
+		t.Errorf("BizStatusCode() = %d, want %d", bizErr.BizStatusCode(), errors.CodeRateLimitExceeded)
+	}
+	if bizErr.BizMessage() != "请求过于频繁" {
+		t.Errorf("BizMessage() = %s, want 请求过于频繁", bizErr.BizMessage())
+	}
+	extra := bizErr.BizExtra()
+	if extra["limit"] != "100" {
+		t.Errorf("BizExtra[limit] = %s, want 100", extra["limit"])
+	}
+	if _, ok := extra["stack"]; ok {
+		t.Error("BizExtra 不应包含堆栈信息")
+	}
+
+	var se errors.StatusError
+	if !stderrors.As(err, &se) || se.Code() != errors.CodeRateLimitExceeded {
+		t.Error("应能通过 errors.As 取出原始 StatusError")
+	}
+}
+
+func TestFromBizStatusError(t *testing.T) {
+	// 模拟经过 TTHeader 传输后客户端收到的错误
+	wire := kerrors.NewBizStatusErrorWithExtra(
+		errors.CodeDependencyUnavailable,
+		"下游不可用",
+		map[string]string{"retryable": "false", "service": "payment"},
+	)
+
+	se := kitexerr.FromBizStatusError(wire)
+	if se == nil {
+		t.Fatal("FromBizStatusError() = nil, want StatusError")
+	}
+	if se.Code() != errors.CodeDependencyUnavailable || se.Msg() != "下游不可用" {
+		t.Errorf("StatusError = (%d, %s)", se.Code(), se.Msg())
+	}
+	if errors.IsRetryable(se) {
+		t.Error("应使用 BizExtra 中的可重试标记")
+	}
+	if se.Extra()["service"] != "payment" {
+		t.Errorf("Extra[service] = %s, want payment", se.Extra()["service"])
+	}
+
+	if kitexerr.FromBizStatusError(stderrors.New("other")) != nil {
+		t.Error("非业务错误应返回 nil")
+	}
+}
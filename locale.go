@@ -0,0 +1,129 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package errors
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// MetadataAcceptLanguage 是 gRPC 请求中携带调用方语言的 metadata key
+const MetadataAcceptLanguage = "accept-language"
+
+type localeContextKey struct{}
+
+// WithLocale 返回记录了调用方语言的 context
+func WithLocale(ctx context.Context, locale string) context.Context {
+	return context.WithValue(ctx, localeContextKey{}, locale)
+}
+
+// LocaleFromContext 获取 context 中记录的调用方语言，没有时返回空字符串
+func LocaleFromContext(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	locale, _ := ctx.Value(localeContextKey{}).(string)
+	return locale
+}
+
+// LocaleMiddleware 是根据 Accept-Language 头解析调用方语言的 HTTP 中间件
+// 解析结果记录在请求的 context 中（见 LocaleFromContext），WriteError 会使用该语言渲染错误，
+// handler 也可以据此构造本地化的消息.
+func LocaleMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		locale := MatchLocale(r.Header.Get("Accept-Language"))
+		next.ServeHTTP(w, r.WithContext(WithLocale(r.Context(), locale)))
+	})
+}
+
+// UnaryServerLocaleInterceptor 返回根据 metadata 中的 accept-language 本地化错误消息的 gRPC 一元拦截器
+// handler 返回的错误被转换为 gRPC status，消息替换为调用方语言下的消息，
+// 并附加 errdetails.LocalizedMessage.
+func UnaryServerLocaleInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		locale := incomingLocale(ctx)
+		resp, err := handler(WithLocale(ctx, locale), req)
+		return resp, localizeGRPCError(err, locale)
+	}
+}
+
+// StreamServerLocaleInterceptor 返回根据 metadata 中的 accept-language 本地化错误消息的 gRPC 流拦截器
+func StreamServerLocaleInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		locale := incomingLocale(ss.Context())
+		err := handler(srv, &localeServerStream{ServerStream: ss, ctx: WithLocale(ss.Context(), locale)})
+		return localizeGRPCError(err, locale)
+	}
+}
+
+// localeServerStream 替换 ServerStream 的 context
+// 同时用于 StreamServerContextInterceptor.
+type localeServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+// Context 返回记录了调用方语言的 context
+func (s *localeServerStream) Context() context.Context {
+	return s.ctx
+}
+
+// incomingLocale 从 gRPC metadata 中解析调用方语言
+func incomingLocale(ctx context.Context) string {
+	md, _ := metadata.FromIncomingContext(ctx)
+	var acceptLanguage string
+	if values := md.Get(MetadataAcceptLanguage); len(values) > 0 {
+		acceptLanguage = values[0]
+	}
+	return MatchLocale(acceptLanguage)
+}
+
+// localizeGRPCError 将 err 转换为调用方语言下的 gRPC error
+// StatusError 与 gRPC status 之外的错误原样返回.
+func localizeGRPCError(err error, locale string) error {
+	if err == nil {
+		return nil
+	}
+
+	var se StatusError
+	var st *status.Status
+	if errors.As(err, &se) {
+		st = ToGRPCStatus(se)
+	} else if s, ok := status.FromError(err); ok {
+		st = s
+		se = FromGRPCStatus(s)
+	} else {
+		return err
+	}
+
+	msg := Localize(se, locale)
+	if msg == se.Msg() {
+		return st.Err()
+	}
+	pb := st.Proto()
+	pb.Message = msg
+	if localized, err := marshalAny(&errdetails.LocalizedMessage{Locale: locale, Message: msg}); err == nil {
+		pb.Details = append(pb.Details, localized)
+	}
+	return status.FromProto(pb).Err()
+}
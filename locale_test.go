@@ -0,0 +1,82 @@
+package errors_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/go-anyway/framework-errors"
+)
+
+func TestLocaleMiddleware(t *testing.T) {
+	var gotLocale string
+	h := errors.LocaleMiddleware(errors.Handler(func(w http.ResponseWriter, r *http.Request) error {
+		gotLocale = errors.LocaleFromContext(r.Context())
+		return errors.NewWithStatus(errors.CodeNotFound, "")
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Accept-Language", "en-US,en;q=0.9")
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+
+	if gotLocale != "en" {
+		t.Errorf("LocaleFromContext() = %q, want en", gotLocale)
+	}
+	var env errors.Envelope
+	if err := json.Unmarshal(rec.Body.Bytes(), &env); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+	if env.Message != "resource not found" {
+		t.Errorf("Envelope.Message = %q, want %q", env.Message, "resource not found")
+	}
+}
+
+func TestUnaryServerLocaleInterceptor(t *testing.T) {
+	interceptor := errors.UnaryServerLocaleInterceptor()
+
+	tests := []struct {
+		name           string
+		acceptLanguage string
+		handlerErr     error
+		wantMsg        string
+		wantLocalized  bool
+	}{
+		{name: "英文", acceptLanguage: "en", handlerErr: errors.NewWithStatus(errors.CodeNotFound, ""), wantMsg: "resource not found", wantLocalized: true},
+		{name: "默认语言", acceptLanguage: "zh-CN", handlerErr: errors.NewWithStatus(errors.CodeNotFound, ""), wantMsg: "资源未找到", wantLocalized: false},
+		{name: "gRPC error", acceptLanguage: "en", handlerErr: errors.ToGRPCError(errors.NewStatusError(errors.CodeNotFound, "", nil)), wantMsg: "resource not found", wantLocalized: true},
+		{name: "自定义消息", acceptLanguage: "en", handlerErr: errors.NewWithStatus(errors.CodeNotFound, "订单不存在"), wantMsg: "订单不存在", wantLocalized: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(errors.MetadataAcceptLanguage, tt.acceptLanguage))
+			_, err := interceptor(ctx, nil, &grpc.UnaryServerInfo{}, func(ctx context.Context, req interface{}) (interface{}, error) {
+				return nil, tt.handlerErr
+			})
+
+			st, _ := status.FromError(err)
+			if st.Message() != tt.wantMsg {
+				t.Errorf("Message() = %q, want %q", st.Message(), tt.wantMsg)
+			}
+			var localized bool
+			for _, d := range st.Details() {
+				if _, ok := d.(*errdetails.LocalizedMessage); ok {
+					localized = true
+				}
+			}
+			if localized != tt.wantLocalized {
+				t.Errorf("LocalizedMessage = %v, want %v", localized, tt.wantLocalized)
+			}
+			if se := errors.FromGRPCStatus(st); se.Code() != errors.CodeNotFound || se.Msg() != tt.wantMsg {
+				t.Errorf("FromGRPCStatus() = %d %q", se.Code(), se.Msg())
+			}
+		})
+	}
+}
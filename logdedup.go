@@ -0,0 +1,156 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package errors
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// maxLogDedupEntries 是同一窗口内记录的指纹的最大数量，超出后新的指纹不去重
+const maxLogDedupEntries = 4096
+
+var (
+	logDedupWindow atomic.Int64
+
+	logDedupMu sync.Mutex
+	// logDedup 记录每个指纹在当前窗口内的首次记录时间与被抑制的次数
+	logDedup = map[string]*logDedupEntry{}
+	// logDedupQueue 按窗口开始时间排列，窗口长度相同，因此越靠前越早过期
+	logDedupQueue []*logDedupEntry
+	// logDedupTimer 在队首的窗口结束时输出汇总日志，没有流量时被抑制的次数也不会丢失
+	logDedupTimer *time.Timer
+)
+
+// logDedupEntry 是一个指纹在当前窗口内的记录情况，汇总日志使用第一次记录时的 logger 与级别
+type logDedupEntry struct {
+	fingerprint string
+	code        int32
+	level       zapcore.Level
+	logger      *zap.Logger
+	start       time.Time
+	suppressed  int
+}
+
+// SetLogDedupWindow 设置日志去重窗口，0 表示不去重（默认）
+// 开启后相同指纹（见 Fingerprint）的错误在窗口内只记录第一次，窗口结束时
+// 记录一条 "重复出现 N 次" 的汇总日志，避免错误风暴时日志被大量重复内容淹没.
+// 修改窗口时会先输出尚未输出的汇总日志.
+func SetLogDedupWindow(window time.Duration) {
+	logDedupWindow.Store(int64(window))
+	logDedupMu.Lock()
+	var pending []*logDedupEntry
+	for _, e := range logDedupQueue {
+		if e.suppressed > 0 {
+			pending = append(pending, e)
+		}
+	}
+	clear(logDedup)
+	logDedupQueue = nil
+	if logDedupTimer != nil {
+		logDedupTimer.Stop()
+		logDedupTimer = nil
+	}
+	logDedupMu.Unlock()
+	logDedupSummaries(pending)
+}
+
+// dedupLog 判断指纹为 fingerprint 的错误是否需要记录日志，同时输出已结束的窗口的汇总日志
+func dedupLog(logger *zap.Logger, level zapcore.Level, code int32, fingerprint string) bool {
+	window := time.Duration(logDedupWindow.Load())
+	if window <= 0 {
+		return true
+	}
+
+	t := now()
+	logDedupMu.Lock()
+	expired := sweepLogDedup(t, window)
+	if e, exists := logDedup[fingerprint]; exists {
+		e.suppressed++
+		logDedupMu.Unlock()
+		logDedupSummaries(expired)
+		return false
+	}
+	if len(logDedup) < maxLogDedupEntries {
+		e := &logDedupEntry{fingerprint: fingerprint, code: code, level: level, logger: logger, start: t}
+		logDedup[fingerprint] = e
+		logDedupQueue = append(logDedupQueue, e)
+		if logDedupTimer == nil {
+			logDedupTimer = time.AfterFunc(window, flushLogDedup)
+		}
+	}
+	logDedupMu.Unlock()
+
+	// 汇总日志先于本次的日志输出
+	logDedupSummaries(expired)
+	return true
+}
+
+// flushLogDedup 由 logDedupTimer 调用，输出已结束的窗口的汇总日志
+func flushLogDedup() {
+	window := time.Duration(logDedupWindow.Load())
+	t := now()
+	logDedupMu.Lock()
+	logDedupTimer = nil
+	var expired []*logDedupEntry
+	if window > 0 {
+		expired = sweepLogDedup(t, window)
+		if len(logDedupQueue) > 0 {
+			d := logDedupQueue[0].start.Add(window).Sub(t)
+			if d <= 0 {
+				d = window
+			}
+			logDedupTimer = time.AfterFunc(d, flushLogDedup)
+		}
+	}
+	logDedupMu.Unlock()
+	logDedupSummaries(expired)
+}
+
+// sweepLogDedup 移除窗口已结束的指纹，返回其中有被抑制记录的指纹，调用方需持有 logDedupMu
+// 每个指纹只会被移除一次，因此均摊开销为 O(1).
+func sweepLogDedup(t time.Time, window time.Duration) []*logDedupEntry {
+	var expired []*logDedupEntry
+	for len(logDedupQueue) > 0 && t.Sub(logDedupQueue[0].start) >= window {
+		e := logDedupQueue[0]
+		logDedupQueue[0] = nil
+		logDedupQueue = logDedupQueue[1:]
+		delete(logDedup, e.fingerprint)
+		if e.suppressed > 0 {
+			expired = append(expired, e)
+		}
+	}
+	if len(logDedupQueue) == 0 {
+		logDedupQueue = nil
+	}
+	return expired
+}
+
+// logDedupSummaries 为每个窗口内被抑制过的指纹记录一条汇总日志
+func logDedupSummaries(entries []*logDedupEntry) {
+	for _, e := range entries {
+		e.logger.Log(e.level, "业务错误重复出现",
+			zap.String("fingerprint", e.fingerprint),
+			zap.Int32("error_code", e.code),
+			zap.Int("repeated", e.suppressed),
+		)
+	}
+}
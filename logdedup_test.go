@@ -0,0 +1,97 @@
+package errors_test
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/go-anyway/framework-errors"
+)
+
+func TestLogDedupWindow(t *testing.T) {
+	current := time.Unix(1700000000, 0)
+	defer errors.SetClock(func() time.Time { return current })()
+	errors.SetLogDedupWindow(time.Minute)
+	defer errors.SetLogDedupWindow(0)
+
+	logOnce := func() {
+		_ = errors.LogAndReturnError(context.Background(), errors.NewStatusError(errors.CodeDependencyUnavailable, "", nil))
+	}
+
+	entries := captureLogs(t, func() {
+		for i := 0; i < 5; i++ {
+			logOnce()
+		}
+		// 不同指纹的错误不受影响
+		_ = errors.LogAndReturnError(context.Background(), errors.NewStatusError(errors.CodeInternalError, "", nil))
+
+		current = current.Add(2 * time.Minute)
+		logOnce()
+	})
+
+	var msgs []string
+	for _, e := range entries {
+		msgs = append(msgs, e["msg"].(string))
+	}
+	want := []string{"业务错误（影响稳定性）", "业务错误（影响稳定性）", "业务错误重复出现", "业务错误（影响稳定性）"}
+	if len(msgs) != len(want) {
+		t.Fatalf("msgs = %v, want %v", msgs, want)
+	}
+	for i := range want {
+		if msgs[i] != want[i] {
+			t.Errorf("msgs[%d] = %q, want %q", i, msgs[i], want[i])
+		}
+	}
+	if repeated := entries[2]["repeated"]; repeated != float64(4) {
+		t.Errorf("repeated = %v, want 4", repeated)
+	}
+}
+
+func TestLogDedupFlushWithoutTraffic(t *testing.T) {
+	errors.SetLogDedupWindow(20 * time.Millisecond)
+	defer errors.SetLogDedupWindow(0)
+
+	entries := captureLogs(t, func() {
+		for i := 0; i < 3; i++ {
+			_ = errors.LogAndReturnError(context.Background(), errors.NewStatusError(errors.CodeDependencyUnavailable, "", nil))
+		}
+		// 窗口结束后没有新的错误，汇总日志仍然应该输出
+		deadline := time.Now().Add(2 * time.Second)
+		for errors.LogDedupLen() > 0 && time.Now().Before(deadline) {
+			time.Sleep(5 * time.Millisecond)
+		}
+	})
+
+	if len(entries) != 2 || entries[1]["msg"] != "业务错误重复出现" || entries[1]["repeated"] != float64(2) {
+		t.Errorf("entries = %v", entries)
+	}
+}
+
+func TestLogDedupEvictsExpired(t *testing.T) {
+	current := time.Unix(1700000000, 0)
+	defer errors.SetClock(func() time.Time { return current })()
+	errors.SetLogDedupWindow(time.Minute)
+	defer errors.SetLogDedupWindow(0)
+
+	_ = captureLogs(t, func() {
+		for i := 0; i < 100; i++ {
+			err := errors.NewStatusError(errors.CodeDependencyUnavailable, fmt.Sprintf("err-%d", i), nil)
+			_ = errors.LogAndReturnError(context.Background(), errors.Annotate(err, strconv.Itoa(i)))
+			_ = errors.LogAndReturnError(context.Background(), errors.Annotate(err, strconv.Itoa(i)))
+		}
+	})
+	if n := errors.LogDedupLen(); n == 0 {
+		t.Fatal("LogDedupLen() = 0, 窗口内应记录指纹")
+	}
+
+	// 窗口结束后，被抑制过的指纹同样被清理
+	current = current.Add(2 * time.Minute)
+	_ = captureLogs(t, func() {
+		_ = errors.LogAndReturnError(context.Background(), errors.NewStatusError(errors.CodeInternalError, "", nil))
+	})
+	if n := errors.LogDedupLen(); n != 1 {
+		t.Errorf("LogDedupLen() = %d, want 1", n)
+	}
+}
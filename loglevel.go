@@ -0,0 +1,79 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package errors
+
+import (
+	"sync"
+
+	"go.uber.org/zap/zapcore"
+)
+
+var (
+	logLevelsMu sync.RWMutex
+	// stabilityLogLevel 与 otherLogLevel 分别是影响稳定性与不影响稳定性的错误的默认日志级别
+	stabilityLogLevel = zapcore.ErrorLevel
+	otherLogLevel     = zapcore.WarnLevel
+	// codeLogLevels 是按错误码配置的日志级别，优先于默认级别
+	codeLogLevels = map[int32]zapcore.Level{}
+	// suppressedCodes 是不记录日志的错误码
+	suppressedCodes = map[int32]bool{}
+)
+
+// SetDefaultLogLevels 设置 LogAndReturnError 与 WriteError 记录日志的默认级别
+// 默认影响稳定性的错误使用 Error，其余使用 Warn.
+func SetDefaultLogLevels(affectStability, other zapcore.Level) {
+	logLevelsMu.Lock()
+	defer logLevelsMu.Unlock()
+	stabilityLogLevel = affectStability
+	otherLogLevel = other
+}
+
+// SetLogLevel 设置错误码记录日志的级别，优先于 SetDefaultLogLevels
+//
+//	errors.SetLogLevel(errors.CodeUserNotFound, zapcore.InfoLevel)
+func SetLogLevel(code int32, level zapcore.Level) {
+	logLevelsMu.Lock()
+	defer logLevelsMu.Unlock()
+	codeLogLevels[code] = level
+	delete(suppressedCodes, code)
+}
+
+// SuppressLog 设置错误码不记录日志，例如公开查询接口中的 CodeNotFound
+// 之后调用 SetLogLevel 可以恢复记录.
+func SuppressLog(codes ...int32) {
+	logLevelsMu.Lock()
+	defer logLevelsMu.Unlock()
+	for _, code := range codes {
+		suppressedCodes[code] = true
+	}
+}
+
+// logLevel 返回 err 记录日志的级别，不记录日志时返回 false
+func logLevel(err StatusError) (zapcore.Level, bool) {
+	logLevelsMu.RLock()
+	defer logLevelsMu.RUnlock()
+	if suppressedCodes[err.Code()] {
+		return 0, false
+	}
+	if level, ok := codeLogLevels[err.Code()]; ok {
+		return level, true
+	}
+	if err.IsAffectStability() {
+		return stabilityLogLevel, true
+	}
+	return otherLogLevel, true
+}
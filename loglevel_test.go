@@ -0,0 +1,41 @@
+package errors_test
+
+import (
+	"testing"
+
+	"go.uber.org/zap/zapcore"
+
+	"github.com/go-anyway/framework-errors"
+)
+
+func TestLogLevel(t *testing.T) {
+	defer errors.ResetLogLevels()
+	errors.SetDefaultLogLevels(zapcore.ErrorLevel, zapcore.InfoLevel)
+	errors.SetLogLevel(errors.CodeUserNotFound, zapcore.DebugLevel)
+	errors.SuppressLog(errors.CodeNotFound, errors.CodeCacheMiss)
+	errors.SetLogLevel(errors.CodeCacheMiss, zapcore.DebugLevel)
+
+	tests := []struct {
+		name      string
+		code      int32
+		wantLevel zapcore.Level
+		wantLog   bool
+	}{
+		{name: "影响稳定性", code: errors.CodeInternalError, wantLevel: zapcore.ErrorLevel, wantLog: true},
+		{name: "不影响稳定性", code: errors.CodeInvalidParam, wantLevel: zapcore.InfoLevel, wantLog: true},
+		{name: "按错误码配置", code: errors.CodeUserNotFound, wantLevel: zapcore.DebugLevel, wantLog: true},
+		{name: "不记录日志", code: errors.CodeNotFound, wantLog: false},
+		{name: "恢复记录", code: errors.CodeCacheMiss, wantLevel: zapcore.DebugLevel, wantLog: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			level, ok := errors.LogLevel(errors.NewStatusError(tt.code, "", nil))
+			if ok != tt.wantLog {
+				t.Fatalf("LogLevel() ok = %v, want %v", ok, tt.wantLog)
+			}
+			if ok && level != tt.wantLevel {
+				t.Errorf("LogLevel() = %v, want %v", level, tt.wantLevel)
+			}
+		})
+	}
+}
@@ -0,0 +1,71 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package errors
+
+import "go.uber.org/zap"
+
+// LogOption 用于定制 LogAndReturnError 记录的日志
+type LogOption func(c *logConfig)
+
+// logConfig 是 LogOption 修改的日志配置
+type logConfig struct {
+	message string
+	fields  []zap.Field
+	skip    bool
+	causes  bool
+}
+
+// LogMessage 替换日志消息，默认为 "业务错误" 或 "业务错误（影响稳定性）"
+func LogMessage(msg string) LogOption {
+	return func(c *logConfig) {
+		c.message = msg
+	}
+}
+
+// LogFields 向日志追加 zap 字段
+//
+//	return errors.LogAndReturnError(ctx, err, errors.LogFields(zap.String("order_id", id)))
+func LogFields(fields ...zap.Field) LogOption {
+	return func(c *logConfig) {
+		c.fields = append(c.fields, fields...)
+	}
+}
+
+// SkipLog 不记录日志，只转换为 gRPC error
+func SkipLog() LogOption {
+	return func(c *logConfig) {
+		c.skip = true
+	}
+}
+
+// LogCauseChain 在日志的 causes 字段中记录错误链，每一项包含错误的类型、消息以及错误码（如果有）
+func LogCauseChain() LogOption {
+	return func(c *logConfig) {
+		c.causes = true
+	}
+}
+
+// newLogConfig 应用 opts 并返回日志配置
+func newLogConfig(opts []LogOption) logConfig {
+	var c logConfig
+	for _, opt := range opts {
+		if opt != nil {
+			opt(&c)
+		}
+	}
+	return c
+}
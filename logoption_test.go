@@ -0,0 +1,173 @@
+package errors_test
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	errstd "errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/go-anyway/framework-log"
+	"go.uber.org/zap"
+
+	"github.com/go-anyway/framework-errors"
+)
+
+// captureLogs 将全局 logger 的输出重定向到临时文件，返回 fn 执行期间记录的 JSON 日志
+func captureLogs(t *testing.T, fn func()) []map[string]interface{} {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "errors.log")
+	log.Init(log.WithFilename(path), log.WithOutputPaths(nil), log.WithFormat("json"), log.WithLevel("debug"))
+	defer log.Init()
+
+	fn()
+	_ = log.Sync()
+
+	f, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		t.Fatalf("os.Open() error = %v", err)
+	}
+	defer f.Close()
+
+	var entries []map[string]interface{}
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var entry map[string]interface{}
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			t.Fatalf("json.Unmarshal(%s) error = %v", scanner.Text(), err)
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+func TestLogAndReturnErrorOptions(t *testing.T) {
+	newErr := func() errors.StatusError {
+		return errors.WrapWithStatusOptions(errstd.New("connection refused"), errors.CodeDependencyUnavailable, "")
+	}
+
+	tests := []struct {
+		name  string
+		err   func() errors.StatusError
+		opts  []errors.LogOption
+		check func(t *testing.T, entries []map[string]interface{})
+	}{
+		{name: "默认", check: func(t *testing.T, entries []map[string]interface{}) {
+			if len(entries) != 1 || entries[0]["msg"] != "业务错误（影响稳定性）" {
+				t.Errorf("entries = %v", entries)
+			}
+			if _, ok := entries[0]["causes"]; ok {
+				t.Error("默认不应记录错误链")
+			}
+		}},
+		{name: "自定义消息与字段", opts: []errors.LogOption{errors.LogMessage("下单失败"), errors.LogFields(zap.String("order_id", "42"))}, check: func(t *testing.T, entries []map[string]interface{}) {
+			if len(entries) != 1 || entries[0]["msg"] != "下单失败" || entries[0]["order_id"] != "42" {
+				t.Errorf("entries = %v", entries)
+			}
+		}},
+		{name: "不记录日志", opts: []errors.LogOption{errors.SkipLog()}, check: func(t *testing.T, entries []map[string]interface{}) {
+			if len(entries) != 0 {
+				t.Errorf("entries = %v, want none", entries)
+			}
+		}},
+		{name: "记录错误链", opts: []errors.LogOption{errors.LogCauseChain()}, check: func(t *testing.T, entries []map[string]interface{}) {
+			causes, _ := entries[0]["causes"].([]interface{})
+			if len(causes) != 1 {
+				t.Fatalf("causes = %v", entries[0]["causes"])
+			}
+			cause, _ := causes[0].(map[string]interface{})
+			if cause["type"] != "*errors.errorString" || cause["message"] != "connection refused" {
+				t.Errorf("causes[0] = %v", cause)
+			}
+			if _, ok := cause["code"]; ok {
+				t.Errorf("causes[0] = %v, 普通错误不应包含 code", cause)
+			}
+		}},
+		{name: "错误链中的 StatusError", err: func() errors.StatusError { return errors.Annotate(newErr(), "下单") }, opts: []errors.LogOption{errors.LogCauseChain()}, check: func(t *testing.T, entries []map[string]interface{}) {
+			causes, _ := entries[0]["causes"].([]interface{})
+			first, _ := causes[0].(map[string]interface{})
+			if len(causes) != 2 || first["code"] != float64(errors.CodeDependencyUnavailable) {
+				t.Errorf("causes = %v", causes)
+			}
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.err == nil {
+				tt.err = newErr
+			}
+			var grpcErr error
+			entries := captureLogs(t, func() {
+				grpcErr = errors.LogAndReturnError(context.Background(), tt.err(), tt.opts...)
+			})
+			if grpcErr == nil {
+				t.Fatal("LogAndReturnError() = nil")
+			}
+			tt.check(t, entries)
+		})
+	}
+}
+
+func TestLogCauseChainScrubbed(t *testing.T) {
+	defer errors.ResetScrubbers()
+	errors.RegisterScrubber(func(key, value string) (string, bool) {
+		if key == "" && strings.Contains(value, "secret") {
+			return strings.ReplaceAll(value, "secret", "***"), true
+		}
+		return value, key != "" || !strings.Contains(value, "13800000000")
+	})
+
+	tests := []struct {
+		name  string
+		cause error
+		want  string
+	}{
+		{name: "替换敏感内容", cause: errstd.New("dial postgres://app:secret@db"), want: "dial postgres://app:***@db"},
+		{name: "丢弃整条消息", cause: errstd.New("user 13800000000 not found"), want: errors.RedactedValue},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entries := captureLogs(t, func() {
+				_ = errors.LogAndReturnError(context.Background(),
+					errors.WrapWithStatusOptions(tt.cause, errors.CodeDependencyUnavailable, ""), errors.LogCauseChain())
+			})
+			causes, _ := entries[0]["causes"].([]interface{})
+			cause, _ := causes[len(causes)-1].(map[string]interface{})
+			if cause["message"] != tt.want {
+				t.Errorf("causes = %v, want message %q", causes, tt.want)
+			}
+		})
+	}
+}
+
+func TestLogStackFrames(t *testing.T) {
+	err := errors.NewWithStatus(errors.CodeInternalError, "", errors.Extra("order_id", "42"))
+	frames := errors.StackFrames(err)
+	if len(frames) == 0 || frames[0].Func != "github.com/go-anyway/framework-errors_test.TestLogStackFrames" || frames[0].Line == 0 {
+		t.Fatalf("StackFrames() = %+v", frames)
+	}
+
+	entries := captureLogs(t, func() {
+		_ = errors.LogAndReturnError(context.Background(), err)
+	})
+	if len(entries) != 1 {
+		t.Fatalf("entries = %v", entries)
+	}
+	stack, _ := entries[0]["stack"].([]interface{})
+	if len(stack) != len(frames) {
+		t.Fatalf("stack = %v, want %d frames", entries[0]["stack"], len(frames))
+	}
+	if first, _ := stack[0].(map[string]interface{}); first["func"] != frames[0].Func || first["line"] != float64(frames[0].Line) {
+		t.Errorf("stack[0] = %v", first)
+	}
+	extra, _ := entries[0]["extra"].(map[string]interface{})
+	if _, ok := extra["stack"]; ok || extra["order_id"] != "42" {
+		t.Errorf("extra = %v, 不应包含 stack", extra)
+	}
+}
@@ -0,0 +1,100 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package errors
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// maintenanceWindow 是一个计划内的维护窗口
+type maintenanceWindow struct {
+	start, end time.Time
+	codes      map[int32]bool
+}
+
+var (
+	maintenanceMu sync.Mutex
+	// maintenanceWindows 只在设置时整体替换，读取时无需加锁
+	maintenanceWindows atomic.Pointer[[]*maintenanceWindow]
+)
+
+// SetMaintenanceWindow 设置 [start, end) 的维护窗口，返回提前结束该窗口的函数
+// 窗口内 codes 对应的错误不再影响稳定性：IsAffectStability 返回 false，严重程度降为 SeverityWarning，
+// 日志使用非稳定性错误的级别，不再生成参考编号，SLOImpactOf 返回 SLOImpactNone（通过 Impact 单独覆盖的除外）.
+// 用于计划内的迁移或维护，预期出现的依赖错误不会触发值班告警. 组合错误码（见 ComposeCode）按原错误码匹配.
+//
+//	end := errors.SetMaintenanceWindow(start, start.Add(2*time.Hour),
+//		errors.CodeDependencyUnavailable, errors.CodeDependencyTimeout)
+//	defer end()
+func SetMaintenanceWindow(start, end time.Time, codes ...int32) (cancel func()) {
+	if len(codes) == 0 || !end.After(start) {
+		return func() {}
+	}
+	w := &maintenanceWindow{start: start, end: end, codes: make(map[int32]bool, len(codes))}
+	for _, code := range codes {
+		w.codes[CanonicalCode(code)] = true
+	}
+
+	maintenanceMu.Lock()
+	defer maintenanceMu.Unlock()
+	t := now()
+	var list []*maintenanceWindow
+	if old := maintenanceWindows.Load(); old != nil {
+		for _, o := range *old {
+			// 顺便清理已经结束的窗口
+			if t.Before(o.end) {
+				list = append(list, o)
+			}
+		}
+	}
+	list = append(list, w)
+	maintenanceWindows.Store(&list)
+
+	return func() {
+		maintenanceMu.Lock()
+		defer maintenanceMu.Unlock()
+		old := maintenanceWindows.Load()
+		if old == nil {
+			return
+		}
+		list := make([]*maintenanceWindow, 0, len(*old))
+		for _, o := range *old {
+			if o != w {
+				list = append(list, o)
+			}
+		}
+		maintenanceWindows.Store(&list)
+	}
+}
+
+// inMaintenance 判断错误码当前是否处于维护窗口内
+func inMaintenance(code int32) bool {
+	list := maintenanceWindows.Load()
+	if list == nil || len(*list) == 0 {
+		return false
+	}
+	_, base := SplitCode(code)
+	t := now()
+	for _, w := range *list {
+		if !t.Before(w.start) && t.Before(w.end) && (w.codes[code] || w.codes[base]) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,58 @@
+package errors_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/go-anyway/framework-errors"
+)
+
+func TestSetMaintenanceWindow(t *testing.T) {
+	start := time.Date(2025, 1, 1, 2, 0, 0, 0, time.UTC)
+	current := start.Add(-time.Minute)
+	defer errors.SetClock(func() time.Time { return current })()
+
+	end := errors.SetMaintenanceWindow(start, start.Add(time.Hour), errors.CodeDependencyUnavailable)
+	defer end()
+
+	err := errors.NewWithStatus(errors.CodeDependencyUnavailable, "")
+	other := errors.NewWithStatus(errors.CodeInternalError, "")
+	composite := errors.NewWithStatus(errors.ComposeCode(7, errors.CodeDependencyUnavailable), "")
+
+	tests := []struct {
+		name      string
+		at        time.Time
+		err       errors.StatusError
+		stability bool
+	}{
+		{name: "窗口开始前", at: start.Add(-time.Minute), err: err, stability: true},
+		{name: "窗口内", at: start.Add(time.Minute), err: err, stability: false},
+		{name: "窗口内组合错误码", at: start.Add(time.Minute), err: composite, stability: false},
+		{name: "窗口内其他错误码", at: start.Add(time.Minute), err: other, stability: true},
+		{name: "窗口结束", at: start.Add(time.Hour), err: err, stability: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			current = tt.at
+			if got := tt.err.IsAffectStability(); got != tt.stability {
+				t.Errorf("IsAffectStability() = %v, want %v", got, tt.stability)
+			}
+			wantSeverity := errors.SeverityWarning
+			if tt.stability {
+				wantSeverity = errors.SeverityCritical
+			}
+			if got := errors.SeverityOf(tt.err); got != wantSeverity {
+				t.Errorf("SeverityOf() = %v, want %v", got, wantSeverity)
+			}
+			if got := errors.SLOImpactOf(tt.err); !tt.stability && got != errors.SLOImpactNone {
+				t.Errorf("SLOImpactOf() = %v, want none", got)
+			}
+		})
+	}
+
+	current = start.Add(time.Minute)
+	end()
+	if !err.IsAffectStability() {
+		t.Error("提前结束维护窗口后应恢复")
+	}
+}
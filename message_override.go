@@ -0,0 +1,88 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package errors
+
+import "strings"
+
+// messageOverrides 按语言存储运行时覆盖的消息，key 为小写的语言标签，由 messagesMu 保护
+var messageOverrides = map[string]map[int32]string{}
+
+// SetMessageOverride 在运行时覆盖错误码在某个语言下的消息，优先于 CodeDefinitions 与 RegisterMessages，
+// 产品团队可以通过配置中心或功能开关修正线上不合适的文案，无需重新发布. locale 为空时使用 DefaultLocale，
+// msg 为空时删除覆盖. 覆盖 DefaultLocale 时只影响之后创建的错误.
+//
+//	errors.SetMessageOverride(errors.CodeRateLimitExceeded, "en", "You're going too fast, please retry shortly")
+func SetMessageOverride(code int32, locale, msg string) {
+	if locale == "" {
+		locale = DefaultLocale
+	}
+	locale = strings.ToLower(locale)
+	messagesMu.Lock()
+	defer messagesMu.Unlock()
+	if msg == "" {
+		delete(messageOverrides[locale], code)
+		return
+	}
+	if messageOverrides[locale] == nil {
+		messageOverrides[locale] = make(map[int32]string)
+	}
+	messageOverrides[locale][code] = msg
+}
+
+// SetMessageOverrides 使用 overrides 替换全部运行时覆盖的消息，key 为语言标签，适合在配置变更时整体重新加载
+// overrides 为 nil 时清空所有覆盖.
+func SetMessageOverrides(overrides map[string]map[int32]string) {
+	next := make(map[string]map[int32]string, len(overrides))
+	for locale, msgs := range overrides {
+		if locale == "" {
+			locale = DefaultLocale
+		}
+		locale = strings.ToLower(locale)
+		if next[locale] == nil {
+			next[locale] = make(map[int32]string, len(msgs))
+		}
+		for code, msg := range msgs {
+			if msg != "" {
+				next[locale][code] = msg
+			}
+		}
+	}
+	messagesMu.Lock()
+	defer messagesMu.Unlock()
+	messageOverrides = next
+}
+
+// defaultMessage 返回错误码在 DefaultLocale 下的消息，运行时覆盖优先于 CodeDefinitions
+func defaultMessage(code int32) string {
+	messagesMu.RLock()
+	var msg string
+	var ok bool
+	// 没有覆盖时避免转换语言标签，创建错误的路径上不产生额外的内存分配
+	if len(messageOverrides) > 0 {
+		msg, ok = messageOverrides[strings.ToLower(DefaultLocale)][code]
+	}
+	messagesMu.RUnlock()
+	if ok {
+		return msg
+	}
+	return GetCodeDefinition(code).Message
+}
+
+// isDefaultMessage 判断 msg 是否为错误码的默认消息（包括运行时覆盖前后的消息）
+func isDefaultMessage(code int32, msg string) bool {
+	return msg == GetCodeDefinition(code).Message || msg == defaultMessage(code)
+}
@@ -0,0 +1,58 @@
+package errors_test
+
+import (
+	"testing"
+
+	"github.com/go-anyway/framework-errors"
+)
+
+func TestSetMessageOverride(t *testing.T) {
+	defer errors.SetMessageOverrides(nil)
+
+	errors.SetMessageOverride(errors.CodeRateLimitExceeded, "", "请稍后再试")
+	errors.SetMessageOverride(errors.CodeRateLimitExceeded, "en", "please slow down")
+
+	err := errors.NewWithStatus(errors.CodeRateLimitExceeded, "")
+	tests := []struct {
+		name   string
+		locale string
+		want   string
+	}{
+		{name: "默认语言", locale: "", want: "请稍后再试"},
+		{name: "覆盖的语言", locale: "en", want: "please slow down"},
+		{name: "覆盖主语言", locale: "en-US", want: "please slow down"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := errors.Localize(err, tt.locale); got != tt.want {
+				t.Errorf("Localize() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	// 自定义消息不受影响
+	if got := errors.Localize(errors.NewWithStatus(errors.CodeRateLimitExceeded, "custom"), "en"); got != "custom" {
+		t.Errorf("Localize() = %q, want custom", got)
+	}
+
+	errors.SetMessageOverride(errors.CodeRateLimitExceeded, "en", "")
+	if got, _ := errors.LocalizedMessage(errors.CodeRateLimitExceeded, "en"); got != "too many requests" {
+		t.Errorf("删除覆盖后 LocalizedMessage() = %q", got)
+	}
+}
+
+func TestSetMessageOverrides(t *testing.T) {
+	errors.Configure(errors.Config{MessageOverrides: map[string]map[int32]string{
+		"EN": {errors.CodeNotFound: "nothing here"},
+	}})
+	defer errors.Configure(errors.Config{})
+
+	if got, _ := errors.LocalizedMessage(errors.CodeNotFound, "en"); got != "nothing here" {
+		t.Errorf("LocalizedMessage() = %q, want nothing here", got)
+	}
+
+	errors.SetMessageOverrides(nil)
+	if got, _ := errors.LocalizedMessage(errors.CodeNotFound, "en"); got != "resource not found" {
+		t.Errorf("清空后 LocalizedMessage() = %q", got)
+	}
+}
@@ -0,0 +1,130 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package errors
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// Severity 是错误的严重程度，用作指标的标签
+type Severity string
+
+const (
+	// SeverityCritical 表示影响系统稳定性的错误
+	SeverityCritical Severity = "critical"
+	// SeverityWarning 表示不影响系统稳定性的错误，例如参数错误、资源不存在
+	SeverityWarning Severity = "warning"
+)
+
+// SeverityOf 返回错误的严重程度
+func SeverityOf(err StatusError) Severity {
+	if err != nil && err.IsAffectStability() {
+		return SeverityCritical
+	}
+	return SeverityWarning
+}
+
+// MetricsSink 接收错误相关的指标，Prometheus、OpenTelemetry 与 StatsD 的实现分别位于
+// prometheuserr、otelerr 与 statsderr 子包中. 方法在请求 goroutine 中同步调用，实现应当足够快.
+type MetricsSink interface {
+	// Increment 对错误码与严重程度对应的错误计数加一
+	Increment(code int32, severity Severity)
+	// ObserveLatency 记录请求的耗时，成功的请求 code 为 CodeSuccess
+	ObserveLatency(code int32, latency time.Duration)
+}
+
+var metricsSink atomic.Pointer[MetricsSink]
+
+// SetMetricsSink 设置 MetricsSink，nil 表示不记录指标（默认）
+// 设置后 LogAndReturnError 与 WriteError 处理的每个错误都会计数.
+//
+//	sink, _ := prometheuserr.New(prometheus.DefaultRegisterer)
+//	errors.SetMetricsSink(sink)
+func SetMetricsSink(s MetricsSink) {
+	if s == nil {
+		metricsSink.Store(nil)
+		return
+	}
+	metricsSink.Store(&s)
+}
+
+// ObserveLatency 将请求的耗时记录到 MetricsSink，err 为 nil 时使用 CodeSuccess
+// 非 StatusError 会先经过 Translate 转换. 适用于在中间件或拦截器中统计各错误码的耗时分布.
+func ObserveLatency(err error, latency time.Duration) {
+	if metricsSink.Load() == nil {
+		return
+	}
+	code := CodeSuccess
+	if err != nil {
+		code = Translate(err).Code()
+	}
+	observeLatency(code, latency)
+}
+
+// observeLatency 将错误码对应的耗时记录到 MetricsSink
+func observeLatency(code int32, latency time.Duration) {
+	if s := metricsSink.Load(); s != nil {
+		(*s).ObserveLatency(code, latency)
+	}
+}
+
+// DependencyMetricsSink 是 MetricsSink 的可选扩展，按依赖服务统计错误
+// MetricsSink 实现了该接口时，带有依赖服务（见 Dependency）的错误还会调用 IncrementDependency.
+type DependencyMetricsSink interface {
+	// IncrementDependency 对依赖服务与错误码对应的错误计数加一
+	IncrementDependency(dependency string, code int32)
+}
+
+// SLOMetricsSink 是 MetricsSink 的可选扩展，按 SLO 影响程度统计错误
+// MetricsSink 实现了该接口时，影响程度不为 SLOImpactNone 的错误还会调用 IncrementSLOImpact，
+// 错误预算消耗速率的告警可以只根据错误元数据计算.
+type SLOMetricsSink interface {
+	// IncrementSLOImpact 对错误码与影响程度对应的错误计数加一
+	IncrementSLOImpact(code int32, impact SLOImpact)
+}
+
+// countError 将错误计入 MetricsSink 与按错误码的错误数量
+func countError(err StatusError) {
+	if err == nil {
+		return
+	}
+	if s := metricsSink.Load(); s != nil {
+		(*s).Increment(err.Code(), SeverityOf(err))
+		if ss, ok := (*s).(SLOMetricsSink); ok {
+			if impact := SLOImpactOf(err); impact != SLOImpactNone {
+				ss.IncrementSLOImpact(err.Code(), impact)
+			}
+		}
+		if ds, ok := (*s).(DependencyMetricsSink); ok {
+			if dep := DependencyOf(err); dep != "" {
+				ds.IncrementDependency(dep, err.Code())
+			}
+		}
+	}
+	countErrorCode(err.Code())
+}
+
+// countDegraded 将降级成功的错误只计入 SLOMetricsSink
+// 兜底已经成功，不计入按严重级别的错误计数与按错误码的错误数量，避免降级被当作失败告警.
+func countDegraded(err StatusError) {
+	if s := metricsSink.Load(); s != nil {
+		if ss, ok := (*s).(SLOMetricsSink); ok {
+			ss.IncrementSLOImpact(err.Code(), SLOImpactDegraded)
+		}
+	}
+}
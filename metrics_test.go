@@ -0,0 +1,57 @@
+package errors_test
+
+import (
+	"context"
+	errstd "errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-anyway/framework-errors"
+)
+
+// fakeSink 记录 MetricsSink 收到的调用
+type fakeSink struct {
+	mu        sync.Mutex
+	counts    map[errors.Severity]map[int32]int
+	latencies map[int32]time.Duration
+}
+
+func newFakeSink() *fakeSink {
+	return &fakeSink{counts: map[errors.Severity]map[int32]int{}, latencies: map[int32]time.Duration{}}
+}
+
+func (s *fakeSink) Increment(code int32, severity errors.Severity) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.counts[severity] == nil {
+		s.counts[severity] = map[int32]int{}
+	}
+	s.counts[severity][code]++
+}
+
+func (s *fakeSink) ObserveLatency(code int32, latency time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.latencies[code] = latency
+}
+
+func TestMetricsSink(t *testing.T) {
+	sink := newFakeSink()
+	errors.SetMetricsSink(sink)
+	defer errors.SetMetricsSink(nil)
+
+	_ = errors.LogAndReturnError(context.Background(), errors.NewStatusError(errors.CodeNotFound, "", nil), errors.SkipLog())
+	errors.WriteError(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil), errstd.New("boom"))
+	errors.ObserveLatency(nil, time.Second)
+	errors.ObserveLatency(errors.NewStatusError(errors.CodeNotFound, "", nil), time.Millisecond)
+
+	if sink.counts[errors.SeverityWarning][errors.CodeNotFound] != 1 || sink.counts[errors.SeverityCritical][errors.CodeInternalError] != 1 {
+		t.Errorf("counts = %v", sink.counts)
+	}
+	if sink.latencies[errors.CodeSuccess] != time.Second || sink.latencies[errors.CodeNotFound] != time.Millisecond {
+		t.Errorf("latencies = %v", sink.latencies)
+	}
+}
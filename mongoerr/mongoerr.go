@@ -0,0 +1,92 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+// Package mongoerr 将 MongoDB 驱动返回的错误翻译为 StatusError.
+package mongoerr
+
+import (
+	stderrors "errors"
+
+	"github.com/go-anyway/framework-errors"
+
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/x/mongo/driver/topology"
+)
+
+// Translate 将 MongoDB 错误翻译为 StatusError，其他错误返回 nil
+func Translate(err error) errors.StatusError {
+	return translate(err)
+}
+
+// Wrap 翻译 MongoDB 错误并在 Extra 中记录集合名与操作名
+// 非 MongoDB 错误通过 errors.Translate 处理.
+//
+//	if err := coll.FindOne(ctx, filter).Decode(&u); err != nil {
+//		return mongoerr.Wrap(err, "users", "FindOne")
+//	}
+func Wrap(err error, collection, operation string) errors.StatusError {
+	if err == nil {
+		return nil
+	}
+
+	opts := []errors.Option{
+		errors.Extra("collection", collection),
+		errors.Extra("operation", operation),
+	}
+	if se := translate(err, opts...); se != nil {
+		return se
+	}
+
+	fallback := errors.Translate(err)
+	return errors.WrapWithStatusOptions(err, fallback.Code(), fallback.Msg(), append([]errors.Option{errors.OverrideCode()}, opts...)...)
+}
+
+// translate 根据错误类型选择业务错误码和可重试标记
+func translate(err error, opts ...errors.Option) errors.StatusError {
+	if err == nil {
+		return nil
+	}
+
+	code := errors.CodeInternalError
+	retryable := false
+
+	var serverErr mongo.ServerError
+	var selectionErr topology.ServerSelectionError
+	switch {
+	case stderrors.Is(err, mongo.ErrNoDocuments):
+		code = errors.CodeNotFound
+	case mongo.IsDuplicateKeyError(err):
+		code = errors.CodeAlreadyExists
+	case stderrors.As(err, &selectionErr):
+		code = errors.CodeRequestTimeout
+		retryable = true
+	case mongo.IsTimeout(err):
+		code = errors.CodeRequestTimeout
+		retryable = true
+	case mongo.IsNetworkError(err):
+		retryable = true
+	case stderrors.As(err, &serverErr):
+		retryable = serverErr.HasErrorLabel("RetryableWriteError") ||
+			serverErr.HasErrorLabel("TransientTransactionError")
+	default:
+		return nil
+	}
+
+	if retryable {
+		opts = append(opts, errors.Retryable(true))
+	}
+	return errors.WrapWithStatusOptions(err, code, "", append([]errors.Option{errors.OverrideCode()}, opts...)...)
+}
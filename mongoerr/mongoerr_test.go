@@ -0,0 +1,87 @@
+package mongoerr_test
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+	"testing"
+
+	"github.com/go-anyway/framework-errors"
+	"github.com/go-anyway/framework-errors/mongoerr"
+
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/x/mongo/driver/topology"
+)
+
+func TestTranslate(t *testing.T) {
+	tests := []struct {
+		name      string
+		err       error
+		expected  int32
+		retryable bool
+	}{
+		{"文档不存在", mongo.ErrNoDocuments, errors.CodeNotFound, false},
+		{
+			"重复键",
+			mongo.WriteException{WriteErrors: []mongo.WriteError{{Code: 11000, Message: "E11000"}}},
+			errors.CodeAlreadyExists,
+			false,
+		},
+		{
+			"服务器选择超时",
+			topology.ServerSelectionError{Wrapped: context.DeadlineExceeded},
+			errors.CodeRequestTimeout,
+			true,
+		},
+		{
+			"可重试写错误",
+			mongo.CommandError{Code: 91, Labels: []string{"RetryableWriteError"}},
+			errors.CodeInternalError,
+			true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			se := mongoerr.Translate(fmt.Errorf("op: %w", tt.err))
+			if se == nil {
+				t.Fatal("Translate() = nil, want StatusError")
+			}
+			if se.Code() != tt.expected {
+				t.Errorf("Code() = %d, want %d", se.Code(), tt.expected)
+			}
+			if errors.IsRetryable(se) != tt.retryable {
+				t.Errorf("IsRetryable() = %v, want %v", errors.IsRetryable(se), tt.retryable)
+			}
+		})
+	}
+}
+
+func TestTranslateUnknown(t *testing.T) {
+	if se := mongoerr.Translate(stderrors.New("other")); se != nil {
+		t.Errorf("Translate() = %v, want nil", se)
+	}
+}
+
+func TestWrap(t *testing.T) {
+	se := mongoerr.Wrap(mongo.ErrNoDocuments, "users", "FindOne")
+	if se.Code() != errors.CodeNotFound {
+		t.Errorf("Code() = %d, want %d", se.Code(), errors.CodeNotFound)
+	}
+	extra := se.Extra()
+	if extra["collection"] != "users" || extra["operation"] != "FindOne" {
+		t.Errorf("Extra = %v, want collection and operation", extra)
+	}
+
+	se = mongoerr.Wrap(stderrors.New("other"), "users", "InsertOne")
+	if se.Code() != errors.CodeInternalError {
+		t.Errorf("Code() = %d, want %d", se.Code(), errors.CodeInternalError)
+	}
+	if se.Extra()["collection"] != "users" {
+		t.Errorf("Extra[collection] = %s, want users", se.Extra()["collection"])
+	}
+
+	if mongoerr.Wrap(nil, "users", "FindOne") != nil {
+		t.Error("Wrap(nil) 应返回 nil")
+	}
+}
@@ -0,0 +1,38 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package errors
+
+// Must 在 err 不为 nil 时 panic，panic 的值是带调用堆栈的 StatusError
+// 普通错误被包装为 CodeInternalError 并保留为 cause，已经是 StatusError 的错误沿用其错误码.
+// 适用于程序启动、全局变量初始化等无法继续执行的场景，使启动失败与运行时错误具有相同的结构.
+//
+//	errors.Must(db.Ping())
+func Must(err error) {
+	if err != nil {
+		panic(WrapWithStatusOptions(err, CodeInternalError, ""))
+	}
+}
+
+// MustValue 在 err 不为 nil 时以 StatusError panic，否则返回 v，见 Must
+//
+//	cfg := errors.MustValue(config.Load("app.yaml"))
+func MustValue[T any](v T, err error) T {
+	if err != nil {
+		panic(WrapWithStatusOptions(err, CodeInternalError, ""))
+	}
+	return v
+}
@@ -0,0 +1,60 @@
+package errors_test
+
+import (
+	errstd "errors"
+	"strings"
+	"testing"
+
+	"github.com/go-anyway/framework-errors"
+)
+
+// recoverStatus 执行 fn 并返回 panic 的值
+func recoverStatus(fn func()) (rec interface{}) {
+	defer func() { rec = recover() }()
+	fn()
+	return nil
+}
+
+func TestMust(t *testing.T) {
+	cause := errstd.New("dial tcp: connection refused")
+
+	tests := []struct {
+		name     string
+		fn       func()
+		wantCode int32
+	}{
+		{name: "没有错误", fn: func() { errors.Must(nil) }},
+		{name: "普通错误", fn: func() { errors.Must(cause) }, wantCode: errors.CodeInternalError},
+		{
+			name:     "StatusError 沿用错误码",
+			fn:       func() { errors.Must(errors.NewWithStatus(errors.CodeInvalidParam, "配置无效")) },
+			wantCode: errors.CodeInvalidParam,
+		},
+		{name: "MustValue", fn: func() { _ = errors.MustValue(0, cause) }, wantCode: errors.CodeInternalError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := recoverStatus(tt.fn)
+			if tt.wantCode == 0 {
+				if rec != nil {
+					t.Fatalf("panic = %v, want none", rec)
+				}
+				return
+			}
+			se, ok := rec.(errors.StatusError)
+			if !ok || se.Code() != tt.wantCode {
+				t.Fatalf("panic = %#v, want StatusError with code %d", rec, tt.wantCode)
+			}
+			if !strings.Contains(se.Extra()["stack"], "TestMust") {
+				t.Errorf("stack = %q, want caller frame", se.Extra()["stack"])
+			}
+			if tt.wantCode == errors.CodeInternalError && !errstd.Is(se, cause) {
+				t.Error("errors.Is(panic, cause) = false")
+			}
+		})
+	}
+
+	if got := errors.MustValue("ok", nil); got != "ok" {
+		t.Errorf("MustValue() = %q, want ok", got)
+	}
+}
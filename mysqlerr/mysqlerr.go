@@ -0,0 +1,71 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+// Package mysqlerr 根据错误号将 go-sql-driver/mysql 错误翻译为 StatusError.
+package mysqlerr
+
+import (
+	stderrors "errors"
+	"strconv"
+
+	"github.com/go-anyway/framework-errors"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+// errorNumber 描述一个 MySQL 错误号对应的业务错误码
+type errorNumber struct {
+	code      int32
+	retryable bool
+}
+
+// errorNumbers 是 MySQL 错误号到业务错误码的映射
+// 参见 https://dev.mysql.com/doc/mysql-errors/8.0/en/server-error-reference.html
+var errorNumbers = map[uint16]errorNumber{
+	1062: {code: errors.CodeAlreadyExists},                   // ER_DUP_ENTRY
+	1048: {code: errors.CodeInvalidParam},                    // ER_BAD_NULL_ERROR
+	1451: {code: errors.CodeInvalidParam},                    // ER_ROW_IS_REFERENCED_2
+	1452: {code: errors.CodeInvalidParam},                    // ER_NO_REFERENCED_ROW_2
+	1213: {code: errors.CodeConflict, retryable: true},       // ER_LOCK_DEADLOCK
+	1205: {code: errors.CodeRequestTimeout, retryable: true}, // ER_LOCK_WAIT_TIMEOUT
+	3024: {code: errors.CodeRequestTimeout},                  // ER_QUERY_TIMEOUT
+	1146: {code: errors.CodeInternalError},                   // ER_NO_SUCH_TABLE，影响稳定性
+}
+
+// Translate 将 *mysql.MySQLError 翻译为 StatusError，其他错误返回 nil
+// 错误号记录在 Extra["mysql_errno"] 中.
+func Translate(err error) errors.StatusError {
+	if err == nil {
+		return nil
+	}
+
+	var myErr *mysql.MySQLError
+	if !stderrors.As(err, &myErr) {
+		return nil
+	}
+
+	num, ok := errorNumbers[myErr.Number]
+	if !ok {
+		num = errorNumber{code: errors.CodeInternalError}
+	}
+
+	opts := []errors.Option{errors.Extra("mysql_errno", strconv.Itoa(int(myErr.Number))), errors.OverrideCode()}
+	if num.retryable {
+		opts = append(opts, errors.Retryable(true))
+	}
+
+	return errors.WrapWithStatusOptions(err, num.code, "", opts...)
+}
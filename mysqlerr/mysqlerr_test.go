@@ -0,0 +1,56 @@
+package mysqlerr_test
+
+import (
+	stderrors "errors"
+	"fmt"
+	"testing"
+
+	"github.com/go-anyway/framework-errors"
+	"github.com/go-anyway/framework-errors/mysqlerr"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+func TestTranslate(t *testing.T) {
+	tests := []struct {
+		name      string
+		number    uint16
+		expected  int32
+		retryable bool
+		stability bool
+	}{
+		{"重复键", 1062, errors.CodeAlreadyExists, false, false},
+		{"死锁", 1213, errors.CodeConflict, true, false},
+		{"锁等待超时", 1205, errors.CodeRequestTimeout, true, false},
+		{"表不存在", 1146, errors.CodeInternalError, false, true},
+		{"未知错误号", 9999, errors.CodeInternalError, false, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := fmt.Errorf("exec: %w", &mysql.MySQLError{Number: tt.number, Message: "boom"})
+			se := mysqlerr.Translate(err)
+			if se == nil {
+				t.Fatal("Translate() = nil, want StatusError")
+			}
+			if se.Code() != tt.expected {
+				t.Errorf("Code() = %d, want %d", se.Code(), tt.expected)
+			}
+			if errors.IsRetryable(se) != tt.retryable {
+				t.Errorf("IsRetryable() = %v, want %v", errors.IsRetryable(se), tt.retryable)
+			}
+			if se.IsAffectStability() != tt.stability {
+				t.Errorf("IsAffectStability() = %v, want %v", se.IsAffectStability(), tt.stability)
+			}
+			if se.Extra()["mysql_errno"] != fmt.Sprint(tt.number) {
+				t.Errorf("Extra[mysql_errno] = %s, want %d", se.Extra()["mysql_errno"], tt.number)
+			}
+		})
+	}
+}
+
+func TestTranslateUnknown(t *testing.T) {
+	if se := mysqlerr.Translate(stderrors.New("other")); se != nil {
+		t.Errorf("Translate() = %v, want nil", se)
+	}
+}
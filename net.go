@@ -0,0 +1,95 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package errors
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"net"
+	"syscall"
+)
+
+// TranslateNetErr 将网络错误翻译为依赖错误码族（3000-3999）的 StatusError
+// 识别 DNS 错误、TLS 握手失败、连接被拒绝/重置以及 net.Error 超时，其他错误（包括 StatusError）返回 nil.
+// context 错误同样实现了 net.Error，但属于调用方自身的取消或超时，返回 nil 交给 FromContextErr 处理.
+// 可以直接用于 RegisterTranslator，Translate 也会在最后尝试它.
+func TranslateNetErr(err error) StatusError {
+	if err == nil {
+		return nil
+	}
+
+	// StatusError 本身实现了 net.Error，已经翻译过的错误不再处理
+	var se StatusError
+	if errors.As(err, &se) || contextCause(err) != nil {
+		return nil
+	}
+
+	var opts []Option
+	var code int32
+
+	var dnsErr *net.DNSError
+	var opErr *net.OpError
+	var netErr net.Error
+	switch {
+	case errors.As(err, &dnsErr):
+		code = CodeDependencyDNSFailure
+		if dnsErr.IsTimeout {
+			code = CodeDependencyTimeout
+		}
+		opts = append(opts, Extra("host", dnsErr.Name))
+	case isTLSErr(err):
+		code = CodeDependencyTLSFailure
+	case errors.Is(err, syscall.ECONNREFUSED),
+		errors.Is(err, syscall.ECONNRESET),
+		errors.Is(err, syscall.ECONNABORTED),
+		errors.Is(err, syscall.EHOSTUNREACH),
+		errors.Is(err, syscall.ENETUNREACH),
+		errors.Is(err, syscall.EPIPE):
+		code = CodeDependencyUnavailable
+	case errors.As(err, &netErr):
+		if netErr.Timeout() {
+			code = CodeDependencyTimeout
+		} else {
+			code = CodeDependencyUnavailable
+		}
+	default:
+		return nil
+	}
+
+	if errors.As(err, &opErr) && opErr.Addr != nil {
+		opts = append(opts, Extra("addr", opErr.Addr.String()))
+	}
+
+	return WrapWithStatusOptions(err, code, "", append([]Option{OverrideCode()}, opts...)...)
+}
+
+// isTLSErr 判断 err 链中是否包含 TLS 握手或证书校验错误
+func isTLSErr(err error) bool {
+	var recordErr tls.RecordHeaderError
+	var alertErr tls.AlertError
+	var verifyErr *tls.CertificateVerificationError
+	var unknownAuthErr x509.UnknownAuthorityError
+	var hostnameErr x509.HostnameError
+	var certInvalidErr x509.CertificateInvalidError
+	return errors.As(err, &recordErr) ||
+		errors.As(err, &alertErr) ||
+		errors.As(err, &verifyErr) ||
+		errors.As(err, &unknownAuthErr) ||
+		errors.As(err, &hostnameErr) ||
+		errors.As(err, &certInvalidErr)
+}
@@ -0,0 +1,148 @@
+package errors_test
+
+import (
+	"context"
+	"crypto/x509"
+	errstd "errors"
+	"fmt"
+	"net"
+	"os"
+	"syscall"
+	"testing"
+
+	"github.com/go-anyway/framework-errors"
+)
+
+// timeoutErr 是一个超时的 net.Error
+type timeoutErr struct{}
+
+func (timeoutErr) Error() string   { return "i/o timeout" }
+func (timeoutErr) Timeout() bool   { return true }
+func (timeoutErr) Temporary() bool { return true }
+
+func TestTranslateNetErr(t *testing.T) {
+	addr := &net.TCPAddr{IP: net.IPv4(10, 0, 0, 1), Port: 3306}
+	tests := []struct {
+		name      string
+		err       error
+		expected  int32
+		retryable bool
+	}{
+		{
+			"连接被拒绝",
+			&net.OpError{Op: "dial", Net: "tcp", Addr: addr, Err: os.NewSyscallError("connect", syscall.ECONNREFUSED)},
+			errors.CodeDependencyUnavailable,
+			true,
+		},
+		{
+			"连接被重置",
+			fmt.Errorf("read: %w", syscall.ECONNRESET),
+			errors.CodeDependencyUnavailable,
+			true,
+		},
+		{
+			"超时",
+			&net.OpError{Op: "read", Net: "tcp", Addr: addr, Err: timeoutErr{}},
+			errors.CodeDependencyTimeout,
+			true,
+		},
+		{
+			"DNS 错误",
+			&net.DNSError{Err: "no such host", Name: "db.internal", IsNotFound: true},
+			errors.CodeDependencyDNSFailure,
+			true,
+		},
+		{
+			"TLS 证书错误",
+			fmt.Errorf("handshake: %w", x509.UnknownAuthorityError{}),
+			errors.CodeDependencyTLSFailure,
+			false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			se := errors.TranslateNetErr(tt.err)
+			if se == nil {
+				t.Fatal("TranslateNetErr() = nil, want StatusError")
+			}
+			if se.Code() != tt.expected {
+				t.Errorf("Code() = %d, want %d", se.Code(), tt.expected)
+			}
+			if errors.IsRetryable(se) != tt.retryable {
+				t.Errorf("IsRetryable() = %v, want %v", errors.IsRetryable(se), tt.retryable)
+			}
+			if !se.IsAffectStability() {
+				t.Error("依赖错误应影响稳定性")
+			}
+		})
+	}
+}
+
+func TestTranslateNetErrExtra(t *testing.T) {
+	err := &net.OpError{
+		Op:   "dial",
+		Net:  "tcp",
+		Addr: &net.TCPAddr{IP: net.IPv4(10, 0, 0, 1), Port: 3306},
+		Err:  syscall.ECONNREFUSED,
+	}
+	se := errors.TranslateNetErr(err)
+	if se.Extra()["addr"] != "10.0.0.1:3306" {
+		t.Errorf("Extra[addr] = %s, want 10.0.0.1:3306", se.Extra()["addr"])
+	}
+}
+
+func TestTranslateNetErrUnknown(t *testing.T) {
+	if se := errors.TranslateNetErr(errstd.New("other")); se != nil {
+		t.Errorf("TranslateNetErr() = %v, want nil", se)
+	}
+	if se := errors.Translate(syscall.ECONNREFUSED); se.Code() != errors.CodeDependencyUnavailable {
+		t.Errorf("Translate() code = %d, want %d", se.Code(), errors.CodeDependencyUnavailable)
+	}
+}
+
+func TestTranslateNetErrContext(t *testing.T) {
+	errors.RegisterTranslator(errors.TranslateNetErr)
+	defer errors.ResetTranslators()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 0)
+	defer cancel()
+	<-ctx.Done()
+
+	if se := errors.TranslateNetErr(ctx.Err()); se != nil {
+		t.Errorf("TranslateNetErr() = %v, want nil", se)
+	}
+	se := errors.Translate(fmt.Errorf("query: %w", ctx.Err()))
+	if se.Code() != errors.CodeRequestTimeout || se.IsAffectStability() {
+		t.Errorf("Translate() = (%d, %v), want (%d, false)", se.Code(), se.IsAffectStability(), errors.CodeRequestTimeout)
+	}
+}
+
+func TestStatusErrorImplementsNetError(t *testing.T) {
+	tests := []struct {
+		name      string
+		err       error
+		timeout   bool
+		temporary bool
+	}{
+		{"请求超时", errors.NewStatusError(errors.CodeRequestTimeout, "", nil), true, true},
+		{"依赖超时", errors.NewWithStatus(errors.CodeDependencyTimeout, ""), true, true},
+		{"依赖不可用", errors.NewWithStatus(errors.CodeDependencyUnavailable, ""), false, true},
+		{"参数错误", errors.NewStatusError(errors.CodeInvalidParam, "", nil), false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var netErr net.Error
+			if !errstd.As(tt.err, &netErr) {
+				t.Fatal("StatusError 应实现 net.Error")
+			}
+			if netErr.Timeout() != tt.timeout {
+				t.Errorf("Timeout() = %v, want %v", netErr.Timeout(), tt.timeout)
+			}
+			if netErr.Temporary() != tt.temporary {
+				t.Errorf("Temporary() = %v, want %v", netErr.Temporary(), tt.temporary)
+			}
+		})
+	}
+}
@@ -0,0 +1,132 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package errors
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+)
+
+// OpenAPIErrorSchema 是 OpenAPIComponents 生成的错误响应 schema 的名称
+const OpenAPIErrorSchema = "ErrorResponse"
+
+// OpenAPIComponents 根据已注册的错误码生成 OpenAPI 3 的 components
+// 包括描述 Envelope 的 ErrorResponse schema、每个错误码的示例（Error<code>），
+// 以及按 HTTP 状态码分组、引用这些示例的响应（Error<status>），
+// 接口文档可以通过 $ref: '#/components/responses/Error404' 引用. 结果可以直接序列化为 JSON 或 YAML.
+func OpenAPIComponents() map[string]interface{} {
+	codes := make([]int32, 0, len(CodeDefinitions))
+	for code := range CodeDefinitions {
+		if code != CodeSuccess {
+			codes = append(codes, code)
+		}
+	}
+	sort.Slice(codes, func(i, j int) bool { return codes[i] < codes[j] })
+
+	enum := make([]interface{}, len(codes))
+	examples := make(map[string]interface{}, len(codes))
+	byStatus := make(map[int]map[string]interface{})
+	for i, code := range codes {
+		enum[i] = code
+		name := "Error" + strconv.Itoa(int(code))
+		def := GetCodeDefinition(code)
+		examples[name] = map[string]interface{}{
+			"summary": def.Message,
+			"value":   map[string]interface{}{"code": code, "reason": def.Reason, "message": def.Message},
+		}
+
+		status := HTTPStatus(code)
+		if byStatus[status] == nil {
+			byStatus[status] = make(map[string]interface{})
+		}
+		byStatus[status][strconv.Itoa(int(code))] = map[string]interface{}{
+			"$ref": "#/components/examples/" + name,
+		}
+	}
+
+	responses := make(map[string]interface{}, len(byStatus))
+	for status, refs := range byStatus {
+		description := http.StatusText(status)
+		if description == "" {
+			description = "Error " + strconv.Itoa(status)
+		}
+		responses["Error"+strconv.Itoa(status)] = map[string]interface{}{
+			"description": description,
+			"content": map[string]interface{}{
+				"application/json": map[string]interface{}{
+					"schema":   map[string]interface{}{"$ref": "#/components/schemas/" + OpenAPIErrorSchema},
+					"examples": refs,
+				},
+			},
+		}
+	}
+
+	return map[string]interface{}{
+		"schemas":   map[string]interface{}{OpenAPIErrorSchema: errorResponseSchema(enum)},
+		"examples":  examples,
+		"responses": responses,
+	}
+}
+
+// errorResponseSchema 返回描述 Envelope 的 schema
+func errorResponseSchema(enum []interface{}) map[string]interface{} {
+	stringMap := map[string]interface{}{
+		"type":                 "object",
+		"additionalProperties": map[string]interface{}{"type": "string"},
+	}
+	return map[string]interface{}{
+		"type":     "object",
+		"required": []string{"code", "message"},
+		"properties": map[string]interface{}{
+			"code": map[string]interface{}{
+				"type":        "integer",
+				"format":      "int32",
+				"description": "业务错误码",
+				"enum":        enum,
+			},
+			"reason": map[string]interface{}{
+				"type":        "string",
+				"description": "错误原因，即错误码的符号名称",
+			},
+			"message": map[string]interface{}{
+				"type":        "string",
+				"description": "错误消息",
+			},
+			"extra": stringMap,
+			"debug": map[string]interface{}{
+				"type":        "object",
+				"description": "只在开启调试模式时输出",
+				"properties": map[string]interface{}{
+					"stack":    map[string]interface{}{"type": "string"},
+					"causes":   map[string]interface{}{"type": "array", "items": map[string]interface{}{"type": "string"}},
+					"internal": stringMap,
+				},
+			},
+		},
+	}
+}
+
+// WriteOpenAPIComponents 将 OpenAPIComponents 以 {"components": ...} 的 JSON 格式写入 w
+// 输出可以合并到服务的 OpenAPI 文档中.
+func WriteOpenAPIComponents(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(map[string]interface{}{"components": OpenAPIComponents()})
+}
@@ -0,0 +1,55 @@
+package errors_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/go-anyway/framework-errors"
+)
+
+func TestWriteOpenAPIComponents(t *testing.T) {
+	var buf bytes.Buffer
+	if err := errors.WriteOpenAPIComponents(&buf); err != nil {
+		t.Fatalf("WriteOpenAPIComponents() error = %v", err)
+	}
+
+	var doc struct {
+		Components struct {
+			Schemas  map[string]map[string]interface{} `json:"schemas"`
+			Examples map[string]struct {
+				Value errors.Envelope `json:"value"`
+			} `json:"examples"`
+			Responses map[string]struct {
+				Description string `json:"description"`
+				Content     map[string]struct {
+					Examples map[string]map[string]string `json:"examples"`
+				} `json:"content"`
+			} `json:"responses"`
+		} `json:"components"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &doc); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+
+	if _, ok := doc.Components.Schemas[errors.OpenAPIErrorSchema]; !ok {
+		t.Errorf("schemas = %v, 应包含 %s", doc.Components.Schemas, errors.OpenAPIErrorSchema)
+	}
+	if got := doc.Components.Examples["Error1004"].Value; got.Code != errors.CodeNotFound || got.Message != "资源未找到" {
+		t.Errorf("examples[Error1004] = %+v", got)
+	}
+	if _, ok := doc.Components.Examples["Error200"]; ok {
+		t.Error("examples 不应包含 CodeSuccess")
+	}
+
+	notFound, ok := doc.Components.Responses["Error404"]
+	if !ok || notFound.Description != "Not Found" {
+		t.Fatalf("responses[Error404] = %+v", notFound)
+	}
+	refs := notFound.Content["application/json"].Examples
+	for _, code := range []string{"1004", "1009", "2001"} {
+		if refs[code]["$ref"] != "#/components/examples/Error"+code {
+			t.Errorf("responses[Error404] examples[%s] = %v", code, refs[code])
+		}
+	}
+}
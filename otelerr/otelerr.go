@@ -0,0 +1,100 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+// Package otelerr 提供基于 OpenTelemetry Metrics 的 errors.MetricsSink 实现.
+package otelerr
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/go-anyway/framework-errors"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// Sink 将错误计数与请求耗时记录为 OpenTelemetry 指标
+// 计数器为 framework.errors.count，属性为 code 与 severity；
+// 直方图为 framework.errors.latency，单位为秒，属性为 code；
+// 依赖服务计数器为 framework.errors.dependency.count，属性为 dependency 与 code；
+// SLO 影响计数器为 framework.errors.slo_impact.count，属性为 code 与 impact.
+type Sink struct {
+	count      metric.Int64Counter
+	latency    metric.Float64Histogram
+	dependency metric.Int64Counter
+	sloImpact  metric.Int64Counter
+}
+
+// New 使用 meter 创建 Sink
+//
+//	sink, err := otelerr.New(otel.Meter("github.com/go-anyway/framework-errors"))
+func New(meter metric.Meter) (*Sink, error) {
+	count, err := meter.Int64Counter("framework.errors.count",
+		metric.WithDescription("按错误码与严重程度统计的错误数量."))
+	if err != nil {
+		return nil, err
+	}
+	latency, err := meter.Float64Histogram("framework.errors.latency",
+		metric.WithDescription("按错误码统计的请求耗时."),
+		metric.WithUnit("s"))
+	if err != nil {
+		return nil, err
+	}
+	dependency, err := meter.Int64Counter("framework.errors.dependency.count",
+		metric.WithDescription("按依赖服务与错误码统计的错误数量."))
+	if err != nil {
+		return nil, err
+	}
+	sloImpact, err := meter.Int64Counter("framework.errors.slo_impact.count",
+		metric.WithDescription("按错误码与 SLO 影响程度统计的错误数量."))
+	if err != nil {
+		return nil, err
+	}
+	return &Sink{count: count, latency: latency, dependency: dependency, sloImpact: sloImpact}, nil
+}
+
+// Increment 实现 errors.MetricsSink 接口
+func (s *Sink) Increment(code int32, severity errors.Severity) {
+	s.count.Add(context.Background(), 1, metric.WithAttributes(
+		attribute.String("code", strconv.Itoa(int(code))),
+		attribute.String("severity", string(severity)),
+	))
+}
+
+// ObserveLatency 实现 errors.MetricsSink 接口
+func (s *Sink) ObserveLatency(code int32, latency time.Duration) {
+	s.latency.Record(context.Background(), latency.Seconds(), metric.WithAttributes(
+		attribute.String("code", strconv.Itoa(int(code))),
+	))
+}
+
+// IncrementDependency 实现 errors.DependencyMetricsSink 接口
+func (s *Sink) IncrementDependency(dependency string, code int32) {
+	s.dependency.Add(context.Background(), 1, metric.WithAttributes(
+		attribute.String("dependency", dependency),
+		attribute.String("code", strconv.Itoa(int(code))),
+	))
+}
+
+// IncrementSLOImpact 实现 errors.SLOMetricsSink 接口
+func (s *Sink) IncrementSLOImpact(code int32, impact errors.SLOImpact) {
+	s.sloImpact.Add(context.Background(), 1, metric.WithAttributes(
+		attribute.String("code", strconv.Itoa(int(code))),
+		attribute.String("impact", impact.String()),
+	))
+}
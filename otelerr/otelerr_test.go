@@ -0,0 +1,77 @@
+package otelerr_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-anyway/framework-errors"
+	"github.com/go-anyway/framework-errors/otelerr"
+
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+func TestSink(t *testing.T) {
+	reader := sdkmetric.NewManualReader()
+	provider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+	sink, err := otelerr.New(provider.Meter("test"))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	sink.Increment(errors.CodeNotFound, errors.SeverityWarning)
+	sink.Increment(errors.CodeNotFound, errors.SeverityWarning)
+	sink.ObserveLatency(errors.CodeNotFound, 20*time.Millisecond)
+
+	var rm metricdata.ResourceMetrics
+	if err := reader.Collect(context.Background(), &rm); err != nil {
+		t.Fatalf("Collect() error = %v", err)
+	}
+
+	found := map[string]bool{}
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			found[m.Name] = true
+			if sum, ok := m.Data.(metricdata.Sum[int64]); ok {
+				if len(sum.DataPoints) != 1 || sum.DataPoints[0].Value != 2 {
+					t.Errorf("%s = %+v", m.Name, sum.DataPoints)
+				}
+				if v, _ := sum.DataPoints[0].Attributes.Value("severity"); v.AsString() != "warning" {
+					t.Errorf("severity = %v, want warning", v.AsString())
+				}
+			}
+		}
+	}
+	if !found["framework.errors.count"] || !found["framework.errors.latency"] {
+		t.Errorf("metrics = %v", found)
+	}
+}
+
+func TestSinkDependency(t *testing.T) {
+	reader := sdkmetric.NewManualReader()
+	provider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+	sink, err := otelerr.New(provider.Meter("test"))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	sink.IncrementDependency("payment-api", errors.CodeDependencyTimeout)
+
+	var rm metricdata.ResourceMetrics
+	if err := reader.Collect(context.Background(), &rm); err != nil {
+		t.Fatalf("Collect() error = %v", err)
+	}
+	var dependency string
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			if sum, ok := m.Data.(metricdata.Sum[int64]); ok && m.Name == "framework.errors.dependency.count" {
+				v, _ := sum.DataPoints[0].Attributes.Value("dependency")
+				dependency = v.AsString()
+			}
+		}
+	}
+	if dependency != "payment-api" {
+		t.Errorf("dependency = %q, want payment-api", dependency)
+	}
+}
@@ -0,0 +1,109 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package errors
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+)
+
+// 权限错误在扩展信息中的 key
+const (
+	PermissionResourceKey = "permission_resource"
+	PermissionActionKey   = "permission_action"
+	// PermissionScopesKey 的值为逗号分隔的缺少的授权范围
+	PermissionScopesKey = "missing_scopes"
+)
+
+// PermissionDenial 是权限错误携带的结构化信息
+type PermissionDenial struct {
+	// Resource 是被访问的资源，例如 "orders/123"
+	Resource string
+	// Action 是被拒绝的操作，例如 "delete"
+	Action string
+	// MissingScopes 是调用方缺少的授权范围（OAuth scope 或权限点）
+	MissingScopes []string
+}
+
+// NewPermissionDenied 创建携带资源、操作与缺少的授权范围的 CodeForbidden 错误
+// 信息随扩展信息在 gRPC details 与 HTTP 响应体中传递，API 网关与客户端可以据此申请对应的授权范围；
+// 缺少授权范围时 WriteError 还会按 RFC 6750 输出 WWW-Authenticate: Bearer error="insufficient_scope" 响应头.
+//
+//	return errors.NewPermissionDenied("orders/"+id, "delete", []string{"orders:write"})
+func NewPermissionDenied(resource, action string, missingScopes []string, opts ...Option) StatusError {
+	var denial []Option
+	if resource != "" {
+		denial = append(denial, Extra(PermissionResourceKey, resource))
+	}
+	if action != "" {
+		denial = append(denial, Extra(PermissionActionKey, action))
+	}
+	if len(missingScopes) > 0 {
+		denial = append(denial, Extra(PermissionScopesKey, strings.Join(missingScopes, ",")))
+	}
+	return NewWithStatus(CodeForbidden, "", append(denial, opts...)...)
+}
+
+// Permission 返回权限错误信息，没有时第二个返回值为 false
+func (e *statusError) Permission() (PermissionDenial, bool) {
+	return permissionDenial(e.ext.Extra)
+}
+
+// Permission 返回权限错误信息，没有时第二个返回值为 false
+func (w *withStatus) Permission() (PermissionDenial, bool) {
+	return w.status.Permission()
+}
+
+// Permission 返回 err 携带的权限错误信息，没有时第二个返回值为 false
+//
+//	if p, ok := errors.Permission(err); ok {
+//		requestScopes(p.MissingScopes)
+//	}
+func Permission(err error) (PermissionDenial, bool) {
+	var p interface {
+		Permission() (PermissionDenial, bool)
+	}
+	if errors.As(err, &p) {
+		return p.Permission()
+	}
+	return PermissionDenial{}, false
+}
+
+// permissionDenial 从扩展信息中解析权限错误信息，三项都没有时返回 false
+func permissionDenial(extra map[string]string) (PermissionDenial, bool) {
+	p := PermissionDenial{
+		Resource: extra[PermissionResourceKey],
+		Action:   extra[PermissionActionKey],
+	}
+	if scopes := extra[PermissionScopesKey]; scopes != "" {
+		p.MissingScopes = strings.Split(scopes, ",")
+	}
+	if p.Resource == "" && p.Action == "" && len(p.MissingScopes) == 0 {
+		return PermissionDenial{}, false
+	}
+	return p, true
+}
+
+// setInsufficientScopeHeader 为缺少授权范围的错误写入 WWW-Authenticate 响应头（RFC 6750）
+func setInsufficientScopeHeader(h http.Header, err StatusError) {
+	p, ok := Permission(err)
+	if !ok || len(p.MissingScopes) == 0 {
+		return
+	}
+	h.Set("WWW-Authenticate", `Bearer error="insufficient_scope", scope="`+strings.Join(p.MissingScopes, " ")+`"`)
+}
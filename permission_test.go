@@ -0,0 +1,66 @@
+package errors_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/go-anyway/framework-errors"
+)
+
+func TestNewPermissionDenied(t *testing.T) {
+	err := errors.NewPermissionDenied("orders/1", "delete", []string{"orders:write", "orders:admin"})
+	if err.Code() != errors.CodeForbidden {
+		t.Errorf("Code() = %d", err.Code())
+	}
+
+	tests := []struct {
+		name string
+		err  error
+	}{
+		{name: "本地错误", err: err},
+		{name: "gRPC 往返", err: errors.FromGRPCStatus(errors.ToGRPCStatus(err))},
+		{name: "消息头往返", err: errors.DecodeHeaders(errors.EncodeHeaders(err))},
+	}
+	want := errors.PermissionDenial{Resource: "orders/1", Action: "delete", MissingScopes: []string{"orders:write", "orders:admin"}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := errors.Permission(tt.err)
+			if !ok || !reflect.DeepEqual(got, want) {
+				t.Errorf("Permission() = %+v, %v, want %+v", got, ok, want)
+			}
+		})
+	}
+
+	if _, ok := errors.Permission(errors.NewWithStatus(errors.CodeForbidden, "")); ok {
+		t.Error("没有权限信息的错误不应返回权限信息")
+	}
+}
+
+func TestPermissionDeniedHTTP(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{
+			name: "缺少授权范围",
+			err:  errors.NewPermissionDenied("orders/1", "delete", []string{"orders:write", "orders:admin"}),
+			want: `Bearer error="insufficient_scope", scope="orders:write orders:admin"`,
+		},
+		{name: "只有资源", err: errors.NewPermissionDenied("orders/1", "delete", nil), want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			errors.WriteError(rec, httptest.NewRequest(http.MethodGet, "/", nil), tt.err)
+			if rec.Code != http.StatusForbidden {
+				t.Errorf("status = %d", rec.Code)
+			}
+			if got := rec.Header().Get("WWW-Authenticate"); got != tt.want {
+				t.Errorf("WWW-Authenticate = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
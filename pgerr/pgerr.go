@@ -0,0 +1,118 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+// Package pgerr 根据 SQLSTATE 将 PostgreSQL 错误（pgx / lib/pq）翻译为 StatusError.
+package pgerr
+
+import (
+	stderrors "errors"
+
+	"github.com/go-anyway/framework-errors"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/lib/pq"
+)
+
+// sqlState 描述一个 SQLSTATE 对应的业务错误码
+type sqlState struct {
+	code      int32
+	retryable bool
+}
+
+// sqlStates 是 SQLSTATE 到业务错误码的映射
+// 参见 https://www.postgresql.org/docs/current/errcodes-appendix.html
+var sqlStates = map[string]sqlState{
+	"23505": {code: errors.CodeAlreadyExists},                   // unique_violation
+	"23503": {code: errors.CodeInvalidParam},                    // foreign_key_violation
+	"23502": {code: errors.CodeInvalidParam},                    // not_null_violation
+	"23514": {code: errors.CodeInvalidParam},                    // check_violation
+	"57014": {code: errors.CodeRequestTimeout},                  // query_canceled（含 statement_timeout）
+	"40001": {code: errors.CodeConflict, retryable: true},       // serialization_failure
+	"40P01": {code: errors.CodeConflict, retryable: true},       // deadlock_detected
+	"55P03": {code: errors.CodeRequestTimeout, retryable: true}, // lock_not_available
+}
+
+// pgFields 是从驱动错误中提取出的公共字段
+type pgFields struct {
+	sqlState   string
+	schema     string
+	table      string
+	column     string
+	constraint string
+}
+
+// Translate 将 PostgreSQL 错误翻译为 StatusError
+// 支持 *pgconn.PgError（pgx）与 *pq.Error（lib/pq），其他错误返回 nil.
+// 约束名、表名等信息会记录在 Extra 中.
+func Translate(err error) errors.StatusError {
+	f, ok := extract(err)
+	if !ok {
+		return nil
+	}
+
+	state, ok := sqlStates[f.sqlState]
+	if !ok {
+		state = sqlState{code: errors.CodeInternalError}
+	}
+
+	opts := []errors.Option{errors.Extra("sqlstate", f.sqlState)}
+	if state.retryable {
+		opts = append(opts, errors.Retryable(true))
+	}
+	for k, v := range map[string]string{
+		"schema":     f.schema,
+		"table":      f.table,
+		"column":     f.column,
+		"constraint": f.constraint,
+	} {
+		if v != "" {
+			opts = append(opts, errors.Extra(k, v))
+		}
+	}
+
+	return errors.WrapWithStatusOptions(err, state.code, "", append([]errors.Option{errors.OverrideCode()}, opts...)...)
+}
+
+// extract 从 err 链中提取 PostgreSQL 错误字段
+func extract(err error) (pgFields, bool) {
+	if err == nil {
+		return pgFields{}, false
+	}
+
+	var pgErr *pgconn.PgError
+	if stderrors.As(err, &pgErr) {
+		return pgFields{
+			sqlState:   pgErr.Code,
+			schema:     pgErr.SchemaName,
+			table:      pgErr.TableName,
+			column:     pgErr.ColumnName,
+			constraint: pgErr.ConstraintName,
+		}, true
+	}
+
+	var pqErr *pq.Error
+	if stderrors.As(err, &pqErr) {
+		return pgFields{
+			sqlState:   string(pqErr.Code),
+			schema:     pqErr.Schema,
+			table:      pqErr.Table,
+			column:     pqErr.Column,
+			constraint: pqErr.Constraint,
+		}, true
+	}
+
+	return pgFields{}, false
+}
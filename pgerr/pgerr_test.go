@@ -0,0 +1,74 @@
+package pgerr_test
+
+import (
+	stderrors "errors"
+	"fmt"
+	"testing"
+
+	"github.com/go-anyway/framework-errors"
+	"github.com/go-anyway/framework-errors/pgerr"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/lib/pq"
+)
+
+func TestTranslatePgx(t *testing.T) {
+	tests := []struct {
+		name      string
+		sqlState  string
+		expected  int32
+		retryable bool
+	}{
+		{"唯一约束", "23505", errors.CodeAlreadyExists, false},
+		{"外键约束", "23503", errors.CodeInvalidParam, false},
+		{"语句超时", "57014", errors.CodeRequestTimeout, true},
+		{"序列化失败", "40001", errors.CodeConflict, true},
+		{"未知状态", "XX000", errors.CodeInternalError, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := fmt.Errorf("insert user: %w", &pgconn.PgError{
+				Code:           tt.sqlState,
+				TableName:      "users",
+				ConstraintName: "users_email_key",
+			})
+			se := pgerr.Translate(err)
+			if se == nil {
+				t.Fatal("Translate() = nil, want StatusError")
+			}
+			if se.Code() != tt.expected {
+				t.Errorf("Code() = %d, want %d", se.Code(), tt.expected)
+			}
+			if errors.IsRetryable(se) != tt.retryable {
+				t.Errorf("IsRetryable() = %v, want %v", errors.IsRetryable(se), tt.retryable)
+			}
+			extra := se.Extra()
+			if extra["sqlstate"] != tt.sqlState {
+				t.Errorf("Extra[sqlstate] = %s, want %s", extra["sqlstate"], tt.sqlState)
+			}
+			if extra["table"] != "users" || extra["constraint"] != "users_email_key" {
+				t.Errorf("Extra = %v, want table and constraint", extra)
+			}
+		})
+	}
+}
+
+func TestTranslatePq(t *testing.T) {
+	se := pgerr.Translate(&pq.Error{Code: "23505", Table: "orders"})
+	if se == nil {
+		t.Fatal("Translate() = nil, want StatusError")
+	}
+	if se.Code() != errors.CodeAlreadyExists {
+		t.Errorf("Code() = %d, want %d", se.Code(), errors.CodeAlreadyExists)
+	}
+	if se.Extra()["table"] != "orders" {
+		t.Errorf("Extra[table] = %s, want orders", se.Extra()["table"])
+	}
+}
+
+func TestTranslateUnknown(t *testing.T) {
+	if se := pgerr.Translate(stderrors.New("other")); se != nil {
+		t.Errorf("Translate() = %v, want nil", se)
+	}
+}
@@ -0,0 +1,70 @@
+package errors_test
+
+import (
+	"context"
+	errstd "errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-anyway/framework-errors"
+)
+
+func TestPooling(t *testing.T) {
+	errors.EnablePooling(true)
+	defer errors.EnablePooling(false)
+
+	for i := 0; i < 100; i++ {
+		err := errors.NewWithStatus(errors.CodeNotFound, "", errors.Extra("id", "42"))
+		if err.Code() != errors.CodeNotFound || err.Extra()["id"] != "42" {
+			t.Fatalf("err = %v, Extra() = %v", err, err.Extra())
+		}
+		errors.Release(err)
+
+		reused := errors.WrapWithStatusOptions(context.Canceled, errors.CodeInternalError, "")
+		if _, ok := reused.Extra()["id"]; ok {
+			t.Fatal("复用的错误不应残留上一次的扩展信息")
+		}
+		if reused.Code() != errors.CodeInternalError || !errstd.Is(reused, context.Canceled) {
+			t.Fatalf("reused = %v", reused)
+		}
+		errors.Release(reused)
+	}
+}
+
+func TestReleaseIgnoresUnpooled(t *testing.T) {
+	err := errors.NewWithStatus(errors.CodeNotFound, "", errors.Extra("id", "42"))
+	errors.Release(err)
+	if err.Extra()["id"] != "42" {
+		t.Error("未启用对象池时 Release 不应修改错误")
+	}
+	errors.Release(errors.NewStatusError(errors.CodeNotFound, "", nil))
+	errors.Release(nil)
+}
+
+func TestReleaseKeepsWrappedPooledError(t *testing.T) {
+	errors.EnablePooling(true)
+	defer errors.EnablePooling(false)
+
+	inner := errors.NewWithStatus(errors.CodeNotFound, "", errors.Extra("id", "42"))
+	outer := errors.WrapWithStatusOptions(fmt.Errorf("load order: %w", inner), errors.CodeInternalError, "")
+	_ = errors.LogAndReturnError(context.Background(), outer, errors.SkipLog())
+
+	// 归还外层错误后，池中的对象被再次使用也不能影响仍被引用的内层错误
+	for i := 0; i < 100; i++ {
+		_ = errors.NewWithStatus(errors.CodeConflict, "", errors.Extra("id", "x"))
+	}
+	if inner.Code() != errors.CodeNotFound || inner.Extra()["id"] != "42" {
+		t.Errorf("inner = (%d, %v), 被包装的池化错误不应被归还", inner.Code(), inner.Extra())
+	}
+
+	inner = errors.NewWithStatus(errors.CodeNotFound, "", errors.Extra("id", "43"))
+	errors.WriteError(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil), fmt.Errorf("load order: %w", inner))
+	for i := 0; i < 100; i++ {
+		_ = errors.NewWithStatus(errors.CodeConflict, "", errors.Extra("id", "x"))
+	}
+	if inner.Code() != errors.CodeNotFound || inner.Extra()["id"] != "43" {
+		t.Errorf("inner = (%d, %v), WriteError 不应归还被包装的池化错误", inner.Code(), inner.Extra())
+	}
+}
@@ -0,0 +1,113 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+// Package prometheuserr 提供基于 Prometheus 的 errors.MetricsSink 实现.
+package prometheuserr
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/go-anyway/framework-errors"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Sink 将错误计数与请求耗时记录为 Prometheus 指标
+//
+//	framework_errors_total{code="1004",severity="warning"}
+//	framework_errors_latency_seconds{code="1004"}
+//	framework_errors_dependency_total{dependency="payment-api",code="3002"}
+//	framework_errors_slo_impact_total{code="1006",impact="critical"}
+type Sink struct {
+	errors     *prometheus.CounterVec
+	latency    *prometheus.HistogramVec
+	dependency *prometheus.CounterVec
+	sloImpact  *prometheus.CounterVec
+}
+
+// New 创建 Sink 并将指标注册到 reg，reg 为 nil 时使用 prometheus.DefaultRegisterer
+func New(reg prometheus.Registerer) (*Sink, error) {
+	if reg == nil {
+		reg = prometheus.DefaultRegisterer
+	}
+	s := &Sink{
+		errors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "framework",
+			Subsystem: "errors",
+			Name:      "total",
+			Help:      "按错误码与严重程度统计的错误数量.",
+		}, []string{"code", "severity"}),
+		latency: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: "framework",
+			Subsystem: "errors",
+			Name:      "latency_seconds",
+			Help:      "按错误码统计的请求耗时.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"code"}),
+		dependency: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "framework",
+			Subsystem: "errors",
+			Name:      "dependency_total",
+			Help:      "按依赖服务与错误码统计的错误数量.",
+		}, []string{"dependency", "code"}),
+		sloImpact: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "framework",
+			Subsystem: "errors",
+			Name:      "slo_impact_total",
+			Help:      "按错误码与 SLO 影响程度统计的错误数量.",
+		}, []string{"code", "impact"}),
+	}
+	if err := reg.Register(s.errors); err != nil {
+		return nil, err
+	}
+	if err := reg.Register(s.latency); err != nil {
+		reg.Unregister(s.errors)
+		return nil, err
+	}
+	if err := reg.Register(s.dependency); err != nil {
+		reg.Unregister(s.errors)
+		reg.Unregister(s.latency)
+		return nil, err
+	}
+	if err := reg.Register(s.sloImpact); err != nil {
+		reg.Unregister(s.errors)
+		reg.Unregister(s.latency)
+		reg.Unregister(s.dependency)
+		return nil, err
+	}
+	return s, nil
+}
+
+// Increment 实现 errors.MetricsSink 接口
+func (s *Sink) Increment(code int32, severity errors.Severity) {
+	s.errors.WithLabelValues(strconv.Itoa(int(code)), string(severity)).Inc()
+}
+
+// ObserveLatency 实现 errors.MetricsSink 接口
+func (s *Sink) ObserveLatency(code int32, latency time.Duration) {
+	s.latency.WithLabelValues(strconv.Itoa(int(code))).Observe(latency.Seconds())
+}
+
+// IncrementDependency 实现 errors.DependencyMetricsSink 接口
+func (s *Sink) IncrementDependency(dependency string, code int32) {
+	s.dependency.WithLabelValues(dependency, strconv.Itoa(int(code))).Inc()
+}
+
+// IncrementSLOImpact 实现 errors.SLOMetricsSink 接口
+func (s *Sink) IncrementSLOImpact(code int32, impact errors.SLOImpact) {
+	s.sloImpact.WithLabelValues(strconv.Itoa(int(code)), impact.String()).Inc()
+}
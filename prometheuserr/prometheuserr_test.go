@@ -0,0 +1,69 @@
+package prometheuserr_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/go-anyway/framework-errors"
+	"github.com/go-anyway/framework-errors/prometheuserr"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestSink(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	sink, err := prometheuserr.New(reg)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	sink.Increment(errors.CodeNotFound, errors.SeverityWarning)
+	sink.Increment(errors.CodeNotFound, errors.SeverityWarning)
+	sink.Increment(errors.CodeInternalError, errors.SeverityCritical)
+	sink.ObserveLatency(errors.CodeNotFound, 20*time.Millisecond)
+
+	families, err := reg.Gather()
+	if err != nil {
+		t.Fatalf("Gather() error = %v", err)
+	}
+	counts := map[string]float64{}
+	for _, mf := range families {
+		if mf.GetName() != "framework_errors_total" {
+			continue
+		}
+		for _, m := range mf.GetMetric() {
+			var code, severity string
+			for _, l := range m.GetLabel() {
+				switch l.GetName() {
+				case "code":
+					code = l.GetValue()
+				case "severity":
+					severity = l.GetValue()
+				}
+			}
+			counts[code+"/"+severity] = m.GetCounter().GetValue()
+		}
+	}
+	if counts["1004/warning"] != 2 || counts["1006/critical"] != 1 {
+		t.Errorf("counts = %v", counts)
+	}
+	if n := testutil.CollectAndCount(reg, "framework_errors_latency_seconds"); n != 1 {
+		t.Errorf("latency series = %d, want 1", n)
+	}
+
+	sink.IncrementDependency("payment-api", errors.CodeDependencyTimeout)
+	if n := testutil.CollectAndCount(reg, "framework_errors_dependency_total"); n != 1 {
+		t.Errorf("dependency series = %d, want 1", n)
+	}
+
+	sink.IncrementSLOImpact(errors.CodeInternalError, errors.SLOImpactCritical)
+	if n := testutil.CollectAndCount(reg, "framework_errors_slo_impact_total"); n != 1 {
+		t.Errorf("slo impact series = %d, want 1", n)
+	}
+
+	// 重复注册返回错误
+	if _, err := prometheuserr.New(reg); err == nil {
+		t.Error("New() 重复注册应返回错误")
+	}
+}
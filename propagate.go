@@ -0,0 +1,140 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package errors
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+const (
+	// MetadataDebugErrors 是 gRPC 请求中携带调试模式标记的 metadata key
+	MetadataDebugErrors = "x-debug-errors"
+	// MetadataErrorContextPrefix 是 gRPC 请求中携带错误上下文的 metadata key 前缀
+	MetadataErrorContextPrefix = "x-error-ctx-"
+)
+
+type errorContextKey struct{}
+
+// WithErrorContext 返回记录了错误上下文 key/value 的 context，例如租户 ID
+// 错误上下文会被 gRPC 客户端拦截器传递到下游服务，下游可以通过 ContextExtra 将其添加到错误中，
+// 保证整条链路上的错误带有一致的扩展信息. key 会被转换为小写.
+func WithErrorContext(ctx context.Context, key, value string) context.Context {
+	prev := ErrorContextFromContext(ctx)
+	values := make(map[string]string, len(prev)+1)
+	for k, v := range prev {
+		values[k] = v
+	}
+	values[strings.ToLower(key)] = value
+	return context.WithValue(ctx, errorContextKey{}, values)
+}
+
+// ErrorContextFromContext 获取 context 中记录的错误上下文，返回的 map 不应被修改
+func ErrorContextFromContext(ctx context.Context) map[string]string {
+	if ctx == nil {
+		return nil
+	}
+	values, _ := ctx.Value(errorContextKey{}).(map[string]string)
+	return values
+}
+
+// ContextExtra 将 ctx 中的错误上下文添加到错误的扩展信息中
+//
+//	return errors.NewWithStatus(CodeOrderClosed, "", errors.ContextExtra(ctx))
+func ContextExtra(ctx context.Context) Option {
+	values := ErrorContextFromContext(ctx)
+	return func(ws *withStatus) {
+		for k, v := range values {
+			Extra(k, v)(ws)
+		}
+	}
+}
+
+// UnaryClientContextInterceptor 返回将语言、调试模式与错误上下文写入 metadata 的 gRPC 一元客户端拦截器
+func UnaryClientContextInterceptor() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		return invoker(outgoingErrorContext(ctx), method, req, reply, cc, opts...)
+	}
+}
+
+// StreamClientContextInterceptor 返回将语言、调试模式与错误上下文写入 metadata 的 gRPC 流客户端拦截器
+func StreamClientContextInterceptor() grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		return streamer(outgoingErrorContext(ctx), desc, cc, method, opts...)
+	}
+}
+
+// UnaryServerContextInterceptor 返回从 metadata 中还原语言、调试模式与错误上下文的 gRPC 一元服务端拦截器
+// 只有 authorize 返回 true 的请求才会开启调试模式，authorize 为 nil 时始终不开启.
+func UnaryServerContextInterceptor(authorize func(ctx context.Context) bool) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		return handler(incomingErrorContext(ctx, authorize), req)
+	}
+}
+
+// StreamServerContextInterceptor 返回从 metadata 中还原语言、调试模式与错误上下文的 gRPC 流服务端拦截器
+// authorize 的含义与 UnaryServerContextInterceptor 相同.
+func StreamServerContextInterceptor(authorize func(ctx context.Context) bool) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx := incomingErrorContext(ss.Context(), authorize)
+		return handler(srv, &localeServerStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+// outgoingErrorContext 将 ctx 中的语言、调试模式与错误上下文追加到 outgoing metadata
+func outgoingErrorContext(ctx context.Context) context.Context {
+	var kv []string
+	if locale := LocaleFromContext(ctx); locale != "" {
+		kv = append(kv, MetadataAcceptLanguage, locale)
+	}
+	if DebugErrorsFromContext(ctx) {
+		kv = append(kv, MetadataDebugErrors, "true")
+	}
+	for k, v := range ErrorContextFromContext(ctx) {
+		kv = append(kv, MetadataErrorContextPrefix+k, v)
+	}
+	if len(kv) == 0 {
+		return ctx
+	}
+	return metadata.AppendToOutgoingContext(ctx, kv...)
+}
+
+// incomingErrorContext 从 incoming metadata 中还原语言、调试模式与错误上下文
+func incomingErrorContext(ctx context.Context, authorize func(ctx context.Context) bool) context.Context {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ctx
+	}
+	if values := md.Get(MetadataAcceptLanguage); len(values) > 0 {
+		ctx = WithLocale(ctx, MatchLocale(values[0]))
+	}
+	if values := md.Get(MetadataDebugErrors); len(values) > 0 {
+		if enabled, _ := strconv.ParseBool(values[0]); enabled && authorize != nil && authorize(ctx) {
+			ctx = WithDebugErrors(ctx)
+		}
+	}
+	for k, values := range md {
+		if key, ok := strings.CutPrefix(k, MetadataErrorContextPrefix); ok && key != "" && len(values) > 0 {
+			ctx = WithErrorContext(ctx, key, values[0])
+		}
+	}
+	return ctx
+}
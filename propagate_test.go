@@ -0,0 +1,70 @@
+package errors_test
+
+import (
+	"context"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+
+	"github.com/go-anyway/framework-errors"
+)
+
+// roundTrip 依次经过客户端与服务端拦截器，返回服务端 handler 收到的 context
+func roundTrip(t *testing.T, ctx context.Context, authorize func(ctx context.Context) bool) context.Context {
+	t.Helper()
+	var outgoing metadata.MD
+	client := errors.UnaryClientContextInterceptor()
+	err := client(ctx, "/svc/Method", nil, nil, nil, func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+		outgoing, _ = metadata.FromOutgoingContext(ctx)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("client interceptor error = %v", err)
+	}
+
+	var got context.Context
+	server := errors.UnaryServerContextInterceptor(authorize)
+	_, _ = server(metadata.NewIncomingContext(context.Background(), outgoing), nil, &grpc.UnaryServerInfo{}, func(ctx context.Context, req interface{}) (interface{}, error) {
+		got = ctx
+		return nil, nil
+	})
+	return got
+}
+
+func TestErrorContextPropagation(t *testing.T) {
+	ctx := errors.WithLocale(context.Background(), "en")
+	ctx = errors.WithDebugErrors(ctx)
+	ctx = errors.WithErrorContext(ctx, "Tenant", "acme")
+
+	tests := []struct {
+		name      string
+		authorize func(ctx context.Context) bool
+		wantDebug bool
+	}{
+		{name: "未授权调试模式", authorize: nil, wantDebug: false},
+		{name: "授权调试模式", authorize: func(context.Context) bool { return true }, wantDebug: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := roundTrip(t, ctx, tt.authorize)
+			if locale := errors.LocaleFromContext(got); locale != "en" {
+				t.Errorf("LocaleFromContext() = %q, want en", locale)
+			}
+			if debug := errors.DebugErrorsFromContext(got); debug != tt.wantDebug {
+				t.Errorf("DebugErrorsFromContext() = %v, want %v", debug, tt.wantDebug)
+			}
+			err := errors.NewWithStatus(errors.CodeNotFound, "", errors.ContextExtra(got))
+			if tenant := err.Extra()["tenant"]; tenant != "acme" {
+				t.Errorf("Extra()[tenant] = %q, want acme", tenant)
+			}
+		})
+	}
+}
+
+func TestErrorContextNoMetadata(t *testing.T) {
+	got := roundTrip(t, context.Background(), nil)
+	if len(errors.ErrorContextFromContext(got)) != 0 || errors.LocaleFromContext(got) != "" {
+		t.Error("没有错误上下文时不应写入 metadata")
+	}
+}
@@ -0,0 +1,120 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package errors
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ProtoEnumOptions 是 GenerateProtoEnum 的选项
+type ProtoEnumOptions struct {
+	// Package 是 .proto 文件的 package，为空时使用 errors.v1
+	Package string
+	// EnumName 是枚举的名称，为空时使用 ErrorCode
+	EnumName string
+	// GoPackage 是 go_package 选项，为空时不输出
+	GoPackage string
+	// Names 覆盖错误码的符号名称（UPPER_SNAKE），同时作为 reason 注解的值
+	// 未指定时使用 CodeDefinition.Reason，没有定义错误原因的错误码使用 CODE_<code>.
+	Names map[int32]string
+}
+
+// upperSnake 匹配 UPPER_SNAKE 格式的名称
+var upperSnake = regexp.MustCompile(`^[A-Z][A-Z0-9]*(_[A-Z0-9]+)*$`)
+
+// GenerateProtoEnum 根据已注册的错误码生成 .proto 枚举定义
+// Go 中的 CodeDefinitions 仍是唯一的数据源，其他语言的服务可以编译生成的文件，共享同一套错误码.
+// 每个枚举值带有 reason 注解（自定义的 EnumValueOptions 扩展）与默认消息注释，
+// 枚举值的数值即业务错误码，另外包含值为 0 的 <ENUM>_UNSPECIFIED.
+//
+//	f, _ := os.Create("errors.proto")
+//	err := errors.GenerateProtoEnum(f, errors.ProtoEnumOptions{Package: "orders.v1"})
+func GenerateProtoEnum(w io.Writer, opts ProtoEnumOptions) error {
+	if opts.Package == "" {
+		opts.Package = "errors.v1"
+	}
+	if opts.EnumName == "" {
+		opts.EnumName = "ErrorCode"
+	}
+
+	codes := make([]int32, 0, len(CodeDefinitions))
+	for code := range CodeDefinitions {
+		codes = append(codes, code)
+	}
+	sort.Slice(codes, func(i, j int) bool { return codes[i] < codes[j] })
+
+	prefix := enumPrefix(opts.EnumName)
+	seen := make(map[string]int32, len(codes))
+	var b strings.Builder
+	b.WriteString("// Code generated by errors.GenerateProtoEnum. DO NOT EDIT.\n\n")
+	b.WriteString("syntax = \"proto3\";\n\n")
+	fmt.Fprintf(&b, "package %s;\n\n", opts.Package)
+	b.WriteString("import \"google/protobuf/descriptor.proto\";\n\n")
+	if opts.GoPackage != "" {
+		fmt.Fprintf(&b, "option go_package = %q;\n\n", opts.GoPackage)
+	}
+	b.WriteString("extend google.protobuf.EnumValueOptions {\n")
+	b.WriteString("  // reason 是错误码的符号名称\n")
+	b.WriteString("  string reason = 51001;\n")
+	b.WriteString("}\n\n")
+	fmt.Fprintf(&b, "enum %s {\n", opts.EnumName)
+	fmt.Fprintf(&b, "  %s_UNSPECIFIED = 0;\n", prefix)
+	for _, code := range codes {
+		name := codeName(code, opts.Names)
+		if !upperSnake.MatchString(name) {
+			return fmt.Errorf("错误码 %d 的名称 %q 不是 UPPER_SNAKE 格式", code, name)
+		}
+		if other, ok := seen[name]; ok {
+			return fmt.Errorf("错误码 %d 与 %d 的名称 %s 重复", code, other, name)
+		}
+		seen[name] = code
+		fmt.Fprintf(&b, "  // %s\n", strings.ReplaceAll(GetCodeDefinition(code).Message, "\n", " "))
+		fmt.Fprintf(&b, "  %s_%s = %d [(reason) = %q];\n", prefix, name, code, name)
+	}
+	b.WriteString("}\n")
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// codeName 返回错误码的符号名称
+func codeName(code int32, names map[int32]string) string {
+	if name, ok := names[code]; ok {
+		return name
+	}
+	if reason := GetCodeDefinition(code).Reason; reason != "" {
+		return reason
+	}
+	return "CODE_" + strconv.Itoa(int(code))
+}
+
+// enumPrefix 将驼峰格式的枚举名称转换为 UPPER_SNAKE 格式的枚举值前缀，例如 ErrorCode 转换为 ERROR_CODE
+func enumPrefix(name string) string {
+	var b strings.Builder
+	for i, r := range name {
+		if i > 0 && r >= 'A' && r <= 'Z' {
+			b.WriteByte('_')
+		}
+		b.WriteRune(r)
+	}
+	return strings.ToUpper(b.String())
+}
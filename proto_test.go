@@ -0,0 +1,54 @@
+package errors_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/go-anyway/framework-errors"
+)
+
+func TestGenerateProtoEnum(t *testing.T) {
+	const codeOrderClosed int32 = 20001
+	errors.CodeDefinitions[codeOrderClosed] = errors.CodeDefinition{Message: "订单已关闭"}
+	defer delete(errors.CodeDefinitions, codeOrderClosed)
+
+	var b strings.Builder
+	err := errors.GenerateProtoEnum(&b, errors.ProtoEnumOptions{
+		Package:   "orders.v1",
+		GoPackage: "example.com/orders/v1;ordersv1",
+		Names:     map[int32]string{codeOrderClosed: "ORDER_CLOSED"},
+	})
+	if err != nil {
+		t.Fatalf("GenerateProtoEnum() error = %v", err)
+	}
+	got := b.String()
+	for _, want := range []string{
+		"package orders.v1;",
+		`option go_package = "example.com/orders/v1;ordersv1";`,
+		"ERROR_CODE_UNSPECIFIED = 0;",
+		"// 资源未找到\n  ERROR_CODE_NOT_FOUND = 1004 [(reason) = \"NOT_FOUND\"];",
+		`ERROR_CODE_ORDER_CLOSED = 20001 [(reason) = "ORDER_CLOSED"];`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("GenerateProtoEnum() 缺少 %q:\n%s", want, got)
+		}
+	}
+}
+
+func TestGenerateProtoEnumInvalidName(t *testing.T) {
+	tests := []struct {
+		name  string
+		names map[int32]string
+	}{
+		{name: "非 UPPER_SNAKE", names: map[int32]string{errors.CodeNotFound: "notFound"}},
+		{name: "名称重复", names: map[int32]string{errors.CodeNotFound: "CONFLICT"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var b strings.Builder
+			if err := errors.GenerateProtoEnum(&b, errors.ProtoEnumOptions{Names: tt.names}); err == nil {
+				t.Error("GenerateProtoEnum() error = nil")
+			}
+		})
+	}
+}
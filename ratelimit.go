@@ -0,0 +1,167 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package errors
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/protobuf/proto"
+)
+
+// 限流配额在扩展信息中的 key
+const (
+	RateLimitLimitKey     = "ratelimit_limit"
+	RateLimitRemainingKey = "ratelimit_remaining"
+	// RateLimitResetKey 的值为配额重置时间的 Unix 秒数
+	RateLimitResetKey = "ratelimit_reset"
+)
+
+// 限流配额对应的 HTTP 头
+const (
+	HeaderRateLimitLimit     = "X-RateLimit-Limit"
+	HeaderRateLimitRemaining = "X-RateLimit-Remaining"
+	HeaderRateLimitReset     = "X-RateLimit-Reset"
+)
+
+// rateLimitSubject 是 ToGRPCStatus 附加的 errdetails.QuotaFailure 的 Subject
+const rateLimitSubject = ErrorInfoDomain + "/ratelimit"
+
+// RateLimitQuota 是限流错误携带的配额信息
+type RateLimitQuota struct {
+	// Limit 是窗口内允许的请求数
+	Limit int
+	// Remaining 是窗口内剩余的请求数
+	Remaining int
+	// Reset 是配额重置的时间
+	Reset time.Time
+}
+
+// NewRateLimited 创建携带配额信息的 CodeRateLimitExceeded 错误
+// 建议的重试等待时间为距离 reset 的时间（见 RetryAfter），WriteError 会输出 X-RateLimit-* 响应头，
+// ToGRPCStatus 会附加 errdetails.QuotaFailure，调用方通过 RateLimit 读取配额信息.
+//
+//	if !limiter.Allow() {
+//		return errors.NewRateLimited(100, 0, limiter.Reset())
+//	}
+func NewRateLimited(limit, remaining int, reset time.Time, opts ...Option) StatusError {
+	quota := []Option{
+		Extra(RateLimitLimitKey, strconv.Itoa(limit)),
+		Extra(RateLimitRemainingKey, strconv.Itoa(remaining)),
+		Extra(RateLimitResetKey, strconv.FormatInt(reset.Unix(), 10)),
+		RetryAfter(reset.Sub(now())),
+	}
+	return NewWithStatus(CodeRateLimitExceeded, "", append(quota, opts...)...)
+}
+
+// RateLimit 返回限流配额信息，没有时第二个返回值为 false
+func (e *statusError) RateLimit() (RateLimitQuota, bool) {
+	return rateLimitQuota(e.ext.Extra)
+}
+
+// RateLimit 返回限流配额信息，没有时第二个返回值为 false
+func (w *withStatus) RateLimit() (RateLimitQuota, bool) {
+	return w.status.RateLimit()
+}
+
+// RateLimit 返回 err 携带的限流配额信息，没有时第二个返回值为 false
+func RateLimit(err error) (RateLimitQuota, bool) {
+	var r interface{ RateLimit() (RateLimitQuota, bool) }
+	if errors.As(err, &r) {
+		return r.RateLimit()
+	}
+	return RateLimitQuota{}, false
+}
+
+// rateLimitQuota 从扩展信息中解析限流配额，没有 RateLimitLimitKey 时返回 false
+func rateLimitQuota(extra map[string]string) (RateLimitQuota, bool) {
+	limit, err := strconv.Atoi(extra[RateLimitLimitKey])
+	if err != nil {
+		return RateLimitQuota{}, false
+	}
+	q := RateLimitQuota{Limit: limit}
+	q.Remaining, _ = strconv.Atoi(extra[RateLimitRemainingKey])
+	if reset, err := strconv.ParseInt(extra[RateLimitResetKey], 10, 64); err == nil {
+		q.Reset = time.Unix(reset, 0)
+	}
+	return q, true
+}
+
+// quotaFailureDetail 将限流配额编码为 errdetails.QuotaFailure，没有时返回 nil
+func quotaFailureDetail(err StatusError) proto.Message {
+	q, ok := RateLimit(err)
+	if !ok {
+		return nil
+	}
+	return &errdetails.QuotaFailure{Violations: []*errdetails.QuotaFailure_Violation{{
+		Subject:     rateLimitSubject,
+		Description: fmt.Sprintf("limit %d, remaining %d, reset at %s", q.Limit, q.Remaining, q.Reset.UTC().Format(time.RFC3339)),
+		QuotaValue:  int64(q.Limit),
+	}}}
+}
+
+// decodeQuotaFailure 解析 ToGRPCStatus 附加的限流配额，其他来源的 QuotaFailure 作为附加的 proto 消息保留
+// 配额信息同时记录在扩展信息中，这里只在扩展信息缺失时补充配额上限.
+func decodeQuotaFailure(detail proto.Message, d *DecodedStatus) bool {
+	qf, ok := detail.(*errdetails.QuotaFailure)
+	if !ok || len(qf.GetViolations()) != 1 || qf.GetViolations()[0].GetSubject() != rateLimitSubject {
+		return false
+	}
+	if _, exists := d.Extra[RateLimitLimitKey]; !exists {
+		d.AddExtra(RateLimitLimitKey, strconv.FormatInt(qf.GetViolations()[0].GetQuotaValue(), 10))
+	}
+	return true
+}
+
+// setRateLimitHeaders 将限流配额写入 X-RateLimit-* 响应头
+func setRateLimitHeaders(h http.Header, err StatusError) {
+	q, ok := RateLimit(err)
+	if !ok {
+		return
+	}
+	h.Set(HeaderRateLimitLimit, strconv.Itoa(q.Limit))
+	h.Set(HeaderRateLimitRemaining, strconv.Itoa(q.Remaining))
+	if !q.Reset.IsZero() {
+		h.Set(HeaderRateLimitReset, strconv.FormatInt(q.Reset.Unix(), 10))
+	}
+}
+
+// withRateLimitHeaders 根据 X-RateLimit-* 响应头为 se 补充限流配额，响应体中已经包含配额时以响应体为准
+func withRateLimitHeaders(se StatusError, h http.Header) StatusError {
+	if se == nil {
+		return se
+	}
+	if _, ok := RateLimit(se); ok {
+		return se
+	}
+	limit, err := strconv.Atoi(h.Get(HeaderRateLimitLimit))
+	if err != nil {
+		return se
+	}
+	opts := []Option{Extra(RateLimitLimitKey, strconv.Itoa(limit))}
+	if remaining, err := strconv.Atoi(h.Get(HeaderRateLimitRemaining)); err == nil {
+		opts = append(opts, Extra(RateLimitRemainingKey, strconv.Itoa(remaining)))
+	}
+	if reset, err := strconv.ParseInt(h.Get(HeaderRateLimitReset), 10, 64); err == nil {
+		opts = append(opts, Extra(RateLimitResetKey, strconv.FormatInt(reset, 10)))
+	}
+	return With(se, opts...)
+}
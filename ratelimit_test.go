@@ -0,0 +1,124 @@
+package errors_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-anyway/framework-errors"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+)
+
+func TestNewRateLimited(t *testing.T) {
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	defer errors.SetClock(func() time.Time { return now })()
+
+	reset := now.Add(30 * time.Second)
+	err := errors.NewRateLimited(100, 0, reset)
+	if err.Code() != errors.CodeRateLimitExceeded {
+		t.Errorf("Code() = %d", err.Code())
+	}
+	if got := errors.BackoffHint(err); got != 30*time.Second {
+		t.Errorf("BackoffHint() = %v, want 30s", got)
+	}
+
+	q, ok := errors.RateLimit(err)
+	if !ok || q.Limit != 100 || q.Remaining != 0 || !q.Reset.Equal(reset) {
+		t.Errorf("RateLimit() = %+v, %v", q, ok)
+	}
+	if _, ok := errors.RateLimit(errors.NewWithStatus(errors.CodeRateLimitExceeded, "")); ok {
+		t.Error("没有配额信息的错误不应返回配额")
+	}
+}
+
+func TestRateLimitGRPC(t *testing.T) {
+	reset := time.Unix(1700000000, 0)
+	tests := []struct {
+		name     string
+		encoding errors.DetailEncoding
+	}{
+		{name: "structpb", encoding: errors.DetailEncodingStruct},
+		{name: "二进制编码", encoding: errors.DetailEncodingBinary},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errors.SetDetailEncoding(tt.encoding)
+			defer errors.SetDetailEncoding(errors.DetailEncodingStruct)
+
+			st := errors.ToGRPCStatus(errors.NewRateLimited(100, 3, reset))
+			var quota *errdetails.QuotaFailure
+			for _, d := range st.Details() {
+				if qf, ok := d.(*errdetails.QuotaFailure); ok {
+					quota = qf
+				}
+			}
+			if quota == nil || quota.GetViolations()[0].GetQuotaValue() != 100 {
+				t.Fatalf("QuotaFailure = %v", quota)
+			}
+
+			got := errors.FromGRPCStatus(st)
+			q, ok := errors.RateLimit(got)
+			if !ok || q.Limit != 100 || q.Remaining != 3 || !q.Reset.Equal(reset) {
+				t.Errorf("RateLimit() = %+v, %v", q, ok)
+			}
+			if details := errors.Details(got); len(details) != 0 {
+				t.Errorf("Details() = %v, QuotaFailure 不应作为附加消息转发", details)
+			}
+		})
+	}
+}
+
+func TestRateLimitHTTP(t *testing.T) {
+	reset := time.Now().Add(time.Minute).Truncate(time.Second)
+	rec := httptest.NewRecorder()
+	errors.WriteError(rec, httptest.NewRequest(http.MethodGet, "/", nil), errors.NewRateLimited(10, 0, reset))
+
+	h := rec.Header()
+	if h.Get(errors.HeaderRateLimitLimit) != "10" || h.Get(errors.HeaderRateLimitRemaining) != "0" ||
+		h.Get(errors.HeaderRateLimitReset) == "" {
+		t.Errorf("headers = %v", h)
+	}
+
+	// 第三方接口只通过响应头返回配额
+	resp := &http.Response{
+		StatusCode: http.StatusTooManyRequests,
+		Header:     http.Header{},
+		Body:       io.NopCloser(strings.NewReader("too many requests")),
+	}
+	resp.Header.Set(errors.HeaderRateLimitLimit, "10")
+	resp.Header.Set(errors.HeaderRateLimitRemaining, "0")
+	resp.Header.Set(errors.HeaderRateLimitReset, h.Get(errors.HeaderRateLimitReset))
+
+	q, ok := errors.RateLimit(errors.CheckResponse(resp))
+	if !ok || q.Limit != 10 || q.Remaining != 0 || !q.Reset.Equal(reset) {
+		t.Errorf("RateLimit() = %+v, %v", q, ok)
+	}
+}
+
+func TestRateLimitHTTPWithDecoder(t *testing.T) {
+	reset := time.Now().Add(time.Minute).Truncate(time.Second)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set(errors.HeaderRateLimitLimit, "10")
+		w.Header().Set(errors.HeaderRateLimitRemaining, "0")
+		w.Header().Set(errors.HeaderRateLimitReset, strconv.FormatInt(reset.Unix(), 10))
+		w.WriteHeader(http.StatusTooManyRequests)
+		_, _ = w.Write([]byte(`{"errno":"E_LIMIT","errmsg":"slow down"}`))
+	}))
+	defer srv.Close()
+
+	dec := &errors.ResponseDecoder{CodePath: "errno", MessagePath: "errmsg"}
+	client := &http.Client{Transport: &errors.Transport{Decoder: dec}}
+	resp, err := client.Get(srv.URL)
+	if resp != nil {
+		_ = resp.Body.Close()
+	}
+
+	q, ok := errors.RateLimit(err)
+	if !ok || q.Limit != 10 || q.Remaining != 0 || !q.Reset.Equal(reset) {
+		t.Errorf("RateLimit() = %+v, %v", q, ok)
+	}
+}
@@ -0,0 +1,55 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package errors
+
+import "errors"
+
+// Reason 返回错误原因，即错误码的符号名称（UPPER_SNAKE），见 CodeDefinition.Reason
+func (e *statusError) Reason() string {
+	if e.reason != "" {
+		return e.reason
+	}
+	return GetCodeDefinition(e.statusCode).Reason
+}
+
+// Reason 返回错误原因，即错误码的符号名称（UPPER_SNAKE），见 CodeDefinition.Reason
+func (w *withStatus) Reason() string {
+	return w.status.Reason()
+}
+
+// Reason 返回 err 的错误原因，没有定义时返回空字符串
+// 错误原因随 gRPC details（ErrorInfo.reason）、HTTP 响应体与消息头传递，
+// 便于阅读日志以及偏好按符号匹配错误的客户端使用.
+//
+//	if errors.Reason(err) == "ORDER_CLOSED" { ... }
+func Reason(err error) string {
+	var r interface{ Reason() string }
+	if errors.As(err, &r) {
+		return r.Reason()
+	}
+	return ""
+}
+
+// remoteReason 返回需要单独记录的错误原因，与本地错误码定义一致时返回空字符串
+// 例如调用方使用了本地未注册的错误码时，收到的错误原因只能记录在错误上.
+func remoteReason(code int32, reason string) string {
+	reason = truncateString(reason, maxIncomingKeyLen)
+	if reason == GetCodeDefinition(code).Reason {
+		return ""
+	}
+	return reason
+}
@@ -0,0 +1,105 @@
+package errors_test
+
+import (
+	"encoding/json"
+	errstd "errors"
+	"net/http"
+	"testing"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/go-anyway/framework-errors"
+)
+
+func TestReason(t *testing.T) {
+	if got := errors.Reason(errors.NewWithStatus(errors.CodeNotFound, "")); got != "NOT_FOUND" {
+		t.Errorf("Reason() = %q, want NOT_FOUND", got)
+	}
+	if got := errors.Reason(errors.NewWithStatus(errors.ComposeCode(12, errors.CodeNotFound), "")); got != "NOT_FOUND" {
+		t.Errorf("Reason() = %q, 组合错误码应使用原错误码的错误原因", got)
+	}
+	if got := errors.Reason(errstd.New("boom")); got != "" {
+		t.Errorf("Reason() = %q, want 空字符串", got)
+	}
+}
+
+func TestReasonRoundTrip(t *testing.T) {
+	const codeOrderClosed int32 = 20001
+	// 业务自己的 reason 扩展信息与错误原因互不影响
+	err := errors.NewWithStatus(errors.CodeNotFound, "", errors.Extra("id", "42"), errors.Extra("reason", "expired"))
+	// 对端定义了本地未注册的错误码
+	info := &errdetails.ErrorInfo{Reason: "ORDER_CLOSED", Domain: errors.ErrorInfoDomain, Metadata: map[string]string{"business_code": "20001"}}
+	st, _ := status.New(codes.FailedPrecondition, "订单已关闭").WithDetails(info)
+	remote := errors.FromGRPCStatus(st)
+
+	tests := []struct {
+		name   string
+		decode func(se errors.StatusError) errors.StatusError
+	}{
+		{name: "structpb", decode: func(se errors.StatusError) errors.StatusError {
+			return errors.FromGRPCStatus(errors.ToGRPCStatus(se))
+		}},
+		{name: "ErrorInfo", decode: func(se errors.StatusError) errors.StatusError {
+			errors.SetDetailEncoding(errors.DetailEncodingBinary)
+			defer errors.SetDetailEncoding(errors.DetailEncodingStruct)
+			return errors.FromGRPCStatus(errors.ToGRPCStatus(se))
+		}},
+		{name: "消息头", decode: func(se errors.StatusError) errors.StatusError {
+			return errors.DecodeHeaders(errors.EncodeHeaders(se))
+		}},
+		{name: "HTTP 响应体", decode: func(se errors.StatusError) errors.StatusError {
+			body, _ := json.Marshal(errors.NewEnvelope(se))
+			return errors.FromHTTPResponse(http.StatusNotFound, body)
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.decode(err)
+			if got.Code() != errors.CodeNotFound || errors.Reason(got) != "NOT_FOUND" || got.Extra()["id"] != "42" {
+				t.Errorf("decode() = %d %q %v", got.Code(), errors.Reason(got), got.Extra())
+			}
+			if got.Extra()["reason"] != "expired" {
+				t.Errorf("Extra() = %v, 业务的 reason 扩展信息应原样传递", got.Extra())
+			}
+
+			got = tt.decode(remote)
+			if got.Code() != codeOrderClosed || errors.Reason(got) != "ORDER_CLOSED" {
+				t.Errorf("decode(remote) = %d %q", got.Code(), errors.Reason(got))
+			}
+			if _, ok := got.Extra()["reason"]; ok {
+				t.Errorf("Extra() = %v, 错误原因不应记录在扩展信息中", got.Extra())
+			}
+		})
+	}
+}
+
+func TestEnvelopeKeepsReasonExtra(t *testing.T) {
+	env := errors.NewEnvelope(errors.NewWithStatus(errors.CodeNotFound, "", errors.Extra("reason", "expired")))
+	if env.Reason != "NOT_FOUND" || env.Extra["reason"] != "expired" {
+		t.Errorf("NewEnvelope() = %+v", env)
+	}
+}
+
+func TestErrorInfoReason(t *testing.T) {
+	errors.SetDetailEncoding(errors.DetailEncodingBinary)
+	defer errors.SetDetailEncoding(errors.DetailEncodingStruct)
+
+	st := errors.ToGRPCStatus(errors.NewWithStatus(errors.CodeNotFound, ""))
+	var info *errdetails.ErrorInfo
+	for _, d := range st.Details() {
+		if v, ok := d.(*errdetails.ErrorInfo); ok {
+			info = v
+		}
+	}
+	if info == nil || info.GetReason() != "NOT_FOUND" || info.GetMetadata()["business_code"] != "1004" {
+		t.Fatalf("ErrorInfo = %v", info)
+	}
+
+	// 兼容 Reason 为业务错误码的旧格式
+	legacy, _ := status.New(codes.NotFound, "资源未找到").WithDetails(&errdetails.ErrorInfo{Reason: "1004", Domain: errors.ErrorInfoDomain})
+	if got := errors.FromGRPCStatus(legacy); got.Code() != errors.CodeNotFound || errors.Reason(got) != "NOT_FOUND" {
+		t.Errorf("FromGRPCStatus(legacy) = %d %q", got.Code(), errors.Reason(got))
+	}
+}
@@ -0,0 +1,112 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+// Package rediserr 将 go-redis 返回的错误翻译为 StatusError.
+package rediserr
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	stderrors "errors"
+	"io"
+
+	"github.com/go-anyway/framework-errors"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// NilCode 是 redis.Nil 对应的业务错误码，默认为 CodeNotFound
+// 需要区分缓存未命中与数据不存在的服务可以设置为 errors.CodeCacheMiss.
+var NilCode = errors.CodeNotFound
+
+// IsSensitiveKey 判断缓存 key 是否敏感（例如包含手机号、token）
+// 敏感 key 在 WrapCache 中只记录其哈希值，默认所有 key 都不敏感.
+var IsSensitiveKey = func(key string) bool { return false }
+
+// unavailablePrefixes 是表示 Redis 暂时不可用的服务端错误前缀
+var unavailablePrefixes = []string{"LOADING", "READONLY", "MASTERDOWN", "CLUSTERDOWN", "TRYAGAIN"}
+
+// Translate 将 go-redis 错误翻译为 StatusError，无法识别时返回 nil
+// 连接池超时以及集群切换期间的错误会翻译为可重试的 CodeDependencyUnavailable.
+// 只识别 go-redis 自身的错误类型：io.EOF 与 net.Error 无法判断是否来自 Redis，
+// 交给 errors.TranslateNetErr 处理，已知来自 Redis 的错误应使用 WrapCache.
+func Translate(err error) errors.StatusError {
+	return translate(err)
+}
+
+// WrapCache 翻译缓存操作返回的错误，并在 Extra 中记录缓存 key
+// 如果 IsSensitiveKey(key) 为 true，则记录为 cache_key_hash 而不是原始 key.
+// 错误来自 Redis 客户端，因此 io.EOF（连接被服务端关闭）也会翻译为可重试的 CodeDependencyUnavailable.
+func WrapCache(err error, key string) errors.StatusError {
+	if err == nil {
+		return nil
+	}
+
+	var opt errors.Option
+	if IsSensitiveKey(key) {
+		sum := sha256.Sum256([]byte(key))
+		opt = errors.Extra("cache_key_hash", hex.EncodeToString(sum[:8]))
+	} else {
+		opt = errors.Extra("cache_key", key)
+	}
+
+	if se := translate(err, opt); se != nil {
+		return se
+	}
+	if stderrors.Is(err, io.EOF) || stderrors.Is(err, io.ErrUnexpectedEOF) {
+		return errors.WrapWithStatusOptions(err, errors.CodeDependencyUnavailable, "", opt, errors.OverrideCode())
+	}
+
+	fallback := errors.Translate(err)
+	return errors.WrapWithStatusOptions(err, fallback.Code(), fallback.Msg(), opt, errors.OverrideCode())
+}
+
+// translate 根据错误类型选择业务错误码
+func translate(err error, opts ...errors.Option) errors.StatusError {
+	if err == nil {
+		return nil
+	}
+
+	var code int32
+	switch {
+	case stderrors.Is(err, redis.Nil):
+		code = NilCode
+	case stderrors.Is(err, redis.ErrPoolTimeout),
+		stderrors.Is(err, redis.ErrPoolExhausted),
+		hasUnavailablePrefix(err):
+		code = errors.CodeDependencyUnavailable
+	case stderrors.Is(err, redis.ErrClosed):
+		code = errors.CodeInternalError
+	default:
+		var redisErr redis.Error
+		if !stderrors.As(err, &redisErr) {
+			return nil
+		}
+		code = errors.CodeInternalError
+	}
+
+	return errors.WrapWithStatusOptions(err, code, "", append([]errors.Option{errors.OverrideCode()}, opts...)...)
+}
+
+// hasUnavailablePrefix 判断是否为 Redis 暂时不可用的服务端错误
+func hasUnavailablePrefix(err error) bool {
+	for _, prefix := range unavailablePrefixes {
+		if redis.HasErrorPrefix(err, prefix) {
+			return true
+		}
+	}
+	return false
+}
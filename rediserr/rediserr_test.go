@@ -0,0 +1,104 @@
+package rediserr_test
+
+import (
+	stderrors "errors"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/go-anyway/framework-errors"
+	"github.com/go-anyway/framework-errors/rediserr"
+
+	"github.com/redis/go-redis/v9"
+)
+
+func TestTranslate(t *testing.T) {
+	tests := []struct {
+		name      string
+		err       error
+		expected  int32
+		retryable bool
+	}{
+		{"缓存未命中", redis.Nil, errors.CodeNotFound, false},
+		{"连接池超时", redis.ErrPoolTimeout, errors.CodeDependencyUnavailable, true},
+		{"客户端已关闭", redis.ErrClosed, errors.CodeInternalError, false},
+		{"其他服务端错误", redis.ErrCrossSlot, errors.CodeInternalError, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			se := rediserr.Translate(fmt.Errorf("get: %w", tt.err))
+			if se == nil {
+				t.Fatal("Translate() = nil, want StatusError")
+			}
+			if se.Code() != tt.expected {
+				t.Errorf("Code() = %d, want %d", se.Code(), tt.expected)
+			}
+			if errors.IsRetryable(se) != tt.retryable {
+				t.Errorf("IsRetryable() = %v, want %v", errors.IsRetryable(se), tt.retryable)
+			}
+		})
+	}
+}
+
+func TestTranslateUnknown(t *testing.T) {
+	// 非 go-redis 的错误类型无法判断是否来自 Redis
+	for _, err := range []error{
+		stderrors.New("other"),
+		io.EOF,
+		&net.OpError{Op: "dial", Err: stderrors.New("connection refused")},
+	} {
+		if se := rediserr.Translate(err); se != nil {
+			t.Errorf("Translate(%v) = %v, want nil", err, se)
+		}
+	}
+}
+
+func TestWrapCacheConnErrors(t *testing.T) {
+	for _, err := range []error{
+		io.EOF,
+		&net.OpError{Op: "dial", Err: stderrors.New("connection refused")},
+	} {
+		se := rediserr.WrapCache(fmt.Errorf("get: %w", err), "user:1")
+		if se.Code() != errors.CodeDependencyUnavailable || !errors.IsRetryable(se) {
+			t.Errorf("WrapCache(%v) = (%d, retryable %v)", err, se.Code(), errors.IsRetryable(se))
+		}
+		if se.Extra()["cache_key"] != "user:1" {
+			t.Errorf("Extra[cache_key] = %s, want user:1", se.Extra()["cache_key"])
+		}
+	}
+}
+
+func TestNilCode(t *testing.T) {
+	rediserr.NilCode = errors.CodeCacheMiss
+	defer func() { rediserr.NilCode = errors.CodeNotFound }()
+
+	if se := rediserr.Translate(redis.Nil); se.Code() != errors.CodeCacheMiss {
+		t.Errorf("Code() = %d, want %d", se.Code(), errors.CodeCacheMiss)
+	}
+}
+
+func TestWrapCache(t *testing.T) {
+	se := rediserr.WrapCache(redis.Nil, "user:1")
+	if se.Extra()["cache_key"] != "user:1" {
+		t.Errorf("Extra[cache_key] = %s, want user:1", se.Extra()["cache_key"])
+	}
+
+	rediserr.IsSensitiveKey = func(key string) bool { return strings.HasPrefix(key, "token:") }
+	defer func() { rediserr.IsSensitiveKey = func(string) bool { return false } }()
+
+	se = rediserr.WrapCache(redis.Nil, "token:secret")
+	extra := se.Extra()
+	if _, ok := extra["cache_key"]; ok {
+		t.Error("敏感 key 不应以明文记录")
+	}
+	if extra["cache_key_hash"] == "" {
+		t.Error("敏感 key 应记录哈希值")
+	}
+
+	if rediserr.WrapCache(nil, "user:1") != nil {
+		t.Error("WrapCache(nil) 应返回 nil")
+	}
+}
@@ -0,0 +1,228 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package errors
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base32"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-anyway/framework-log"
+)
+
+// RefIDKey 是错误参考编号在扩展信息中的 key
+const RefIDKey = "ref_id"
+
+// refIDPrefix 是错误参考编号的前缀
+const refIDPrefix = "ERR-"
+
+// refIDEncoding 是 Crockford Base32 字母表，不包含容易混淆的 I、L、O、U，便于用户口头或手工转述
+var refIDEncoding = base32.NewEncoding("0123456789ABCDEFGHJKMNPQRSTVWXYZ").WithPadding(base32.NoPadding)
+
+// newRefID 生成形如 ERR-3M7Q9KXA 的错误参考编号
+// 编号包含 40 位随机数，每天百万级的错误量下发生碰撞的概率仍然很低.
+func newRefID() string {
+	var b [5]byte
+	_, _ = rand.Read(b[:])
+	return refIDPrefix + refIDEncoding.EncodeToString(b[:])
+}
+
+// EnsureRefID 为影响稳定性的错误生成参考编号，记录在 Extra[RefIDKey] 中
+// 参考编号随错误一起记录到日志并返回给客户端，用户反馈的编号可以直接定位到对应的日志.
+// 不影响稳定性或已有参考编号的错误原样返回. WriteError 与 LogAndReturnError 会自动调用.
+func EnsureRefID(err StatusError) StatusError {
+	if err == nil || !err.IsAffectStability() || RefID(err) != "" {
+		return err
+	}
+	return With(err, Extra(RefIDKey, newRefID()))
+}
+
+// RefID 返回 err 的参考编号，没有时返回空字符串
+func RefID(err error) string {
+	var se StatusError
+	if !errors.As(err, &se) {
+		return ""
+	}
+	var ref string
+	RangeExtra(se, func(k, v string) bool {
+		if k == RefIDKey {
+			ref = v
+			return false
+		}
+		return true
+	})
+	return ref
+}
+
+// RefIDStore 保存参考编号与错误指纹、链路 ID 的对应关系
+// 内部工具可以据此将用户反馈的 ERR- 编号直接解析为链路与指纹，无需检索日志.
+type RefIDStore interface {
+	Put(refID, fingerprint, traceID string)
+}
+
+var refIDStore atomic.Pointer[RefIDStore]
+
+// SetRefIDStore 设置 WriteError 与 LogAndReturnError 使用的 RefIDStore，nil 表示不保存（默认）
+func SetRefIDStore(s RefIDStore) {
+	if s == nil {
+		refIDStore.Store(nil)
+		return
+	}
+	refIDStore.Store(&s)
+}
+
+// recordRefID 将 err 的参考编号保存到 RefIDStore，链路 ID 从 ctx 中获取
+func recordRefID(ctx context.Context, err StatusError) {
+	s := refIDStore.Load()
+	if s == nil {
+		return
+	}
+	if ref := RefID(err); ref != "" {
+		(*s).Put(ref, Fingerprint(err), log.TraceIDFromContext(ctx))
+	}
+}
+
+// RefRecord 是 MemoryRefIDStore 中保存的记录
+type RefRecord struct {
+	RefID       string
+	Fingerprint string
+	TraceID     string
+	CreatedAt   time.Time
+}
+
+// defaultRefIDStoreLimit 是 MemoryRefIDStore 默认保存的最大记录数
+const defaultRefIDStoreLimit = 100000
+
+// MemoryRefIDStore 是保存在内存中、按 TTL 过期的 RefIDStore
+// 适用于单实例或配合粘性会话的内部工具，多实例部署应实现基于共享存储的 RefIDStore.
+// 记录数达到上限（默认 100000，见 SetLimit）时淘汰最早写入的记录.
+type MemoryRefIDStore struct {
+	ttl time.Duration
+
+	mu      sync.Mutex
+	limit   int
+	records map[string]memoryRefRecord
+	// order 按写入顺序记录参考编号，TTL 固定，因此越靠前的记录越早过期；
+	// 被覆盖的记录留下的 seq 不匹配的条目在到达队首时丢弃
+	order      []memoryRefEntry
+	seq        uint64
+	collisions uint64
+}
+
+// memoryRefRecord 是 MemoryRefIDStore 中的记录，seq 用于识别 order 中过时的条目
+type memoryRefRecord struct {
+	RefRecord
+	seq uint64
+}
+
+// memoryRefEntry 是 MemoryRefIDStore.order 中的条目
+type memoryRefEntry struct {
+	refID string
+	seq   uint64
+}
+
+// NewMemoryRefIDStore 创建 MemoryRefIDStore，记录在写入 ttl 后过期
+//
+//	store := errors.NewMemoryRefIDStore(24 * time.Hour)
+//	errors.SetRefIDStore(store)
+func NewMemoryRefIDStore(ttl time.Duration) *MemoryRefIDStore {
+	return &MemoryRefIDStore{ttl: ttl, limit: defaultRefIDStoreLimit, records: make(map[string]memoryRefRecord)}
+}
+
+// SetLimit 设置保存的最大记录数，n <= 0 表示不限制
+// 已保存的记录超过 n 时，多出的记录在下次 Put 时淘汰.
+func (s *MemoryRefIDStore) SetLimit(n int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.limit = n
+}
+
+// Put 实现 RefIDStore 接口，同时清理已过期的记录
+// 同一个错误被重复记录时保留第一次的记录；编号已被其他错误使用（碰撞）时以新的记录为准，并计入 Collisions.
+func (s *MemoryRefIDStore) Put(refID, fingerprint, traceID string) {
+	t := now()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for len(s.order) > 0 {
+		e := s.order[0]
+		if r, ok := s.records[e.refID]; ok && r.seq == e.seq {
+			if !s.expired(r.RefRecord, t) {
+				break
+			}
+			delete(s.records, e.refID)
+		}
+		s.order = s.order[1:]
+	}
+
+	old, exists := s.records[refID]
+	if exists {
+		if old.Fingerprint == fingerprint && old.TraceID == traceID {
+			return
+		}
+		s.collisions++
+	}
+	for !exists && s.limit > 0 && len(s.records) >= s.limit {
+		s.evictOldest()
+	}
+
+	s.seq++
+	s.records[refID] = memoryRefRecord{
+		RefRecord: RefRecord{RefID: refID, Fingerprint: fingerprint, TraceID: traceID, CreatedAt: t},
+		seq:       s.seq,
+	}
+	s.order = append(s.order, memoryRefEntry{refID: refID, seq: s.seq})
+}
+
+// evictOldest 淘汰最早写入的记录
+func (s *MemoryRefIDStore) evictOldest() {
+	for len(s.order) > 0 {
+		e := s.order[0]
+		s.order = s.order[1:]
+		if r, ok := s.records[e.refID]; ok && r.seq == e.seq {
+			delete(s.records, e.refID)
+			return
+		}
+	}
+}
+
+// Get 获取参考编号对应的记录，不存在或已过期时返回 false
+func (s *MemoryRefIDStore) Get(refID string) (RefRecord, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	r, ok := s.records[refID]
+	if !ok || s.expired(r.RefRecord, now()) {
+		return RefRecord{}, false
+	}
+	return r.RefRecord, true
+}
+
+// Collisions 返回不同的错误使用了相同参考编号的次数
+func (s *MemoryRefIDStore) Collisions() uint64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.collisions
+}
+
+// expired 判断记录在 t 时是否已过期
+func (s *MemoryRefIDStore) expired(r RefRecord, t time.Time) bool {
+	return t.Sub(r.CreatedAt) >= s.ttl
+}
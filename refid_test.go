@@ -0,0 +1,156 @@
+package errors_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/go-anyway/framework-log"
+	"google.golang.org/grpc/status"
+
+	"github.com/go-anyway/framework-errors"
+)
+
+var refIDPattern = regexp.MustCompile(`^ERR-[0-9A-HJKMNP-TV-Z]{8}$`)
+
+func TestEnsureRefID(t *testing.T) {
+	tests := []struct {
+		name    string
+		err     errors.StatusError
+		wantRef bool
+	}{
+		{name: "影响稳定性", err: errors.NewWithStatus(errors.CodeInternalError, ""), wantRef: true},
+		{name: "不影响稳定性", err: errors.NewWithStatus(errors.CodeNotFound, ""), wantRef: false},
+		{name: "nil", err: nil, wantRef: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := errors.EnsureRefID(tt.err)
+			ref := errors.RefID(got)
+			if (ref != "") != tt.wantRef {
+				t.Fatalf("RefID() = %q, wantRef %v", ref, tt.wantRef)
+			}
+			if tt.wantRef && !refIDPattern.MatchString(ref) {
+				t.Errorf("RefID() = %q, 格式错误", ref)
+			}
+			// 重复调用保持不变
+			if again := errors.RefID(errors.EnsureRefID(got)); again != ref {
+				t.Errorf("RefID() = %q, want %q", again, ref)
+			}
+		})
+	}
+}
+
+func TestRefIDInResponses(t *testing.T) {
+	rec := httptest.NewRecorder()
+	errors.WriteError(rec, httptest.NewRequest(http.MethodGet, "/", nil), errors.NewWithStatus(errors.CodeInternalError, ""))
+	var env errors.Envelope
+	if err := json.Unmarshal(rec.Body.Bytes(), &env); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+	if !refIDPattern.MatchString(env.Extra[errors.RefIDKey]) {
+		t.Errorf("Envelope.Extra = %v, 应包含参考编号", env.Extra)
+	}
+
+	grpcErr := errors.LogAndReturnError(context.Background(), errors.NewWithStatus(errors.CodeInternalError, ""))
+	st, _ := status.FromError(grpcErr)
+	if ref := errors.RefID(errors.FromGRPCStatus(st)); !refIDPattern.MatchString(ref) {
+		t.Errorf("RefID() = %q, gRPC 错误应包含参考编号", ref)
+	}
+}
+
+func TestMemoryRefIDStore(t *testing.T) {
+	current := time.Unix(1700000000, 0)
+	defer errors.SetClock(func() time.Time { return current })()
+
+	store := errors.NewMemoryRefIDStore(time.Hour)
+	store.Put("ERR-000001", "fp1", "trace1")
+
+	current = current.Add(30 * time.Minute)
+	store.Put("ERR-000002", "fp2", "trace2")
+	if r, ok := store.Get("ERR-000001"); !ok || r.Fingerprint != "fp1" || r.TraceID != "trace1" {
+		t.Errorf("Get() = %+v, %v", r, ok)
+	}
+
+	current = current.Add(45 * time.Minute)
+	if _, ok := store.Get("ERR-000001"); ok {
+		t.Error("Get() 应返回 false，记录已过期")
+	}
+	if _, ok := store.Get("ERR-000002"); !ok {
+		t.Error("Get() 应返回 true，记录未过期")
+	}
+}
+
+func TestMemoryRefIDStoreCollision(t *testing.T) {
+	current := time.Unix(1700000000, 0)
+	defer errors.SetClock(func() time.Time { return current })()
+
+	store := errors.NewMemoryRefIDStore(time.Hour)
+	store.Put("ERR-000001", "fp1", "trace1")
+	store.Put("ERR-000001", "fp1", "trace1")
+	if n := store.Collisions(); n != 0 {
+		t.Errorf("Collisions() = %d, 同一个错误重复记录不是碰撞", n)
+	}
+
+	current = current.Add(30 * time.Minute)
+	store.Put("ERR-000001", "fp2", "trace2")
+	if r, ok := store.Get("ERR-000001"); !ok || r.Fingerprint != "fp2" || r.TraceID != "trace2" {
+		t.Errorf("Get() = %+v, %v, 碰撞时应以新的记录为准", r, ok)
+	}
+	if n := store.Collisions(); n != 1 {
+		t.Errorf("Collisions() = %d, want 1", n)
+	}
+
+	// 新记录的过期时间从覆盖时开始计算
+	current = current.Add(45 * time.Minute)
+	store.Put("ERR-000002", "fp3", "trace3")
+	if _, ok := store.Get("ERR-000001"); !ok {
+		t.Error("Get() 应返回 true，覆盖后的记录未过期")
+	}
+}
+
+func TestMemoryRefIDStoreLimit(t *testing.T) {
+	store := errors.NewMemoryRefIDStore(time.Hour)
+	store.SetLimit(2)
+	store.Put("ERR-000001", "fp1", "")
+	store.Put("ERR-000002", "fp2", "")
+	store.Put("ERR-000001", "fp1b", "")
+	store.Put("ERR-000003", "fp3", "")
+
+	tests := []struct {
+		refID string
+		want  bool
+	}{
+		{refID: "ERR-000001", want: true},
+		{refID: "ERR-000002", want: false},
+		{refID: "ERR-000003", want: true},
+	}
+	for _, tt := range tests {
+		if _, ok := store.Get(tt.refID); ok != tt.want {
+			t.Errorf("Get(%q) = %v, want %v", tt.refID, ok, tt.want)
+		}
+	}
+}
+
+func TestSetRefIDStore(t *testing.T) {
+	store := errors.NewMemoryRefIDStore(time.Hour)
+	errors.SetRefIDStore(store)
+	defer errors.SetRefIDStore(nil)
+
+	ctx := log.ContextWithTraceID(context.Background(), "trace-42")
+	grpcErr := errors.LogAndReturnError(ctx, errors.NewWithStatus(errors.CodeInternalError, ""))
+	st, _ := status.FromError(grpcErr)
+	ref := errors.RefID(errors.FromGRPCStatus(st))
+
+	r, ok := store.Get(ref)
+	if !ok {
+		t.Fatalf("Get(%q) 应返回 true", ref)
+	}
+	if r.TraceID != "trace-42" || r.Fingerprint == "" {
+		t.Errorf("Get() = %+v", r)
+	}
+}
@@ -0,0 +1,148 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package errors
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+)
+
+// CatalogEntry 是错误码对外契约的快照，见 Catalog
+type CatalogEntry struct {
+	Message         string `json:"message"`
+	Reason          string `json:"reason,omitempty"`
+	HTTP            int    `json:"http"`
+	GRPC            string `json:"grpc"`
+	Retryable       bool   `json:"retryable,omitempty"`
+	AffectStability bool   `json:"affect_stability,omitempty"`
+}
+
+// Catalog 是错误码注册表的快照，可以序列化为 JSON 提交到代码仓库，供 DiffRegistries 比较
+type Catalog map[int32]CatalogEntry
+
+// CurrentCatalog 返回当前已注册的错误码（CodeDefinitions）及其 HTTP 状态码与 gRPC code 映射的快照
+func CurrentCatalog() Catalog {
+	c := make(Catalog, len(CodeDefinitions))
+	for code := range CodeDefinitions {
+		def := GetCodeDefinition(code)
+		c[code] = CatalogEntry{
+			Message:         def.Message,
+			Reason:          def.Reason,
+			HTTP:            HTTPStatus(code),
+			GRPC:            GRPCCode(code).String(),
+			Retryable:       def.Retryable,
+			AffectStability: def.IsAffectStability,
+		}
+	}
+	return c
+}
+
+// RegistryChangeKind 是错误码契约变更的类型
+type RegistryChangeKind int
+
+const (
+	// RegistryCodeRemoved 表示错误码被删除
+	RegistryCodeRemoved RegistryChangeKind = iota + 1
+	// RegistryReasonChanged 表示错误原因（即错误码的含义）发生变化
+	RegistryReasonChanged
+	// RegistryHTTPChanged 表示 HTTP 状态码映射发生变化
+	RegistryHTTPChanged
+	// RegistryGRPCChanged 表示 gRPC code 映射发生变化
+	RegistryGRPCChanged
+	// RegistryRetryableChanged 表示是否可以重试发生变化，调用方的重试行为会随之改变
+	RegistryRetryableChanged
+)
+
+// registryChangeKindNames 是各变更类型的名称
+var registryChangeKindNames = [...]string{
+	RegistryCodeRemoved:      "removed",
+	RegistryReasonChanged:    "reason",
+	RegistryHTTPChanged:      "http",
+	RegistryGRPCChanged:      "grpc",
+	RegistryRetryableChanged: "retryable",
+}
+
+// String 返回变更类型的名称，例如 "http"
+func (k RegistryChangeKind) String() string {
+	if k <= 0 || int(k) >= len(registryChangeKindNames) {
+		return "unknown"
+	}
+	return registryChangeKindNames[k]
+}
+
+// RegistryChange 是一个不兼容的错误码契约变更
+type RegistryChange struct {
+	Code int32
+	Kind RegistryChangeKind
+	// Old 与 New 是变更前后的值，错误码被删除时 Old 是原来的错误原因（没有时为消息），New 为空
+	Old, New string
+}
+
+// String 返回便于在 CI 中阅读的描述，例如 "1004: http 404 -> 400"
+func (c RegistryChange) String() string {
+	if c.Kind == RegistryCodeRemoved {
+		return fmt.Sprintf("%d: removed (%s)", c.Code, c.Old)
+	}
+	return fmt.Sprintf("%d: %s %s -> %s", c.Code, c.Kind, c.Old, c.New)
+}
+
+// DiffRegistries 比较两个版本的错误码注册表，返回按错误码排序的不兼容变更
+// 报告被删除的错误码、错误原因的变化以及 HTTP 状态码、gRPC code 与可重试标记的变化；
+// 新增错误码、消息文案与 AffectStability 的变化不影响调用方，不会报告. 旧版本没有错误原因时不视为含义变化.
+// 下游服务可以在 CI 中将上一个发布版本的快照与 CurrentCatalog 比较，在上线前发现不兼容的变更：
+//
+//	var old errors.Catalog
+//	data, _ := os.ReadFile("testdata/error_catalog.json")
+//	_ = json.Unmarshal(data, &old)
+//	for _, c := range errors.DiffRegistries(old, errors.CurrentCatalog()) {
+//		t.Errorf("不兼容的错误码变更：%s", c)
+//	}
+func DiffRegistries(old, new Catalog) []RegistryChange {
+	codes := make([]int32, 0, len(old))
+	for code := range old {
+		codes = append(codes, code)
+	}
+	sort.Slice(codes, func(i, j int) bool { return codes[i] < codes[j] })
+
+	var changes []RegistryChange
+	for _, code := range codes {
+		o := old[code]
+		n, ok := new[code]
+		if !ok {
+			name := o.Reason
+			if name == "" {
+				name = o.Message
+			}
+			changes = append(changes, RegistryChange{Code: code, Kind: RegistryCodeRemoved, Old: name})
+			continue
+		}
+		if o.Reason != "" && o.Reason != n.Reason {
+			changes = append(changes, RegistryChange{Code: code, Kind: RegistryReasonChanged, Old: o.Reason, New: n.Reason})
+		}
+		if o.HTTP != n.HTTP {
+			changes = append(changes, RegistryChange{Code: code, Kind: RegistryHTTPChanged, Old: strconv.Itoa(o.HTTP), New: strconv.Itoa(n.HTTP)})
+		}
+		if o.GRPC != n.GRPC {
+			changes = append(changes, RegistryChange{Code: code, Kind: RegistryGRPCChanged, Old: o.GRPC, New: n.GRPC})
+		}
+		if o.Retryable != n.Retryable {
+			changes = append(changes, RegistryChange{Code: code, Kind: RegistryRetryableChanged, Old: strconv.FormatBool(o.Retryable), New: strconv.FormatBool(n.Retryable)})
+		}
+	}
+	return changes
+}
@@ -0,0 +1,111 @@
+package errors_test
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/go-anyway/framework-errors"
+)
+
+func TestDiffRegistries(t *testing.T) {
+	base := errors.Catalog{
+		1004:  {Message: "资源未找到", Reason: "NOT_FOUND", HTTP: 404, GRPC: "NotFound"},
+		2003:  {Message: "请求过于频繁", Reason: "RATE_LIMIT_EXCEEDED", HTTP: 429, GRPC: "ResourceExhausted", Retryable: true},
+		20001: {Message: "订单已关闭", HTTP: 409, GRPC: "FailedPrecondition"},
+	}
+	with := func(code int32, f func(*errors.CatalogEntry)) errors.Catalog {
+		c := make(errors.Catalog, len(base))
+		for k, v := range base {
+			c[k] = v
+		}
+		e := c[code]
+		f(&e)
+		c[code] = e
+		return c
+	}
+
+	tests := []struct {
+		name string
+		new  errors.Catalog
+		want []string
+	}{
+		{
+			name: "没有变化",
+			new:  base,
+		},
+		{
+			name: "新增错误码不报告",
+			new: with(20002, func(e *errors.CatalogEntry) {
+				e.Message, e.HTTP = "订单已锁定", 409
+			}),
+		},
+		{
+			name: "修改文案与稳定性标记不报告",
+			new:  with(1004, func(e *errors.CatalogEntry) { e.Message, e.AffectStability = "not found", true }),
+		},
+		{
+			name: "删除错误码",
+			new: func() errors.Catalog {
+				c := with(1004, func(*errors.CatalogEntry) {})
+				delete(c, 1004)
+				delete(c, 20001)
+				return c
+			}(),
+			want: []string{"1004: removed (NOT_FOUND)", "20001: removed (订单已关闭)"},
+		},
+		{
+			name: "含义与映射变化",
+			new: with(2003, func(e *errors.CatalogEntry) {
+				e.Reason, e.HTTP, e.GRPC, e.Retryable = "QUOTA_EXCEEDED", 503, "Unavailable", false
+			}),
+			want: []string{
+				"2003: reason RATE_LIMIT_EXCEEDED -> QUOTA_EXCEEDED",
+				"2003: http 429 -> 503",
+				"2003: grpc ResourceExhausted -> Unavailable",
+				"2003: retryable true -> false",
+			},
+		},
+		{
+			name: "旧版本没有错误原因",
+			new:  with(20001, func(e *errors.CatalogEntry) { e.Reason = "ORDER_CLOSED" }),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, c := range errors.DiffRegistries(base, tt.new) {
+				got = append(got, c.String())
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("DiffRegistries() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCurrentCatalogRoundTrip(t *testing.T) {
+	cur := errors.CurrentCatalog()
+	if e := cur[errors.CodeNotFound]; e.Reason != "NOT_FOUND" || e.HTTP != 404 || e.GRPC != "NotFound" {
+		t.Errorf("CurrentCatalog()[CodeNotFound] = %+v", e)
+	}
+
+	data, err := json.Marshal(cur)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var old errors.Catalog
+	if err := json.Unmarshal(data, &old); err != nil {
+		t.Fatal(err)
+	}
+	if changes := errors.DiffRegistries(old, errors.CurrentCatalog()); len(changes) != 0 {
+		t.Errorf("DiffRegistries() = %v, want none", changes)
+	}
+
+	const codeOrderClosed int32 = 20001
+	errors.CodeDefinitions[codeOrderClosed] = errors.CodeDefinition{Message: "订单已关闭"}
+	defer delete(errors.CodeDefinitions, codeOrderClosed)
+	if changes := errors.DiffRegistries(errors.CurrentCatalog(), old); len(changes) != 1 || changes[0].Kind != errors.RegistryCodeRemoved {
+		t.Errorf("DiffRegistries() = %v, want 20001 removed", changes)
+	}
+}
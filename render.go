@@ -0,0 +1,235 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package errors
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Renderer 负责将错误写入 HTTP 响应
+// 传入的 err 已经过本地化，且 Extra 中不包含堆栈等不应对外暴露的信息，
+// 实现只需关心响应体的格式. 可以通过 HTTPStatus(err.Code()) 获取映射后的状态码.
+type Renderer interface {
+	Render(w http.ResponseWriter, r *http.Request, err StatusError)
+}
+
+// RendererFunc 是函数形式的 Renderer
+//
+//	errors.DefaultRenderer = errors.RendererFunc(func(w http.ResponseWriter, r *http.Request, err errors.StatusError) {
+//		w.WriteHeader(errors.HTTPStatus(err.Code()))
+//		_ = json.NewEncoder(w).Encode(map[string]interface{}{"errno": err.Code(), "errmsg": err.Msg()})
+//	})
+type RendererFunc func(w http.ResponseWriter, r *http.Request, err StatusError)
+
+// Render 实现 Renderer 接口
+func (f RendererFunc) Render(w http.ResponseWriter, r *http.Request, err StatusError) {
+	f(w, r, err)
+}
+
+// JSONRenderer 将错误渲染为标准 JSON 结构（Envelope）
+type JSONRenderer struct{}
+
+// Render 实现 Renderer 接口
+func (JSONRenderer) Render(w http.ResponseWriter, r *http.Request, err StatusError) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(HTTPStatus(err.Code()))
+	_ = json.NewEncoder(w).Encode(NewEnvelope(err))
+}
+
+// XMLRenderer 将错误渲染为 XML，用于仍要求 XML 错误报文的旧系统
+//
+//	<error><code>1004</code><message>资源未找到</message><extra><item key="id">42</item></extra></error>
+type XMLRenderer struct{}
+
+type xmlExtraItem struct {
+	Key   string `xml:"key,attr"`
+	Value string `xml:",chardata"`
+}
+
+type xmlEnvelope struct {
+	XMLName xml.Name       `xml:"error"`
+	Code    int32          `xml:"code"`
+	Reason  string         `xml:"reason,omitempty"`
+	Message string         `xml:"message"`
+	Extra   []xmlExtraItem `xml:"extra>item,omitempty"`
+}
+
+// Render 实现 Renderer 接口
+func (XMLRenderer) Render(w http.ResponseWriter, r *http.Request, err StatusError) {
+	env := NewEnvelope(err)
+	body := xmlEnvelope{Code: env.Code, Reason: env.Reason, Message: env.Message}
+	for k, v := range env.Extra {
+		body.Extra = append(body.Extra, xmlExtraItem{Key: k, Value: v})
+	}
+	sort.Slice(body.Extra, func(i, j int) bool { return body.Extra[i].Key < body.Extra[j].Key })
+
+	w.Header().Set("Content-Type", "application/xml; charset=utf-8")
+	w.WriteHeader(HTTPStatus(env.Code))
+	_, _ = w.Write([]byte(xml.Header))
+	_ = xml.NewEncoder(w).Encode(body)
+}
+
+// NegotiatingRenderer 根据请求的 Accept 头在 JSON 与 XML 之间选择
+// 客户端明确偏好 application/xml 或 text/xml 时使用 XML，其余情况使用 JSON.
+type NegotiatingRenderer struct {
+	JSON Renderer
+	XML  Renderer
+}
+
+// Render 实现 Renderer 接口
+func (n NegotiatingRenderer) Render(w http.ResponseWriter, r *http.Request, err StatusError) {
+	if r != nil && prefersXML(r.Header.Get("Accept")) {
+		if n.XML != nil {
+			n.XML.Render(w, r, err)
+		} else {
+			XMLRenderer{}.Render(w, r, err)
+		}
+		return
+	}
+	if n.JSON != nil {
+		n.JSON.Render(w, r, err)
+	} else {
+		JSONRenderer{}.Render(w, r, err)
+	}
+}
+
+// prefersXML 判断 Accept 头中 XML 的优先级是否高于 JSON
+func prefersXML(accept string) bool {
+	type candidate struct {
+		mediaType string
+		q         float64
+	}
+
+	var candidates []candidate
+	for _, part := range strings.Split(accept, ",") {
+		mediaType, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		if mediaType == "" {
+			continue
+		}
+		q := 1.0
+		for _, param := range strings.Split(params, ";") {
+			if v, ok := strings.CutPrefix(strings.TrimSpace(param), "q="); ok {
+				if parsed, err := strconv.ParseFloat(v, 64); err == nil {
+					q = parsed
+				}
+			}
+		}
+		if q > 0 {
+			candidates = append(candidates, candidate{mediaType: strings.ToLower(mediaType), q: q})
+		}
+	}
+	sort.SliceStable(candidates, func(i, j int) bool { return candidates[i].q > candidates[j].q })
+
+	for _, c := range candidates {
+		switch {
+		case c.mediaType == "application/xml" || c.mediaType == "text/xml" || strings.HasSuffix(c.mediaType, "+xml"):
+			return true
+		case c.mediaType == "application/json" || strings.HasSuffix(c.mediaType, "+json") ||
+			c.mediaType == "application/*" || c.mediaType == "*/*":
+			return false
+		}
+	}
+	return false
+}
+
+// DefaultRenderer 是 WriteError、Handler 与 Middleware 使用的 Renderer
+var DefaultRenderer Renderer = JSONRenderer{}
+
+// DebugInfo 是调试模式下 HTTP 响应中附带的诊断信息
+// 通过 Config.DebugDetails 全局开启，或通过 WithDebugErrors 对单个请求开启.
+type DebugInfo struct {
+	// Stack 是错误的调用堆栈
+	Stack string `json:"stack,omitempty"`
+	// Causes 是错误链中各层错误的消息，从外到内，已经过 Scrubber 处理
+	Causes []string `json:"causes,omitempty"`
+	// Internal 是不会跨服务传递的扩展信息，包括 InternalExtra 与白名单之外的扩展信息
+	Internal map[string]string `json:"internal,omitempty"`
+	// Request 是失败请求的快照，见 AttachRequestSnapshot
+	Request json.RawMessage `json:"request,omitempty"`
+}
+
+// debugStatusError 是附带诊断信息的 statusError，NewEnvelope 会将其输出到 Envelope.Debug
+type debugStatusError struct {
+	*statusError
+	debug *DebugInfo
+}
+
+// debugInfo 返回诊断信息
+func (e *debugStatusError) debugInfo() *DebugInfo {
+	return e.debug
+}
+
+// newDebugInfo 收集 se 的诊断信息，扩展信息与错误链中的消息仍会经过 Scrubber 处理
+func newDebugInfo(se StatusError) *DebugInfo {
+	public := make(map[string]bool)
+	RangePublicExtra(se, func(k, _ string) bool {
+		public[k] = true
+		return true
+	})
+
+	debug := &DebugInfo{}
+	rangeScrubbedExtra(func(fn func(k, v string) bool) { RangeExtra(se, fn) }, func(k, v string) bool {
+		switch {
+		case k == "stack":
+			debug.Stack = v
+		case k == RequestSnapshotKey && json.Valid([]byte(v)):
+			debug.Request = json.RawMessage(v)
+		case !public[k]:
+			if debug.Internal == nil {
+				debug.Internal = make(map[string]string)
+			}
+			debug.Internal[k] = v
+		}
+		return true
+	})
+	for _, cause := range Chain(se)[1:] {
+		debug.Causes = append(debug.Causes, scrubbedError(cause))
+	}
+	return debug
+}
+
+// publicView 返回用于对外输出的错误副本
+// 消息按 locale 本地化，Extra 中去掉堆栈信息. debug 或 Config.DebugDetails 为 true 时
+// 附带堆栈、错误链与内部扩展信息，见 DebugInfo.
+func publicView(se StatusError, locale string, debug bool) StatusError {
+	extra := make(map[string]string)
+	RangePublicExtra(se, func(k, v string) bool {
+		if k != "stack" {
+			extra[k] = v
+		}
+		return true
+	})
+
+	view := &statusError{
+		statusCode: se.Code(),
+		message:    Localize(se, locale),
+		ext: Extension{
+			IsAffectStability: se.IsAffectStability(),
+			Retryable:         IsRetryable(se),
+			Extra:             extra,
+		},
+	}
+	if debug || debugDetails.Load() {
+		return &debugStatusError{statusError: view, debug: newDebugInfo(se)}
+	}
+	return view
+}
@@ -0,0 +1,92 @@
+package errors_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-anyway/framework-errors"
+)
+
+func TestCustomRenderer(t *testing.T) {
+	original := errors.DefaultRenderer
+	defer func() { errors.DefaultRenderer = original }()
+
+	errors.DefaultRenderer = errors.RendererFunc(func(w http.ResponseWriter, r *http.Request, err errors.StatusError) {
+		if _, ok := err.Extra()["stack"]; ok {
+			t.Error("Renderer 不应收到堆栈信息")
+		}
+		w.WriteHeader(errors.HTTPStatus(err.Code()))
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"errno": err.Code(), "errmsg": err.Msg()})
+	})
+
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Accept-Language", "en")
+	errors.WriteError(rec, req, errors.NewWithStatus(errors.CodeNotFound, ""))
+
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("status = %d, want %d", rec.Code, http.StatusNotFound)
+	}
+	var body struct {
+		Errno  int32  `json:"errno"`
+		Errmsg string `json:"errmsg"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+	if body.Errno != errors.CodeNotFound || body.Errmsg != "resource not found" {
+		t.Errorf("body = %+v", body)
+	}
+	if rec.Header().Get("Content-Language") != "en" {
+		t.Errorf("Content-Language = %s, want en", rec.Header().Get("Content-Language"))
+	}
+}
+
+func TestNegotiatingRenderer(t *testing.T) {
+	original := errors.DefaultRenderer
+	defer func() { errors.DefaultRenderer = original }()
+	errors.DefaultRenderer = errors.NegotiatingRenderer{}
+
+	tests := []struct {
+		name        string
+		accept      string
+		contentType string
+	}{
+		{name: "未指定 Accept 使用 JSON", accept: "", contentType: "application/json; charset=utf-8"},
+		{name: "偏好 XML", accept: "application/xml", contentType: "application/xml; charset=utf-8"},
+		{name: "text/xml", accept: "text/xml, */*;q=0.1", contentType: "application/xml; charset=utf-8"},
+		{name: "JSON 优先级更高", accept: "application/xml;q=0.5, application/json", contentType: "application/json; charset=utf-8"},
+		{name: "任意类型使用 JSON", accept: "*/*", contentType: "application/json; charset=utf-8"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.Header.Set("Accept", tt.accept)
+			errors.WriteError(rec, req, errors.NewWithStatus(errors.CodeNotFound, ""))
+
+			if got := rec.Header().Get("Content-Type"); got != tt.contentType {
+				t.Errorf("Content-Type = %s, want %s", got, tt.contentType)
+			}
+			if rec.Code != http.StatusNotFound {
+				t.Errorf("status = %d, want %d", rec.Code, http.StatusNotFound)
+			}
+		})
+	}
+}
+
+func TestXMLRenderer(t *testing.T) {
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	err := errors.NewWithStatus(errors.CodeNotFound, "订单不存在", errors.Extra("order_id", "42"), errors.Extra("a", "<b>"))
+	errors.XMLRenderer{}.Render(rec, req, err)
+
+	want := `<?xml version="1.0" encoding="UTF-8"?>` + "\n" +
+		`<error><code>1004</code><reason>NOT_FOUND</reason><message>订单不存在</message><extra><item key="a">&lt;b&gt;</item><item key="order_id">42</item></extra></error>`
+	if got := rec.Body.String(); got != want {
+		t.Errorf("body = %s, want %s", got, want)
+	}
+}
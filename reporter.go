@@ -0,0 +1,203 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package errors
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+)
+
+// Reporter 接收 LogAndReturnError 与 WriteError 处理的错误，用于对接 Sentry、指标、审计等系统
+// Report 在后台 goroutine 中调用，err 是处理时的快照，可以安全地保留.
+type Reporter interface {
+	Report(ctx context.Context, err StatusError)
+}
+
+// ReporterFunc 是函数形式的 Reporter
+type ReporterFunc func(ctx context.Context, err StatusError)
+
+// Report 实现 Reporter 接口
+func (f ReporterFunc) Report(ctx context.Context, err StatusError) {
+	f(ctx, err)
+}
+
+const (
+	// reportQueueSize 是等待分发的错误的最大数量，队列满时新的错误被丢弃
+	reportQueueSize = 1024
+	// reportWorkers 是分发错误的 goroutine 数量
+	reportWorkers = 4
+)
+
+// reportJob 是等待分发的错误
+type reportJob struct {
+	ctx context.Context
+	err StatusError
+}
+
+var (
+	reportersMu sync.Mutex
+	// reporters 与 syncReporters 只在注册时整体替换，读取时无需加锁
+	reporters     atomic.Pointer[[]Reporter]
+	syncReporters atomic.Pointer[[]Reporter]
+
+	reportQueue   chan reportJob
+	reportStart   sync.Once
+	reportDropped atomic.Uint64
+
+	// reportPending 是已进入队列但尚未分发完成的错误数量，用于 FlushReports
+	reportPendingMu sync.Mutex
+	reportIdle      = sync.NewCond(&reportPendingMu)
+	reportPending   int
+)
+
+// syncReporter 由不允许丢失错误的 Reporter 实现，RegisterReporter 会将其作为同步 Reporter 注册
+type syncReporter interface {
+	Reporter
+	reportSync()
+}
+
+// RegisterReporter 注册 Reporter
+// 错误通过有界队列由后台 goroutine 分发，上报系统变慢时不会阻塞请求，
+// 队列已满时错误被丢弃并计入 DroppedReports. NewAuditReporter 返回的 Reporter 总是同步调用，见 RegisterSyncReporter.
+func RegisterReporter(r Reporter) {
+	if r == nil {
+		return
+	}
+	if _, ok := r.(syncReporter); ok {
+		RegisterSyncReporter(r)
+		return
+	}
+	reportersMu.Lock()
+	defer reportersMu.Unlock()
+	appendReporter(&reporters, r)
+
+	reportStart.Do(func() {
+		reportQueue = make(chan reportJob, reportQueueSize)
+		for i := 0; i < reportWorkers; i++ {
+			go reportWorker()
+		}
+	})
+}
+
+// RegisterSyncReporter 注册同步调用的 Reporter，适用于审计等不允许丢失的场景
+// Report 在处理错误的 goroutine 中调用，返回前会阻塞请求，因此错误不会因为队列已满而被丢弃，
+// 不计入 DroppedReports. Report 应尽快返回，耗时的处理（例如写入远程存储）应由 Reporter 自行保证可靠性.
+func RegisterSyncReporter(r Reporter) {
+	if r == nil {
+		return
+	}
+	reportersMu.Lock()
+	defer reportersMu.Unlock()
+	appendReporter(&syncReporters, r)
+}
+
+// appendReporter 复制 p 中的 Reporter 列表并追加 r，调用方需持有 reportersMu
+func appendReporter(p *atomic.Pointer[[]Reporter], r Reporter) {
+	var list []Reporter
+	if old := p.Load(); old != nil {
+		list = append(list, *old...)
+	}
+	list = append(list, r)
+	p.Store(&list)
+}
+
+// DroppedReports 返回因队列已满而被丢弃的错误数量
+func DroppedReports() uint64 {
+	return reportDropped.Load()
+}
+
+// FlushReports 等待已进入队列的错误分发完成，ctx 结束时返回 ctx.Err()
+// 适用于进程退出前或测试中.
+func FlushReports(ctx context.Context) error {
+	// ctx 结束时唤醒等待，持有锁再 Broadcast 保证不会错过正在进入 Wait 的调用
+	stop := context.AfterFunc(ctx, func() {
+		reportPendingMu.Lock()
+		defer reportPendingMu.Unlock()
+		reportIdle.Broadcast()
+	})
+	defer stop()
+
+	reportPendingMu.Lock()
+	defer reportPendingMu.Unlock()
+	for reportPending > 0 {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		reportIdle.Wait()
+	}
+	return nil
+}
+
+// report 依次调用同步 Reporter，再将 err 的快照放入分发队列，没有注册 Reporter 时不做任何事
+func report(ctx context.Context, err StatusError) {
+	if err == nil || (reporters.Load() == nil && syncReporters.Load() == nil) {
+		return
+	}
+
+	// err 可能在返回后被归还对象池，Reporter 使用的是复制后的快照
+	snapshot := With(err)
+	if list := syncReporters.Load(); list != nil {
+		for _, r := range *list {
+			callReporter(ctx, r, snapshot)
+		}
+	}
+	if reporters.Load() == nil {
+		return
+	}
+
+	// 请求结束后 ctx 会被取消，但其中的值（如链路 ID）仍然可用
+	job := reportJob{ctx: context.WithoutCancel(ctx), err: snapshot}
+	addReportPending(1)
+	select {
+	case reportQueue <- job:
+	default:
+		addReportPending(-1)
+		reportDropped.Add(1)
+	}
+}
+
+// reportWorker 从队列中取出错误并依次调用所有 Reporter
+func reportWorker() {
+	for job := range reportQueue {
+		dispatchReport(job)
+	}
+}
+
+// dispatchReport 调用所有 Reporter，单个 Reporter panic 不影响其他 Reporter
+func dispatchReport(job reportJob) {
+	defer addReportPending(-1)
+	for _, r := range *reporters.Load() {
+		callReporter(job.ctx, r, job.err)
+	}
+}
+
+// callReporter 调用 r，r panic 时忽略
+func callReporter(ctx context.Context, r Reporter, err StatusError) {
+	defer func() { _ = recover() }()
+	r.Report(ctx, err)
+}
+
+// addReportPending 修改等待分发的错误数量，归零时唤醒 FlushReports
+func addReportPending(delta int) {
+	reportPendingMu.Lock()
+	defer reportPendingMu.Unlock()
+	reportPending += delta
+	if reportPending == 0 {
+		reportIdle.Broadcast()
+	}
+}
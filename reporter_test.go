@@ -0,0 +1,100 @@
+package errors_test
+
+import (
+	"context"
+	"runtime"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-anyway/framework-errors"
+)
+
+func TestRegisterReporter(t *testing.T) {
+	defer errors.ResetReporters()
+
+	var mu sync.Mutex
+	var got []errors.StatusError
+	errors.RegisterReporter(errors.ReporterFunc(func(ctx context.Context, err errors.StatusError) {
+		panic("reporter panic")
+	}))
+	errors.RegisterReporter(errors.ReporterFunc(func(ctx context.Context, err errors.StatusError) {
+		mu.Lock()
+		defer mu.Unlock()
+		got = append(got, err)
+	}))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	_ = errors.LogAndReturnError(ctx, errors.NewWithStatus(errors.CodeNotFound, "", errors.Extra("id", "42")))
+	cancel()
+
+	flushCtx, flushCancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer flushCancel()
+	if err := errors.FlushReports(flushCtx); err != nil {
+		t.Fatalf("FlushReports() error = %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(got) != 1 || got[0].Code() != errors.CodeNotFound || got[0].Extra()["id"] != "42" {
+		t.Errorf("reported = %v", got)
+	}
+}
+
+func TestReporterQueueOverflow(t *testing.T) {
+	defer errors.ResetReporters()
+
+	release := make(chan struct{})
+	errors.RegisterReporter(errors.ReporterFunc(func(ctx context.Context, err errors.StatusError) {
+		<-release
+	}))
+
+	before := errors.DroppedReports()
+	start := time.Now()
+	for i := 0; i < 2000; i++ {
+		_ = errors.LogAndReturnError(context.Background(), errors.NewStatusError(errors.CodeNotFound, "", nil), errors.SkipLog())
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("elapsed = %v, 上报不应阻塞调用方", elapsed)
+	}
+	if dropped := errors.DroppedReports() - before; dropped == 0 {
+		t.Error("DroppedReports() 应统计队列已满时丢弃的错误")
+	}
+
+	close(release)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := errors.FlushReports(ctx); err != nil {
+		t.Fatalf("FlushReports() error = %v", err)
+	}
+}
+
+func TestFlushReportsTimeout(t *testing.T) {
+	defer errors.ResetReporters()
+
+	release := make(chan struct{})
+	errors.RegisterReporter(errors.ReporterFunc(func(ctx context.Context, err errors.StatusError) {
+		<-release
+	}))
+	_ = errors.LogAndReturnError(context.Background(), errors.NewStatusError(errors.CodeNotFound, "", nil), errors.SkipLog())
+
+	before := runtime.NumGoroutine()
+	for i := 0; i < 50; i++ {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+		if err := errors.FlushReports(ctx); err != context.DeadlineExceeded {
+			t.Fatalf("FlushReports() error = %v, want %v", err, context.DeadlineExceeded)
+		}
+		cancel()
+	}
+	time.Sleep(10 * time.Millisecond)
+	if leaked := runtime.NumGoroutine() - before; leaked > 5 {
+		t.Errorf("FlushReports() 超时后泄漏了 %d 个 goroutine", leaked)
+	}
+
+	close(release)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := errors.FlushReports(ctx); err != nil {
+		t.Fatalf("FlushReports() error = %v", err)
+	}
+}
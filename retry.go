@@ -0,0 +1,137 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package errors
+
+import (
+	"context"
+	"math/rand/v2"
+	"strconv"
+	"time"
+)
+
+// RetryPolicy 定义了 Retry 的重试策略
+type RetryPolicy struct {
+	MaxAttempts    int           // 最大尝试次数（包含第一次），<= 0 时使用 3
+	InitialBackoff time.Duration // 第一次重试前的等待时间，<= 0 时使用 100ms
+	MaxBackoff     time.Duration // 单次等待时间上限，<= 0 时使用 10s
+	Multiplier     float64       // 每次重试等待时间的增长倍数，<= 1 时使用 2
+	Jitter         float64       // 随机抖动比例（0-1），等待时间在 [d*(1-Jitter), d] 之间浮动
+	// RequireIdempotentSafe 为 true 时只重试 IsIdempotentSafe 的错误，用于支付等非幂等操作
+	RequireIdempotentSafe bool
+	// Budget 不为 nil 时使用该重试预算判断是否重试，见 RetryBudget
+	Budget *RetryBudget
+}
+
+// DefaultRetryPolicy 返回默认的重试策略
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts:    3,
+		InitialBackoff: 100 * time.Millisecond,
+		MaxBackoff:     10 * time.Second,
+		Multiplier:     2,
+		Jitter:         0.2,
+	}
+}
+
+// Retry 执行 fn，并在返回可重试错误时按指数退避重试
+// 错误会先经过 Translate 分类，只有 IsRetryable 为 true 时才会重试，
+// 设置了 RequireIdempotentSafe 时还要求 IsIdempotentSafe 为 true，设置了 Budget 时还要求重试预算没有耗尽；
+// 错误实现了 RetryAfter() time.Duration 时优先使用该等待时间，但不超过 MaxBackoff；
+// 等待时间超过 ctx 的剩余时间时不再等待，直接返回最后一次的错误.
+// 最终失败时返回的 StatusError 在 Extra 中记录 retry_attempts.
+func Retry(ctx context.Context, policy RetryPolicy, fn func() error) error {
+	policy = policy.normalize()
+
+	var lastErr StatusError
+	attempt := 0
+	for attempt < policy.MaxAttempts {
+		if attempt > 0 {
+			wait := policy.backoff(attempt)
+			if hint := BackoffHint(lastErr); hint > 0 {
+				wait = min(hint, policy.MaxBackoff)
+			}
+			if deadline, ok := ctx.Deadline(); ok && wait > time.Until(deadline) {
+				return With(lastErr, Extra("retry_attempts", strconv.Itoa(attempt)))
+			}
+
+			timer := time.NewTimer(wait)
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return With(lastErr, Extra("retry_attempts", strconv.Itoa(attempt)))
+			case <-timer.C:
+			}
+		} else if ctx.Err() != nil {
+			return FromContextErr(ctx, nil)
+		}
+
+		attempt++
+		policy.Budget.RecordRequest()
+		err := fn()
+		if err == nil {
+			return nil
+		}
+
+		lastErr = Translate(err)
+		// 最后一次尝试失败后不再消耗重试预算
+		if attempt >= policy.MaxAttempts || (policy.RequireIdempotentSafe && !IsIdempotentSafe(lastErr)) ||
+			!policy.Budget.IsRetryable(lastErr) {
+			break
+		}
+	}
+
+	return With(lastErr, Extra("retry_attempts", strconv.Itoa(attempt)))
+}
+
+// normalize 为未设置的字段填充默认值
+func (p RetryPolicy) normalize() RetryPolicy {
+	def := DefaultRetryPolicy()
+	if p.MaxAttempts <= 0 {
+		p.MaxAttempts = def.MaxAttempts
+	}
+	if p.InitialBackoff <= 0 {
+		p.InitialBackoff = def.InitialBackoff
+	}
+	if p.MaxBackoff <= 0 {
+		p.MaxBackoff = def.MaxBackoff
+	}
+	if p.Multiplier <= 1 {
+		p.Multiplier = def.Multiplier
+	}
+	if p.Jitter < 0 {
+		p.Jitter = 0
+	} else if p.Jitter > 1 {
+		p.Jitter = 1
+	}
+	return p
+}
+
+// backoff 计算第 attempt 次重试前的等待时间
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	d := float64(p.InitialBackoff)
+	for i := 1; i < attempt; i++ {
+		d *= p.Multiplier
+		if d >= float64(p.MaxBackoff) {
+			d = float64(p.MaxBackoff)
+			break
+		}
+	}
+	if p.Jitter > 0 {
+		d -= d * p.Jitter * rand.Float64()
+	}
+	return time.Duration(d)
+}
@@ -0,0 +1,120 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package errors
+
+import (
+	"sync"
+	"time"
+)
+
+// retryBudgetBuckets 是 RetryBudget 统计窗口划分的桶数
+const retryBudgetBuckets = 10
+
+// RetryBudget 是重试预算，限制一个时间窗口内重试次数占请求次数的比例
+// 依赖故障时几乎所有请求都会返回可重试的错误，逐个请求独立重试会使下游压力成倍增加（重试风暴），
+// 预算耗尽后 IsRetryable 对可重试的错误也返回 false. 可以在多个 goroutine 中共享同一个 RetryBudget.
+//
+//	budget := errors.NewRetryBudget(0.1, 10, 10*time.Second)
+//	policy := errors.DefaultRetryPolicy()
+//	policy.Budget = budget
+type RetryBudget struct {
+	ratio      float64
+	minRetries int
+	bucketSize time.Duration
+
+	mu      sync.Mutex
+	buckets [retryBudgetBuckets]retryBudgetBucket
+}
+
+// retryBudgetBucket 记录一个桶内的请求与重试次数
+type retryBudgetBucket struct {
+	slot     int64
+	requests int
+	retries  int
+}
+
+// NewRetryBudget 创建重试预算：window 内的重试次数不超过请求次数的 ratio 倍加上 minRetries
+// minRetries 保证请求量较少时仍然可以重试. window <= 0 时使用 10s.
+func NewRetryBudget(ratio float64, minRetries int, window time.Duration) *RetryBudget {
+	if ratio < 0 {
+		ratio = 0
+	}
+	if minRetries < 0 {
+		minRetries = 0
+	}
+	if window <= 0 {
+		window = 10 * time.Second
+	}
+	bucketSize := window / retryBudgetBuckets
+	if bucketSize <= 0 {
+		bucketSize = 1
+	}
+	return &RetryBudget{ratio: ratio, minRetries: minRetries, bucketSize: bucketSize}
+}
+
+// RecordRequest 记录一次请求（包括重试），成功的请求同样需要记录
+func (b *RetryBudget) RecordRequest() {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.bucket(now()).requests++
+}
+
+// IsRetryable 判断 err 是否可以重试，分类与包级别的 IsRetryable 一致
+// err 可以重试且预算没有耗尽时消耗一次重试预算并返回 true. b 为 nil 时不限制重试次数.
+func (b *RetryBudget) IsRetryable(err error) bool {
+	if !IsRetryable(err) {
+		return false
+	}
+	if b == nil {
+		return true
+	}
+
+	t := now()
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	requests, retries := b.count(t)
+	if float64(retries) >= b.ratio*float64(requests)+float64(b.minRetries) {
+		return false
+	}
+	b.bucket(t).retries++
+	return true
+}
+
+// bucket 返回 t 所在的桶，桶已过期时重置
+func (b *RetryBudget) bucket(t time.Time) *retryBudgetBucket {
+	slot := t.UnixNano() / int64(b.bucketSize)
+	bk := &b.buckets[slot%retryBudgetBuckets]
+	if bk.slot != slot {
+		*bk = retryBudgetBucket{slot: slot}
+	}
+	return bk
+}
+
+// count 返回窗口内的请求与重试次数
+func (b *RetryBudget) count(t time.Time) (requests, retries int) {
+	slot := t.UnixNano() / int64(b.bucketSize)
+	for _, bk := range b.buckets {
+		if slot-bk.slot < retryBudgetBuckets {
+			requests += bk.requests
+			retries += bk.retries
+		}
+	}
+	return requests, retries
+}
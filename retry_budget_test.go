@@ -0,0 +1,73 @@
+package errors_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-anyway/framework-errors"
+)
+
+func TestRetryBudget(t *testing.T) {
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	current := start
+	defer errors.SetClock(func() time.Time { return current })()
+
+	budget := errors.NewRetryBudget(0.5, 1, 10*time.Second)
+	retryable := errors.NewWithStatus(errors.CodeDependencyUnavailable, "")
+
+	if budget.IsRetryable(errors.NewWithStatus(errors.CodeNotFound, "")) {
+		t.Error("不可重试的错误不应消耗预算")
+	}
+
+	for i := 0; i < 4; i++ {
+		budget.RecordRequest()
+	}
+	// 4 个请求：允许 0.5*4+1 = 3 次重试
+	for i := 0; i < 3; i++ {
+		if !budget.IsRetryable(retryable) {
+			t.Fatalf("第 %d 次重试应在预算内", i+1)
+		}
+	}
+	if budget.IsRetryable(retryable) {
+		t.Error("预算耗尽后不应重试")
+	}
+	if !errors.IsRetryable(retryable) {
+		t.Error("重试预算不应影响包级别的分类")
+	}
+
+	// 窗口滑动后恢复
+	current = start.Add(11 * time.Second)
+	if !budget.IsRetryable(retryable) {
+		t.Error("窗口过期后应恢复预算")
+	}
+
+	var unlimited *errors.RetryBudget
+	if !unlimited.IsRetryable(retryable) {
+		t.Error("nil 预算不应限制重试")
+	}
+}
+
+func TestRetryWithBudget(t *testing.T) {
+	policy := fastPolicy(5)
+	policy.Budget = errors.NewRetryBudget(0, 2, time.Minute)
+
+	calls := 0
+	_ = errors.Retry(context.Background(), policy, func() error {
+		calls++
+		return errors.NewWithStatus(errors.CodeDependencyUnavailable, "")
+	})
+	if calls != 3 {
+		t.Errorf("calls = %d, want 3", calls)
+	}
+
+	// 预算已经耗尽，不再重试
+	calls = 0
+	_ = errors.Retry(context.Background(), policy, func() error {
+		calls++
+		return errors.NewWithStatus(errors.CodeDependencyUnavailable, "")
+	})
+	if calls != 1 {
+		t.Errorf("calls = %d, want 1", calls)
+	}
+}
@@ -0,0 +1,183 @@
+package errors_test
+
+import (
+	"context"
+	errstd "errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-anyway/framework-errors"
+)
+
+func fastPolicy(attempts int) errors.RetryPolicy {
+	return errors.RetryPolicy{
+		MaxAttempts:    attempts,
+		InitialBackoff: time.Millisecond,
+		MaxBackoff:     5 * time.Millisecond,
+	}
+}
+
+func TestRetrySucceeds(t *testing.T) {
+	calls := 0
+	err := errors.Retry(context.Background(), fastPolicy(3), func() error {
+		calls++
+		if calls < 3 {
+			return errors.NewWithStatus(errors.CodeDependencyUnavailable, "")
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Retry() error = %v", err)
+	}
+	if calls != 3 {
+		t.Errorf("calls = %d, want 3", calls)
+	}
+}
+
+func TestRetryNonRetryable(t *testing.T) {
+	calls := 0
+	err := errors.Retry(context.Background(), fastPolicy(5), func() error {
+		calls++
+		return errors.NewWithStatus(errors.CodeInvalidParam, "")
+	})
+	if calls != 1 {
+		t.Errorf("calls = %d, want 1", calls)
+	}
+
+	var se errors.StatusError
+	if !errstd.As(err, &se) {
+		t.Fatalf("error = %v, want StatusError", err)
+	}
+	if se.Code() != errors.CodeInvalidParam {
+		t.Errorf("Code() = %d, want %d", se.Code(), errors.CodeInvalidParam)
+	}
+	if se.Extra()["retry_attempts"] != "1" {
+		t.Errorf("Extra[retry_attempts] = %s, want 1", se.Extra()["retry_attempts"])
+	}
+}
+
+func TestRetryExhausted(t *testing.T) {
+	calls := 0
+	err := errors.Retry(context.Background(), fastPolicy(3), func() error {
+		calls++
+		return context.DeadlineExceeded
+	})
+	if calls != 3 {
+		t.Errorf("calls = %d, want 3", calls)
+	}
+
+	var se errors.StatusError
+	if !errstd.As(err, &se) {
+		t.Fatalf("error = %v, want StatusError", err)
+	}
+	if se.Code() != errors.CodeRequestTimeout {
+		t.Errorf("Code() = %d, want %d", se.Code(), errors.CodeRequestTimeout)
+	}
+	if se.Extra()["retry_attempts"] != "3" {
+		t.Errorf("Extra[retry_attempts] = %s, want 3", se.Extra()["retry_attempts"])
+	}
+	if !errstd.Is(err, context.DeadlineExceeded) {
+		t.Error("最终错误应保留原始 cause")
+	}
+}
+
+func TestRetryKeepsStack(t *testing.T) {
+	err := errors.Retry(context.Background(), fastPolicy(2), queryInventory)
+
+	frames := errors.StackFrames(err)
+	if len(frames) == 0 || !strings.HasSuffix(frames[0].Func, ".queryInventory") {
+		t.Errorf("StackFrames() = %v, 应保留失败调用的堆栈", frames)
+	}
+	if got := errors.Translate(err).Extra()["retry_attempts"]; got != "2" {
+		t.Errorf("Extra[retry_attempts] = %s, want 2", got)
+	}
+}
+
+func queryInventory() error {
+	return errors.NewWithStatus(errors.CodeDependencyUnavailable, "")
+}
+
+// retryAfterErr 是一个带有重试等待提示的错误
+type retryAfterErr struct {
+	errors.StatusError
+	after time.Duration
+}
+
+func (e retryAfterErr) RetryAfter() time.Duration { return e.after }
+func (e retryAfterErr) IsRetryable() bool         { return true }
+
+func TestRetryHonorsRetryAfter(t *testing.T) {
+	policy := fastPolicy(2)
+	policy.MaxBackoff = time.Second
+	start := time.Now()
+	calls := 0
+	_ = errors.Retry(context.Background(), policy, func() error {
+		calls++
+		return retryAfterErr{
+			StatusError: errors.NewWithStatus(errors.CodeRateLimitExceeded, ""),
+			after:       30 * time.Millisecond,
+		}
+	})
+	if elapsed := time.Since(start); elapsed < 30*time.Millisecond {
+		t.Errorf("elapsed = %v, want >= 30ms", elapsed)
+	}
+	if calls != 2 {
+		t.Errorf("calls = %d, want 2", calls)
+	}
+}
+
+func TestRetryClampsRetryAfter(t *testing.T) {
+	hinted := func(calls *int) func() error {
+		return func() error {
+			*calls++
+			return retryAfterErr{
+				StatusError: errors.NewWithStatus(errors.CodeRateLimitExceeded, ""),
+				after:       time.Hour,
+			}
+		}
+	}
+
+	// 等待时间不超过 MaxBackoff
+	start := time.Now()
+	calls := 0
+	_ = errors.Retry(context.Background(), fastPolicy(2), hinted(&calls))
+	if elapsed := time.Since(start); elapsed > time.Second || calls != 2 {
+		t.Errorf("elapsed = %v, calls = %d, 等待时间应被限制为 MaxBackoff", elapsed, calls)
+	}
+
+	// 等待时间超过 ctx 的剩余时间时直接返回
+	policy := fastPolicy(3)
+	policy.MaxBackoff = time.Minute
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	start = time.Now()
+	calls = 0
+	err := errors.Retry(ctx, policy, hinted(&calls))
+	if elapsed := time.Since(start); elapsed > time.Second || calls != 1 {
+		t.Errorf("elapsed = %v, calls = %d, 超过截止时间的等待应直接返回", elapsed, calls)
+	}
+	var se errors.StatusError
+	if !errstd.As(err, &se) || se.Code() != errors.CodeRateLimitExceeded || se.Extra()["retry_attempts"] != "1" {
+		t.Errorf("error = %v, want CodeRateLimitExceeded with retry_attempts", err)
+	}
+}
+
+func TestRetryContextCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	calls := 0
+	err := errors.Retry(ctx, fastPolicy(3), func() error {
+		calls++
+		return nil
+	})
+	if calls != 0 {
+		t.Errorf("calls = %d, want 0", calls)
+	}
+
+	var se errors.StatusError
+	if !errstd.As(err, &se) || se.Code() != errors.CodeClientCanceled {
+		t.Errorf("error = %v, want CodeClientCanceled", err)
+	}
+}
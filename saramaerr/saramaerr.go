@@ -0,0 +1,75 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+// Package saramaerr 将 StatusError 编码到 Kafka 消息头中，用于死信队列（DLQ）的排查.
+package saramaerr
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/go-anyway/framework-errors"
+
+	"github.com/IBM/sarama"
+)
+
+// EncodeToHeaders 将错误编码为 Kafka 消息头，通常在把失败的消息投递到死信队列时使用
+// 消息头的格式与 errors.EncodeHeaders 一致（X-Error-Code、X-Error-Msg、X-Error-Fingerprint 等），
+// 重试次数取自 Extra["retry_attempts"]，与 errors.Retry 的最终错误保持一致.
+//
+//	msg.Headers = append(msg.Headers, saramaerr.EncodeToHeaders(err)...)
+func EncodeToHeaders(err error) []sarama.RecordHeader {
+	encoded := errors.EncodeHeaders(err)
+	if encoded == nil {
+		return nil
+	}
+
+	keys := make([]string, 0, len(encoded))
+	for k := range encoded {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	headers := make([]sarama.RecordHeader, 0, len(keys))
+	for _, k := range keys {
+		headers = append(headers, sarama.RecordHeader{Key: []byte(k), Value: []byte(encoded[k])})
+	}
+	return headers
+}
+
+// DecodeFromHeaders 从 Kafka 消息头中还原 StatusError，消息头中没有错误码时返回 nil
+// 指纹和重试次数分别记录在 Extra["fingerprint"] 与 Extra["retry_attempts"] 中.
+func DecodeFromHeaders(headers []*sarama.RecordHeader) errors.StatusError {
+	values := make(map[string]string, len(headers))
+	for _, h := range headers {
+		if h != nil {
+			values[string(h.Key)] = string(h.Value)
+		}
+	}
+	return errors.DecodeHeaders(values)
+}
+
+// RetryCount 返回消息头中记录的重试次数，不存在时返回 0
+func RetryCount(headers []*sarama.RecordHeader) int {
+	for _, h := range headers {
+		if h != nil && strings.EqualFold(string(h.Key), errors.HeaderErrorRetryCount) {
+			n, _ := strconv.Atoi(string(h.Value))
+			return n
+		}
+	}
+	return 0
+}
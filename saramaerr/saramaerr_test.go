@@ -0,0 +1,57 @@
+package saramaerr_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-anyway/framework-errors"
+	"github.com/go-anyway/framework-errors/saramaerr"
+
+	"github.com/IBM/sarama"
+)
+
+func toPointers(headers []sarama.RecordHeader) []*sarama.RecordHeader {
+	out := make([]*sarama.RecordHeader, len(headers))
+	for i := range headers {
+		out[i] = &headers[i]
+	}
+	return out
+}
+
+func TestEncodeDecodeHeaders(t *testing.T) {
+	err := errors.Retry(context.Background(), errors.RetryPolicy{MaxAttempts: 2, InitialBackoff: time.Millisecond}, func() error {
+		return errors.NewWithStatus(errors.CodeDependencyUnavailable, "下游不可用")
+	})
+
+	headers := saramaerr.EncodeToHeaders(err)
+	se := saramaerr.DecodeFromHeaders(toPointers(headers))
+	if se == nil {
+		t.Fatal("DecodeFromHeaders() = nil, want StatusError")
+	}
+	if se.Code() != errors.CodeDependencyUnavailable {
+		t.Errorf("Code() = %d, want %d", se.Code(), errors.CodeDependencyUnavailable)
+	}
+	if se.Msg() != "下游不可用" {
+		t.Errorf("Msg() = %s, want 下游不可用", se.Msg())
+	}
+	if se.Extra()["fingerprint"] != errors.Fingerprint(err) {
+		t.Errorf("Extra[fingerprint] = %s, want %s", se.Extra()["fingerprint"], errors.Fingerprint(err))
+	}
+	if !errors.IsRetryable(se) {
+		t.Error("IsRetryable() = false, want true")
+	}
+	if n := saramaerr.RetryCount(toPointers(headers)); n != 2 {
+		t.Errorf("RetryCount() = %d, want 2", n)
+	}
+}
+
+func TestDecodeWithoutCode(t *testing.T) {
+	headers := []*sarama.RecordHeader{{Key: []byte("trace-id"), Value: []byte("abc")}}
+	if se := saramaerr.DecodeFromHeaders(headers); se != nil {
+		t.Errorf("DecodeFromHeaders() = %v, want nil", se)
+	}
+	if saramaerr.EncodeToHeaders(nil) != nil {
+		t.Error("EncodeToHeaders(nil) 应返回 nil")
+	}
+}
@@ -0,0 +1,113 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package errors
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// Scrubber 对错误消息和扩展信息做脱敏处理
+// key 为扩展信息的 key，处理错误消息时 key 为空字符串. 返回处理后的值以及是否保留，
+// 返回 false 时扩展信息会被丢弃，错误消息会被替换为错误码的默认消息.
+type Scrubber func(key, value string) (string, bool)
+
+var (
+	scrubbersMu sync.Mutex
+	// scrubbers 只在注册时整体替换，读取时无需加锁
+	scrubbers atomic.Pointer[[]Scrubber]
+)
+
+// RegisterScrubber 注册 Scrubber，错误在序列化到 gRPC details、HTTP 响应体、消息头以及记录日志前
+// 会依次经过所有 Scrubber 处理，可用于接入基于正则或模型的 PII 检测（邮箱、手机号、证件号等）.
+//
+//	errors.RegisterScrubber(func(key, value string) (string, bool) {
+//		return emailPattern.ReplaceAllString(value, "***"), true
+//	})
+func RegisterScrubber(s Scrubber) {
+	if s == nil {
+		return
+	}
+	scrubbersMu.Lock()
+	defer scrubbersMu.Unlock()
+	var list []Scrubber
+	if old := scrubbers.Load(); old != nil {
+		list = append(list, *old...)
+	}
+	list = append(list, s)
+	scrubbers.Store(&list)
+	// 缓存的 status 中包含未经新 Scrubber 处理的消息
+	grpcStatusCache.Clear()
+}
+
+// scrub 依次使用所有 Scrubber 处理 value
+func scrub(key, value string) (string, bool) {
+	if keys := redactKeys.Load(); keys != nil && key != "" && (*keys)[key] {
+		return RedactedValue, true
+	}
+	list := scrubbers.Load()
+	if list == nil {
+		return value, true
+	}
+	for _, s := range *list {
+		var keep bool
+		if value, keep = s(key, value); !keep {
+			return "", false
+		}
+	}
+	return value, true
+}
+
+// ScrubbedMsg 返回经过 Scrubber 处理的错误消息，序列化错误消息时应使用此函数而不是 Msg()
+func ScrubbedMsg(err StatusError) string {
+	if err == nil {
+		return ""
+	}
+	msg, keep := scrub("", err.Msg())
+	if !keep {
+		return GetMessage(err.Code(), "")
+	}
+	return msg
+}
+
+// scrubbedError 返回经过 Scrubber 处理的 err.Error()，用于错误链中的各层错误
+// 消息被丢弃时 StatusError 使用错误码的默认消息，其他错误使用 RedactedValue.
+func scrubbedError(err error) string {
+	msg, keep := scrub("", err.Error())
+	if keep {
+		return msg
+	}
+	if se, ok := err.(StatusError); ok {
+		return GetMessage(se.Code(), "")
+	}
+	return RedactedValue
+}
+
+// rangeScrubbedExtra 依次对经过 Scrubber 处理的扩展信息调用 fn，被丢弃的扩展信息会被跳过
+func rangeScrubbedExtra(rangeFn func(fn func(k, v string) bool), fn func(k, v string) bool) {
+	if scrubbers.Load() == nil && redactKeys.Load() == nil {
+		rangeFn(fn)
+		return
+	}
+	rangeFn(func(k, v string) bool {
+		v, keep := scrub(k, v)
+		if !keep {
+			return true
+		}
+		return fn(k, v)
+	})
+}
@@ -0,0 +1,83 @@
+package errors_test
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/go-anyway/framework-errors"
+)
+
+var emailPattern = regexp.MustCompile(`[\w.+-]+@[\w-]+\.[\w.]+`)
+
+func TestRegisterScrubber(t *testing.T) {
+	defer errors.ResetScrubbers()
+	errors.RegisterScrubber(func(key, value string) (string, bool) {
+		return emailPattern.ReplaceAllString(value, "***"), true
+	})
+	errors.RegisterScrubber(func(key, value string) (string, bool) {
+		return value, key != "id_card"
+	})
+
+	err := errors.NewWithStatus(errors.CodeInvalidParam, "邮箱 alice@example.com 已被占用",
+		errors.Extra("email", "alice@example.com"),
+		errors.Extra("id_card", "110101199001011234"),
+		errors.Extra("order_id", "42"),
+	)
+
+	tests := []struct {
+		name string
+		msg  func(se errors.StatusError) string
+		ext  func(se errors.StatusError) map[string]string
+	}{
+		{
+			name: "gRPC",
+			msg:  func(se errors.StatusError) string { return errors.FromGRPCStatus(errors.ToGRPCStatus(se)).Msg() },
+			ext: func(se errors.StatusError) map[string]string {
+				return errors.FromGRPCStatus(errors.ToGRPCStatus(se)).Extra()
+			},
+		},
+		{
+			name: "HTTP 响应体",
+			msg:  func(se errors.StatusError) string { return errors.NewEnvelope(se).Message },
+			ext:  func(se errors.StatusError) map[string]string { return errors.NewEnvelope(se).Extra },
+		},
+		{
+			name: "消息头",
+			msg:  func(se errors.StatusError) string { return errors.DecodeHeaders(errors.EncodeHeaders(se)).Msg() },
+			ext: func(se errors.StatusError) map[string]string {
+				return errors.DecodeHeaders(errors.EncodeHeaders(se)).Extra()
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.msg(err); got != "邮箱 *** 已被占用" {
+				t.Errorf("Msg() = %q, want %q", got, "邮箱 *** 已被占用")
+			}
+			extra := tt.ext(err)
+			if extra["email"] != "***" || extra["order_id"] != "42" {
+				t.Errorf("Extra = %v, email 应被脱敏", extra)
+			}
+			if _, ok := extra["id_card"]; ok {
+				t.Errorf("Extra = %v, 不应包含 id_card", extra)
+			}
+		})
+	}
+
+	// 错误本身不受影响
+	if err.Extra()["email"] != "alice@example.com" {
+		t.Error("Scrubber 不应修改错误本身")
+	}
+}
+
+func TestRegisterScrubberDropMessage(t *testing.T) {
+	defer errors.ResetScrubbers()
+	errors.RegisterScrubber(func(key, value string) (string, bool) {
+		return value, key != ""
+	})
+
+	err := errors.NewStatusError(errors.CodeNotFound, "用户 13800000000 不存在", nil)
+	if got, want := errors.ScrubbedMsg(err), errors.GetMessage(errors.CodeNotFound, ""); got != want {
+		t.Errorf("ScrubbedMsg() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,164 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package errors
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+)
+
+// ServerConfig 是 GRPCServerOptions 与 HTTPMiddlewares 的配置
+type ServerConfig struct {
+	// AuthorizeDebug 判断 gRPC 请求能否开启调试模式，为 nil 时始终不开启，见 UnaryServerContextInterceptor
+	AuthorizeDebug func(ctx context.Context) bool
+	// AuthorizeDebugHTTP 判断 HTTP 请求能否开启调试模式，为 nil 时始终不开启，见 DebugMiddleware
+	AuthorizeDebugHTTP func(r *http.Request) bool
+	// LogOptions 是 gRPC 拦截器记录错误日志时使用的选项
+	LogOptions []LogOption
+	// FaultInjection 不为 nil 时在最内层注入错误，只应在非生产环境设置，见 FaultInjection
+	FaultInjection *FaultInjection
+}
+
+// GRPCServerOptions 返回按正确顺序串联本包全部服务端拦截器的 gRPC ServerOption
+// 由外到内依次为：还原语言、调试模式与错误上下文，本地化错误消息，
+// 捕获 panic、记录日志与指标并将错误转换为 gRPC status，设置了 FaultInjection 时最内层为错误注入.
+//
+//	srv := grpc.NewServer(errors.GRPCServerOptions(errors.ServerConfig{})...)
+func GRPCServerOptions(cfg ServerConfig) []grpc.ServerOption {
+	unary := []grpc.UnaryServerInterceptor{
+		UnaryServerContextInterceptor(cfg.AuthorizeDebug),
+		UnaryServerLocaleInterceptor(),
+		UnaryServerErrorInterceptor(cfg.LogOptions...),
+	}
+	stream := []grpc.StreamServerInterceptor{
+		StreamServerContextInterceptor(cfg.AuthorizeDebug),
+		StreamServerLocaleInterceptor(),
+		StreamServerErrorInterceptor(cfg.LogOptions...),
+	}
+	if cfg.FaultInjection != nil {
+		unary = append(unary, UnaryServerFaultInterceptor(cfg.FaultInjection))
+		stream = append(stream, StreamServerFaultInterceptor(cfg.FaultInjection))
+	}
+	return []grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(unary...),
+		grpc.ChainStreamInterceptor(stream...),
+	}
+}
+
+// HTTPMiddlewares 返回按正确顺序排列的本包全部 HTTP 中间件，第一个位于最外层
+// 依次为：解析调用方语言、开启调试模式、记录耗时指标、捕获 panic，设置了 FaultInjection 时最后为错误注入.
+// handler 返回的错误由 Handler 或 WriteError 记录日志并渲染.
+//
+//	r.Use(errors.HTTPMiddlewares(errors.ServerConfig{})...)
+func HTTPMiddlewares(cfg ServerConfig) []func(http.Handler) http.Handler {
+	middlewares := []func(http.Handler) http.Handler{
+		LocaleMiddleware,
+		DebugMiddleware(cfg.AuthorizeDebugHTTP),
+		MetricsMiddleware,
+		Middleware,
+	}
+	if cfg.FaultInjection != nil {
+		middlewares = append(middlewares, FaultInjectionMiddleware(cfg.FaultInjection))
+	}
+	return middlewares
+}
+
+// UnaryServerErrorInterceptor 返回处理 handler 返回的错误的 gRPC 一元拦截器
+// 捕获 panic 并转换为 CodeInternalError，错误经过 LogAndReturnError 记录日志与指标后转换为 gRPC status，
+// 同时将请求的耗时记录到 MetricsSink. 已经是 gRPC status 的错误原样返回.
+func UnaryServerErrorInterceptor(opts ...LogOption) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp interface{}, err error) {
+		start := time.Now()
+		defer func() {
+			if rec := recover(); rec != nil {
+				resp, err = nil, panicError(rec)
+			}
+			err = serverError(ctx, err, time.Since(start), opts)
+		}()
+		return handler(ctx, req)
+	}
+}
+
+// StreamServerErrorInterceptor 返回处理 handler 返回的错误的 gRPC 流拦截器
+// 行为与 UnaryServerErrorInterceptor 相同.
+func StreamServerErrorInterceptor(opts ...LogOption) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
+		start := time.Now()
+		defer func() {
+			if rec := recover(); rec != nil {
+				err = panicError(rec)
+			}
+			err = serverError(ss.Context(), err, time.Since(start), opts)
+		}()
+		return handler(srv, ss)
+	}
+}
+
+// serverError 记录耗时并将 handler 返回的错误转换为 gRPC error
+func serverError(ctx context.Context, err error, latency time.Duration, opts []LogOption) error {
+	if err == nil {
+		observeLatency(CodeSuccess, latency)
+		return nil
+	}
+	if _, ok := err.(interface{ GRPCStatus() *status.Status }); ok {
+		if st, ok := status.FromError(err); ok {
+			observeLatency(CodeFromGRPC(st.Code()), latency)
+			return err
+		}
+	}
+	se := Translate(err)
+	observeLatency(se.Code(), latency)
+	return LogAndReturnError(ctx, se, opts...)
+}
+
+// panicError 将 recover 得到的值转换为带堆栈的 CodeInternalError
+func panicError(rec interface{}) StatusError {
+	cause, ok := rec.(error)
+	if !ok {
+		cause = fmt.Errorf("%v", rec)
+	}
+	return WrapWithStatusOptions(cause, CodeInternalError, "", Extra("panic", "true"), OverrideCode())
+}
+
+type observedCodeKey struct{}
+
+// MetricsMiddleware 是将请求耗时记录到 MetricsSink 的 HTTP 中间件
+// 错误码为 WriteError 渲染的错误码，没有渲染错误的请求记为 CodeSuccess.
+func MetricsMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if metricsSink.Load() == nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+		start := time.Now()
+		code := CodeSuccess
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), observedCodeKey{}, &code)))
+		observeLatency(code, time.Since(start))
+	})
+}
+
+// setObservedCode 记录 MetricsMiddleware 统计耗时使用的错误码
+func setObservedCode(ctx context.Context, code int32) {
+	if p, ok := ctx.Value(observedCodeKey{}).(*int32); ok {
+		*p = code
+	}
+}
@@ -0,0 +1,132 @@
+package errors_test
+
+import (
+	"context"
+	errstd "errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/go-anyway/framework-errors"
+)
+
+func TestUnaryServerErrorInterceptor(t *testing.T) {
+	sink := newFakeSink()
+	errors.SetMetricsSink(sink)
+	defer errors.SetMetricsSink(nil)
+
+	interceptor := errors.UnaryServerErrorInterceptor(errors.SkipLog())
+	tests := []struct {
+		name     string
+		handler  grpc.UnaryHandler
+		wantCode codes.Code
+		wantBiz  int32
+	}{
+		{name: "成功", handler: func(ctx context.Context, req interface{}) (interface{}, error) {
+			return "ok", nil
+		}, wantCode: codes.OK, wantBiz: errors.CodeSuccess},
+		{name: "StatusError", handler: func(ctx context.Context, req interface{}) (interface{}, error) {
+			return nil, errors.NewWithStatus(errors.CodeNotFound, "")
+		}, wantCode: codes.NotFound, wantBiz: errors.CodeNotFound},
+		{name: "普通错误", handler: func(ctx context.Context, req interface{}) (interface{}, error) {
+			return nil, errstd.New("boom")
+		}, wantCode: codes.Internal, wantBiz: errors.CodeInternalError},
+		{name: "gRPC error 原样返回", handler: func(ctx context.Context, req interface{}) (interface{}, error) {
+			return nil, status.Error(codes.Unauthenticated, "no token")
+		}, wantCode: codes.Unauthenticated, wantBiz: errors.CodeUnauthorized},
+		{name: "panic", handler: func(ctx context.Context, req interface{}) (interface{}, error) {
+			panic("boom")
+		}, wantCode: codes.Internal, wantBiz: errors.CodeInternalError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := interceptor(context.Background(), nil, &grpc.UnaryServerInfo{}, tt.handler)
+			if got := status.Code(err); got != tt.wantCode {
+				t.Errorf("status.Code() = %v, want %v", got, tt.wantCode)
+			}
+			if err != nil {
+				if se := errors.FromGRPCStatus(status.Convert(err)); se.Code() != tt.wantBiz {
+					t.Errorf("FromGRPCStatus().Code() = %d, want %d", se.Code(), tt.wantBiz)
+				}
+			}
+			if _, ok := sink.latencies[tt.wantBiz]; !ok {
+				t.Errorf("latencies = %v, 应记录 %d 的耗时", sink.latencies, tt.wantBiz)
+			}
+		})
+	}
+}
+
+func TestGRPCServerOptions(t *testing.T) {
+	if opts := errors.GRPCServerOptions(errors.ServerConfig{}); len(opts) != 2 {
+		t.Fatalf("len(GRPCServerOptions()) = %d, want 2", len(opts))
+	}
+	// 确认可以用于创建 server
+	grpc.NewServer(errors.GRPCServerOptions(errors.ServerConfig{})...).Stop()
+}
+
+func TestStreamServerErrorInterceptor(t *testing.T) {
+	interceptor := errors.StreamServerErrorInterceptor(errors.SkipLog())
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.MD{})
+	err := interceptor(nil, &fakeServerStream{ctx: ctx}, &grpc.StreamServerInfo{}, func(srv interface{}, ss grpc.ServerStream) error {
+		panic(errstd.New("boom"))
+	})
+	if got := status.Code(err); got != codes.Internal {
+		t.Errorf("status.Code() = %v, want %v", got, codes.Internal)
+	}
+	if se := errors.FromGRPCStatus(status.Convert(err)); se.Extra()["panic"] != "true" {
+		t.Errorf("Extra() = %v, 应包含 panic=true", se.Extra())
+	}
+}
+
+// fakeServerStream 只提供 context 的 ServerStream
+type fakeServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *fakeServerStream) Context() context.Context {
+	return s.ctx
+}
+
+func TestHTTPMiddlewares(t *testing.T) {
+	sink := newFakeSink()
+	errors.SetMetricsSink(sink)
+	defer errors.SetMetricsSink(nil)
+
+	var h http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/panic" {
+			panic("boom")
+		}
+		errors.WriteError(w, r, errors.NewWithStatus(errors.CodeNotFound, ""))
+	})
+	mws := errors.HTTPMiddlewares(errors.ServerConfig{})
+	for i := len(mws) - 1; i >= 0; i-- {
+		h = mws[i](h)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Accept-Language", "en")
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	if rec.Code != http.StatusNotFound || rec.Header().Get("Content-Language") != "en" {
+		t.Errorf("响应 = %d %q", rec.Code, rec.Header().Get("Content-Language"))
+	}
+
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/panic", nil))
+	if rec.Code != http.StatusInternalServerError {
+		t.Errorf("panic 响应 = %d, want %d", rec.Code, http.StatusInternalServerError)
+	}
+
+	if _, ok := sink.latencies[errors.CodeNotFound]; !ok {
+		t.Errorf("latencies = %v, 应记录 %d 的耗时", sink.latencies, errors.CodeNotFound)
+	}
+	if _, ok := sink.latencies[errors.CodeInternalError]; !ok {
+		t.Errorf("latencies = %v, 应记录 %d 的耗时", sink.latencies, errors.CodeInternalError)
+	}
+}
@@ -0,0 +1,116 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package errors
+
+import "errors"
+
+// SLOImpact 是错误对服务等级目标（SLO）的影响程度，用于计算错误预算的消耗
+type SLOImpact int32
+
+const (
+	// SLOImpactNone 表示不影响 SLO，例如参数错误、资源不存在
+	SLOImpactNone SLOImpact = iota
+	// SLOImpactDegraded 表示服务降级但请求仍然得到了可接受的结果，例如熔断后返回兜底数据
+	SLOImpactDegraded
+	// SLOImpactBudgetBurning 表示请求失败并消耗错误预算
+	SLOImpactBudgetBurning
+	// SLOImpactCritical 表示严重故障，应立即告警
+	SLOImpactCritical
+)
+
+// SLOImpactKey 是 SLO 影响程度在扩展信息中的 key
+// 只有通过 Impact 覆盖了错误码定义时才会出现，随扩展信息传递给调用方.
+const SLOImpactKey = "slo_impact"
+
+// sloImpactNames 是各影响程度的名称，用作指标的标签
+var sloImpactNames = [...]string{
+	SLOImpactNone:          "none",
+	SLOImpactDegraded:      "degraded",
+	SLOImpactBudgetBurning: "budget_burning",
+	SLOImpactCritical:      "critical",
+}
+
+// String 返回影响程度的名称，例如 "budget_burning"
+func (i SLOImpact) String() string {
+	if i < 0 || int(i) >= len(sloImpactNames) {
+		return sloImpactNames[SLOImpactNone]
+	}
+	return sloImpactNames[i]
+}
+
+// ParseSLOImpact 解析影响程度的名称，无法识别时第二个返回值为 false
+func ParseSLOImpact(s string) (SLOImpact, bool) {
+	for i, name := range sloImpactNames {
+		if name == s {
+			return SLOImpact(i), true
+		}
+	}
+	return SLOImpactNone, false
+}
+
+// Impact 用于覆盖错误码定义中的 SLO 影响程度，见 CodeDefinition.SLOImpact
+// 例如同一个依赖错误在有兜底数据时只是降级：
+//
+//	errors.WrapWithStatusOptions(err, errors.CodeDependencyUnavailable, "", errors.Impact(errors.SLOImpactDegraded))
+func Impact(impact SLOImpact) Option {
+	return Extra(SLOImpactKey, impact.String())
+}
+
+// SLOImpact 返回错误对 SLO 的影响程度
+func (e *statusError) SLOImpact() SLOImpact {
+	if impact, ok := ParseSLOImpact(e.ext.Extra[SLOImpactKey]); ok {
+		return impact
+	}
+	if inMaintenance(e.statusCode) {
+		return SLOImpactNone
+	}
+	return codeSLOImpact(GetCodeDefinition(e.statusCode), e.ext.IsAffectStability)
+}
+
+// SLOImpact 返回错误对 SLO 的影响程度
+func (w *withStatus) SLOImpact() SLOImpact {
+	return w.status.SLOImpact()
+}
+
+// SLOImpactOf 返回错误对 SLO 的影响程度，err 为 nil 时返回 SLOImpactNone
+// 错误链中任意一个错误实现了 SLOImpact() SLOImpact 即以其结果为准；
+// 否则错误码定义了 SLOImpact 时使用定义，影响稳定性的错误视为 SLOImpactBudgetBurning.
+func SLOImpactOf(err error) SLOImpact {
+	if err == nil {
+		return SLOImpactNone
+	}
+	var r interface{ SLOImpact() SLOImpact }
+	if errors.As(err, &r) {
+		return r.SLOImpact()
+	}
+	var se StatusError
+	if errors.As(err, &se) {
+		return codeSLOImpact(GetCodeDefinition(se.Code()), se.IsAffectStability())
+	}
+	return SLOImpactNone
+}
+
+// codeSLOImpact 返回错误码定义的影响程度，没有定义时根据是否影响稳定性推断
+func codeSLOImpact(def CodeDefinition, affectStability bool) SLOImpact {
+	if def.HasSLOImpact || def.SLOImpact != SLOImpactNone {
+		return def.SLOImpact
+	}
+	if affectStability {
+		return SLOImpactBudgetBurning
+	}
+	return SLOImpactNone
+}
@@ -0,0 +1,97 @@
+package errors_test
+
+import (
+	"context"
+	errstd "errors"
+	"sync"
+	"testing"
+
+	"github.com/go-anyway/framework-errors"
+)
+
+func TestSLOImpactOf(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want errors.SLOImpact
+	}{
+		{name: "nil", err: nil, want: errors.SLOImpactNone},
+		{name: "不影响稳定性", err: errors.NewWithStatus(errors.CodeNotFound, ""), want: errors.SLOImpactNone},
+		{name: "影响稳定性未定义", err: errors.NewWithStatus(errors.CodeDependencyTimeout, ""), want: errors.SLOImpactBudgetBurning},
+		{name: "错误码定义", err: errors.NewWithStatus(errors.CodeInternalError, ""), want: errors.SLOImpactCritical},
+		{name: "熔断降级", err: errors.NewWithStatus(errors.CodeDependencyCircuitOpen, ""), want: errors.SLOImpactDegraded},
+		{
+			name: "单个错误覆盖",
+			err:  errors.NewWithStatus(errors.CodeDependencyUnavailable, "", errors.Impact(errors.SLOImpactDegraded)),
+			want: errors.SLOImpactDegraded,
+		},
+		{
+			name: "覆盖经过 gRPC 传递",
+			err: errors.FromGRPCStatus(errors.ToGRPCStatus(
+				errors.NewWithStatus(errors.CodeNotFound, "", errors.Impact(errors.SLOImpactCritical)))),
+			want: errors.SLOImpactCritical,
+		},
+		{name: "普通错误", err: errstd.New("boom"), want: errors.SLOImpactNone},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := errors.SLOImpactOf(tt.err); got != tt.want {
+				t.Errorf("SLOImpactOf() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSLOImpactExplicitNone(t *testing.T) {
+	const code int32 = 47101
+	errors.CodeDefinitions[code] = errors.CodeDefinition{
+		Message:           "缓存预热失败",
+		IsAffectStability: true,
+		SLOImpact:         errors.SLOImpactNone,
+		HasSLOImpact:      true,
+	}
+	defer delete(errors.CodeDefinitions, code)
+
+	if got := errors.SLOImpactOf(errors.NewWithStatus(code, "")); got != errors.SLOImpactNone {
+		t.Errorf("SLOImpactOf() = %v, want none", got)
+	}
+}
+
+func TestParseSLOImpact(t *testing.T) {
+	for _, impact := range []errors.SLOImpact{
+		errors.SLOImpactNone, errors.SLOImpactDegraded, errors.SLOImpactBudgetBurning, errors.SLOImpactCritical,
+	} {
+		if got, ok := errors.ParseSLOImpact(impact.String()); !ok || got != impact {
+			t.Errorf("ParseSLOImpact(%q) = %v, %v", impact.String(), got, ok)
+		}
+	}
+	if _, ok := errors.ParseSLOImpact("high"); ok {
+		t.Error(`ParseSLOImpact("high") 应返回 false`)
+	}
+}
+
+// fakeSLOSink 额外记录按 SLO 影响程度统计的错误
+type fakeSLOSink struct {
+	*fakeSink
+	mu      sync.Mutex
+	impacts map[int32]errors.SLOImpact
+}
+
+func (s *fakeSLOSink) IncrementSLOImpact(code int32, impact errors.SLOImpact) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.impacts[code] = impact
+}
+
+func TestSLOImpactMetrics(t *testing.T) {
+	sink := &fakeSLOSink{fakeSink: newFakeSink(), impacts: map[int32]errors.SLOImpact{}}
+	errors.SetMetricsSink(sink)
+	defer errors.SetMetricsSink(nil)
+
+	_ = errors.LogAndReturnError(context.Background(), errors.NewWithStatus(errors.CodeInternalError, ""), errors.SkipLog())
+	_ = errors.LogAndReturnError(context.Background(), errors.NewWithStatus(errors.CodeNotFound, ""), errors.SkipLog())
+
+	if len(sink.impacts) != 1 || sink.impacts[errors.CodeInternalError] != errors.SLOImpactCritical {
+		t.Errorf("impacts = %v", sink.impacts)
+	}
+}
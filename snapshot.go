@@ -0,0 +1,257 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package errors
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync/atomic"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// RequestSnapshotKey 是请求快照在内部扩展信息中的 key，见 AttachRequestSnapshot
+const RequestSnapshotKey = "request_snapshot"
+
+const (
+	// maxRequestSnapshotSize 是请求快照序列化后的最大字节数，超出的字段被丢弃
+	maxRequestSnapshotSize = 2 << 10
+	// maxRequestSnapshotField 是单个字段值的最大字节数，超出的部分被截断
+	maxRequestSnapshotField = 256
+	// maxRequestSnapshotDepth 是展开嵌套字段的最大层数，更深的字段被丢弃
+	maxRequestSnapshotDepth = 8
+)
+
+var requestSnapshots atomic.Bool
+
+// EnableRequestSnapshots 开启或关闭 AttachRequestSnapshot，默认关闭
+// 请求快照可能包含业务数据，只应在开发与测试环境开启，见 Config.RequestSnapshots.
+func EnableRequestSnapshots(enabled bool) {
+	requestSnapshots.Store(enabled)
+}
+
+// RequestSnapshot 是失败请求的快照
+type RequestSnapshot struct {
+	// Method 是请求的方法，proto 消息为消息的全名，HTTP 请求为 "GET /path"，其他类型为 Go 类型名
+	Method string `json:"method"`
+	// Fields 是请求的字段，已经过 RedactKeys 与 Scrubber 处理
+	// 嵌套的对象与数组被展开，key 是以 "." 分隔的路径，例如 "address.city"、"items.0.sku".
+	Fields map[string]string `json:"fields,omitempty"`
+	// Truncated 表示因大小限制丢弃或截断了部分字段
+	Truncated bool `json:"truncated,omitempty"`
+}
+
+// AttachRequestSnapshot 返回附加了失败请求快照的新错误，err 本身不会被修改
+// 快照只包含 fields 指定的字段及其嵌套字段（为空时包含全部字段），字段可以是以 "." 分隔的路径.
+// 嵌套的对象与数组被逐层展开，路径中任意一层的 key 命中 RedactKeys 时值被替换为 RedactedValue，
+// Scrubber 收到的 key 是路径中最近的字段名（数组元素使用数组的字段名），序列化后不超过 2KB. 快照记录在内部扩展信息中，不会跨服务传递，只出现在日志与调试模式的 DebugInfo.Request 中，
+// 可以直接从错误回答 "是什么输入导致了这个错误". 只有通过 EnableRequestSnapshots 开启后才会附加快照.
+// HTTP 请求只记录查询参数，不会读取请求体.
+//
+//	if err != nil {
+//		return errors.AttachRequestSnapshot(errors.Translate(err), req, "order_id", "sku")
+//	}
+func AttachRequestSnapshot(err StatusError, req any, fields ...string) StatusError {
+	if err == nil || req == nil || !requestSnapshots.Load() {
+		return err
+	}
+	data, marshalErr := json.Marshal(newRequestSnapshot(req, fields))
+	if marshalErr != nil {
+		return err
+	}
+	return With(err, InternalExtra(RequestSnapshotKey, string(data)))
+}
+
+// RequestSnapshotOf 返回 err 附加的请求快照，没有时第二个返回值为 false
+func RequestSnapshotOf(err error) (RequestSnapshot, bool) {
+	var snapshot RequestSnapshot
+	var se StatusError
+	if !errors.As(err, &se) {
+		return snapshot, false
+	}
+	data, ok := se.Extra()[RequestSnapshotKey]
+	if !ok || json.Unmarshal([]byte(data), &snapshot) != nil {
+		return snapshot, false
+	}
+	return snapshot, true
+}
+
+// newRequestSnapshot 提取 req 的方法与字段，并进行脱敏与大小限制
+func newRequestSnapshot(req any, fields []string) RequestSnapshot {
+	method, values, truncated := requestFields(req)
+	snapshot := RequestSnapshot{Method: truncateString(method, maxRequestSnapshotField), Truncated: truncated}
+
+	keys := make([]string, 0, len(values))
+	for k := range values {
+		if selectedField(k, fields) {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+
+	size := len(snapshot.Method)
+	for _, k := range keys {
+		v, ok := scrubPath(k, values[k])
+		if !ok {
+			continue
+		}
+		if len(v) > maxRequestSnapshotField {
+			v = truncateString(v, maxRequestSnapshotField)
+			snapshot.Truncated = true
+		}
+		// 粗略估计字段序列化后的大小，包括引号、冒号与逗号
+		if size += len(k) + len(v) + 6; size > maxRequestSnapshotSize {
+			snapshot.Truncated = true
+			break
+		}
+		if snapshot.Fields == nil {
+			snapshot.Fields = make(map[string]string)
+		}
+		snapshot.Fields[k] = v
+	}
+	return snapshot
+}
+
+// selectedField 判断路径为 k 的字段是否属于 fields 指定的字段，fields 为空时选择全部字段
+func selectedField(k string, fields []string) bool {
+	if len(fields) == 0 {
+		return true
+	}
+	for _, f := range fields {
+		if k == f || strings.HasPrefix(k, f+".") {
+			return true
+		}
+	}
+	return false
+}
+
+// scrubPath 对路径为 path 的字段值进行脱敏
+// 路径中任意一层命中 RedactKeys 时返回 RedactedValue，Scrubber 收到的 key 是路径中最近的非数组下标.
+func scrubPath(path, value string) (string, bool) {
+	segments := strings.Split(path, ".")
+	if keys := redactKeys.Load(); keys != nil {
+		for _, seg := range segments {
+			if (*keys)[seg] {
+				return RedactedValue, true
+			}
+		}
+	}
+	key := segments[0]
+	for i := len(segments) - 1; i >= 0; i-- {
+		if _, err := strconv.Atoi(segments[i]); err != nil {
+			key = segments[i]
+			break
+		}
+	}
+	return scrub(key, value)
+}
+
+// requestFields 返回请求的方法与展开后的字段，第三个返回值表示是否因层数限制丢弃了字段
+func requestFields(req any) (string, map[string]string, bool) {
+	switch r := req.(type) {
+	case *http.Request:
+		values := make(map[string]string)
+		for k, v := range r.URL.Query() {
+			values[k] = strings.Join(v, ",")
+		}
+		return r.Method + " " + r.URL.Path, values, false
+	case proto.Message:
+		name := string(r.ProtoReflect().Descriptor().FullName())
+		data, err := protojson.MarshalOptions{UseProtoNames: true}.Marshal(r)
+		if err != nil {
+			return name, nil, false
+		}
+		values, truncated := jsonFields(data)
+		return name, values, truncated
+	default:
+		data, err := json.Marshal(r)
+		if err != nil {
+			return fmt.Sprintf("%T", req), nil, false
+		}
+		values, truncated := jsonFields(data)
+		return fmt.Sprintf("%T", req), values, truncated
+	}
+}
+
+// jsonFields 将 JSON 展开为路径到标量值的映射，顶层不是对象时记录在 value 字段中
+func jsonFields(data []byte) (map[string]string, bool) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		return map[string]string{"value": string(data)}, false
+	}
+	values := make(map[string]string)
+	if obj, ok := v.(map[string]any); ok {
+		truncated := false
+		for k, child := range obj {
+			if !flattenJSON(k, child, 1, values) {
+				truncated = true
+			}
+		}
+		return values, truncated
+	}
+	return values, !flattenJSON("value", v, 1, values)
+}
+
+// flattenJSON 将 v 展开到 values 中，超过 maxRequestSnapshotDepth 的字段被丢弃并返回 false
+func flattenJSON(path string, v any, depth int, values map[string]string) bool {
+	switch v := v.(type) {
+	case map[string]any:
+		if len(v) == 0 {
+			values[path] = "{}"
+			return true
+		}
+		if depth >= maxRequestSnapshotDepth {
+			return false
+		}
+		complete := true
+		for k, child := range v {
+			complete = flattenJSON(path+"."+k, child, depth+1, values) && complete
+		}
+		return complete
+	case []any:
+		if len(v) == 0 {
+			values[path] = "[]"
+			return true
+		}
+		if depth >= maxRequestSnapshotDepth {
+			return false
+		}
+		complete := true
+		for i, child := range v {
+			complete = flattenJSON(path+"."+strconv.Itoa(i), child, depth+1, values) && complete
+		}
+		return complete
+	case string:
+		values[path] = v
+	case json.Number:
+		values[path] = v.String()
+	case bool:
+		values[path] = strconv.FormatBool(v)
+	case nil:
+		values[path] = "null"
+	}
+	return true
+}
@@ -0,0 +1,210 @@
+package errors_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+
+	"github.com/go-anyway/framework-errors"
+)
+
+type createOrderRequest struct {
+	OrderID  string `json:"order_id"`
+	Quantity int    `json:"quantity"`
+	Card     string `json:"card"`
+	Note     string `json:"note"`
+}
+
+func TestAttachRequestSnapshot(t *testing.T) {
+	errors.EnableRequestSnapshots(true)
+	defer errors.EnableRequestSnapshots(false)
+	errors.RegisterScrubber(func(key, value string) (string, bool) {
+		return value, key != "card"
+	})
+	defer errors.ResetScrubbers()
+
+	req := &createOrderRequest{OrderID: "42", Quantity: 3, Card: "4111111111111111", Note: strings.Repeat("x", 300)}
+
+	tests := []struct {
+		name   string
+		req    any
+		fields []string
+		want   errors.RequestSnapshot
+	}{
+		{
+			name:   "指定字段",
+			req:    req,
+			fields: []string{"order_id", "quantity", "card", "missing"},
+			want: errors.RequestSnapshot{
+				Method: "*errors_test.createOrderRequest",
+				Fields: map[string]string{"order_id": "42", "quantity": "3"},
+			},
+		},
+		{
+			name: "全部字段，超长的值被截断",
+			req:  req,
+			want: errors.RequestSnapshot{
+				Method:    "*errors_test.createOrderRequest",
+				Fields:    map[string]string{"order_id": "42", "quantity": "3", "note": strings.Repeat("x", 256)},
+				Truncated: true,
+			},
+		},
+		{
+			name: "proto 消息",
+			req:  &errdetails.ErrorInfo{Reason: "STOCKOUT", Domain: "orders"},
+			want: errors.RequestSnapshot{
+				Method: "google.rpc.ErrorInfo",
+				Fields: map[string]string{"reason": "STOCKOUT", "domain": "orders"},
+			},
+		},
+		{
+			name: "HTTP 请求只记录查询参数",
+			req:  httptest.NewRequest(http.MethodPost, "/orders?sku=a&sku=b", strings.NewReader(`{"card":"4111"}`)),
+			want: errors.RequestSnapshot{
+				Method: "POST /orders",
+				Fields: map[string]string{"sku": "a,b"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			base := errors.NewWithStatus(errors.CodeInvalidParam, "")
+			err := errors.AttachRequestSnapshot(base, tt.req, tt.fields...)
+			got, ok := errors.RequestSnapshotOf(err)
+			if !ok || !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("RequestSnapshotOf() = %+v, %v, want %+v", got, ok, tt.want)
+			}
+			if _, ok := errors.RequestSnapshotOf(base); ok {
+				t.Error("AttachRequestSnapshot() 不应修改原错误")
+			}
+			if !errors.IsInternalExtra(err, errors.RequestSnapshotKey) {
+				t.Error("请求快照应为内部扩展信息")
+			}
+			if _, ok := errors.RequestSnapshotOf(errors.FromGRPCStatus(errors.ToGRPCStatus(err))); ok {
+				t.Error("请求快照不应跨服务传递")
+			}
+		})
+	}
+}
+
+func TestAttachRequestSnapshotNested(t *testing.T) {
+	errors.Configure(errors.Config{RedactKeys: []string{"credentials"}, RequestSnapshots: true})
+	defer errors.Configure(errors.Config{})
+	errors.RegisterScrubber(func(key, value string) (string, bool) {
+		return value, key != "card"
+	})
+	defer errors.ResetScrubbers()
+
+	req := map[string]any{
+		"order_id":    "42",
+		"credentials": map[string]any{"user": "alice", "token": "t-1"},
+		"payment":     map[string]any{"card": "4111111111111111", "amount": 9.5},
+		"items":       []any{map[string]any{"sku": "a", "card": "4111"}, map[string]any{"sku": "b"}},
+		"tags":        []any{},
+	}
+
+	tests := []struct {
+		name   string
+		fields []string
+		want   map[string]string
+	}{
+		{
+			name: "全部字段",
+			want: map[string]string{
+				"order_id":          "42",
+				"credentials.token": errors.RedactedValue,
+				"credentials.user":  errors.RedactedValue,
+				"payment.amount":    "9.5",
+				"items.0.sku":       "a",
+				"items.1.sku":       "b",
+				"tags":              "[]",
+			},
+		},
+		{
+			name:   "指定嵌套字段",
+			fields: []string{"items", "credentials.user"},
+			want: map[string]string{
+				"credentials.user": errors.RedactedValue,
+				"items.0.sku":      "a",
+				"items.1.sku":      "b",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := errors.AttachRequestSnapshot(errors.NewWithStatus(errors.CodeInvalidParam, ""), req, tt.fields...)
+			got, ok := errors.RequestSnapshotOf(err)
+			if !ok || !reflect.DeepEqual(got.Fields, tt.want) {
+				t.Errorf("Fields = %v, want %v", got.Fields, tt.want)
+			}
+		})
+	}
+
+	// 超过最大层数的字段被丢弃
+	var deep any = "leaf"
+	for i := 0; i < 10; i++ {
+		deep = map[string]any{"n": deep}
+	}
+	err := errors.AttachRequestSnapshot(errors.NewWithStatus(errors.CodeInvalidParam, ""), map[string]any{"deep": deep, "id": "1"})
+	if got, _ := errors.RequestSnapshotOf(err); !got.Truncated || !reflect.DeepEqual(got.Fields, map[string]string{"id": "1"}) {
+		t.Errorf("RequestSnapshotOf() = %+v", got)
+	}
+}
+
+func TestAttachRequestSnapshotLimits(t *testing.T) {
+	errors.EnableRequestSnapshots(true)
+	defer errors.EnableRequestSnapshots(false)
+
+	req := make(map[string]string)
+	for i := 0; i < 20; i++ {
+		req[strings.Repeat(string(rune('a'+i)), 4)] = strings.Repeat("v", 200)
+	}
+	err := errors.AttachRequestSnapshot(errors.NewWithStatus(errors.CodeInvalidParam, ""), req)
+	got, ok := errors.RequestSnapshotOf(err)
+	if !ok || !got.Truncated || len(got.Fields) == 0 || len(got.Fields) == len(req) {
+		t.Fatalf("RequestSnapshotOf() = %+v, %v", got, ok)
+	}
+	if size := len(err.Extra()[errors.RequestSnapshotKey]); size > 2<<10 {
+		t.Errorf("快照大小 = %d, want <= 2048", size)
+	}
+}
+
+func TestAttachRequestSnapshotDisabled(t *testing.T) {
+	err := errors.AttachRequestSnapshot(errors.NewWithStatus(errors.CodeInvalidParam, ""), &createOrderRequest{OrderID: "42"})
+	if _, ok := errors.RequestSnapshotOf(err); ok {
+		t.Error("未开启时不应附加请求快照")
+	}
+}
+
+func TestRequestSnapshotDebugInfo(t *testing.T) {
+	errors.EnableRequestSnapshots(true)
+	defer errors.EnableRequestSnapshots(false)
+
+	h := errors.Handler(func(w http.ResponseWriter, r *http.Request) error {
+		return errors.AttachRequestSnapshot(errors.NewWithStatus(errors.CodeInvalidParam, ""), r)
+	})
+	req := httptest.NewRequest(http.MethodGet, "/orders?id=42", nil)
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req.WithContext(errors.WithDebugErrors(req.Context())))
+
+	var env errors.Envelope
+	if err := json.Unmarshal(rec.Body.Bytes(), &env); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+	var snapshot errors.RequestSnapshot
+	if env.Debug == nil || json.Unmarshal(env.Debug.Request, &snapshot) != nil || snapshot.Method != "GET /orders" ||
+		snapshot.Fields["id"] != "42" {
+		t.Errorf("Envelope.Debug = %+v", env.Debug)
+	}
+	if _, ok := env.Debug.Internal[errors.RequestSnapshotKey]; ok {
+		t.Error("请求快照不应重复出现在 Internal 中")
+	}
+	if _, ok := env.Extra[errors.RequestSnapshotKey]; ok {
+		t.Error("请求快照不应出现在响应的扩展信息中")
+	}
+}
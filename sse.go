@@ -0,0 +1,61 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package errors
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+)
+
+// SSEErrorEvent 是 Server-Sent Events 中错误帧使用的事件名
+const SSEErrorEvent = "error"
+
+// EncodeSSEError 将错误编码为一个 Server-Sent Events 帧
+// data 为单行的标准 JSON 结构（Envelope），浏览器端可以通过
+// eventSource.addEventListener("error", ...) 区分业务错误与连接中断.
+//
+//	event: error
+//	data: {"code":1004,"message":"资源未找到"}
+func EncodeSSEError(err error) []byte {
+	se := Translate(err)
+	if se == nil {
+		return nil
+	}
+
+	data, _ := json.Marshal(NewEnvelope(se))
+	frame := make([]byte, 0, len(data)+32)
+	frame = append(frame, "event: "+SSEErrorEvent+"\ndata: "...)
+	frame = append(frame, data...)
+	frame = append(frame, "\n\n"...)
+	return frame
+}
+
+// WriteSSEError 向流式响应写入一个错误帧，w 实现了 http.Flusher 时会立即刷新
+func WriteSSEError(w io.Writer, err error) error {
+	frame := EncodeSSEError(err)
+	if frame == nil {
+		return nil
+	}
+	if _, writeErr := w.Write(frame); writeErr != nil {
+		return writeErr
+	}
+	if f, ok := w.(http.Flusher); ok {
+		f.Flush()
+	}
+	return nil
+}
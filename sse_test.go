@@ -0,0 +1,56 @@
+package errors_test
+
+import (
+	"bufio"
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-anyway/framework-errors"
+)
+
+func TestWriteSSEError(t *testing.T) {
+	rec := httptest.NewRecorder()
+	err := errors.WriteSSEError(rec, errors.NewWithStatus(errors.CodeNotFound, "line1\nline2"))
+	if err != nil {
+		t.Fatalf("WriteSSEError() error = %v", err)
+	}
+	if !rec.Flushed {
+		t.Error("应刷新响应")
+	}
+
+	body := rec.Body.String()
+	if !strings.HasSuffix(body, "\n\n") {
+		t.Errorf("帧应以空行结束: %q", body)
+	}
+
+	var event, data string
+	scanner := bufio.NewScanner(strings.NewReader(body))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if v, ok := strings.CutPrefix(line, "event: "); ok {
+			event = v
+		}
+		if v, ok := strings.CutPrefix(line, "data: "); ok {
+			data = v
+		}
+	}
+	if event != errors.SSEErrorEvent {
+		t.Errorf("event = %s, want %s", event, errors.SSEErrorEvent)
+	}
+
+	var env errors.Envelope
+	if err := json.Unmarshal([]byte(data), &env); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+	if env.Code != errors.CodeNotFound || env.Message != "line1\nline2" {
+		t.Errorf("Envelope = %+v", env)
+	}
+}
+
+func TestEncodeSSEErrorNil(t *testing.T) {
+	if errors.EncodeSSEError(nil) != nil {
+		t.Error("EncodeSSEError(nil) 应返回 nil")
+	}
+}
@@ -0,0 +1,89 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package statsderr
+
+import (
+	"io"
+	"net"
+	"strconv"
+	"time"
+
+	"github.com/go-anyway/framework-errors"
+)
+
+// DogStatsDSink 以带标签的 DogStatsD 格式发送错误计数与请求耗时，适用于使用 Datadog agent 的集群
+//
+//	framework.errors.count:1|c|#code:1004,severity:warning,service:order
+//	framework.errors.latency:12|ms|#code:1004,service:order
+//	framework.errors.dependency:1|c|#dependency:payment-api,code:3002,service:order
+//	framework.errors.slo:1|c|#code:3002,slo_impact:budget_burning,service:order
+type DogStatsDSink struct {
+	w          io.Writer
+	serviceTag string
+}
+
+// NewDogStatsD 创建向 addr（如 127.0.0.1:8125）发送指标的 DogStatsDSink
+// service 会作为 service 标签附加到每个指标上，为空时不附加.
+func NewDogStatsD(addr, service string) (*DogStatsDSink, error) {
+	conn, err := net.Dial("udp", addr)
+	if err != nil {
+		return nil, err
+	}
+	return NewDogStatsDWithWriter(conn, service), nil
+}
+
+// NewDogStatsDWithWriter 创建将指标写入 w 的 DogStatsDSink，每个指标调用一次 Write
+func NewDogStatsDWithWriter(w io.Writer, service string) *DogStatsDSink {
+	s := &DogStatsDSink{w: w}
+	if service != "" {
+		s.serviceTag = ",service:" + service
+	}
+	return s
+}
+
+// Increment 实现 errors.MetricsSink 接口
+func (s *DogStatsDSink) Increment(code int32, severity errors.Severity) {
+	s.send("framework.errors.count:1|c|#code:" + strconv.Itoa(int(code)) + ",severity:" + string(severity) + s.serviceTag)
+}
+
+// ObserveLatency 实现 errors.MetricsSink 接口
+func (s *DogStatsDSink) ObserveLatency(code int32, latency time.Duration) {
+	s.send("framework.errors.latency:" + strconv.FormatInt(latency.Milliseconds(), 10) + "|ms|#code:" + strconv.Itoa(int(code)) + s.serviceTag)
+}
+
+// IncrementDependency 实现 errors.DependencyMetricsSink 接口
+func (s *DogStatsDSink) IncrementDependency(dependency string, code int32) {
+	s.send("framework.errors.dependency:1|c|#dependency:" + sanitize(dependency) + ",code:" + strconv.Itoa(int(code)) + s.serviceTag)
+}
+
+// IncrementSLOImpact 实现 errors.SLOMetricsSink 接口
+func (s *DogStatsDSink) IncrementSLOImpact(code int32, impact errors.SLOImpact) {
+	s.send("framework.errors.slo:1|c|#code:" + strconv.Itoa(int(code)) + ",slo_impact:" + impact.String() + s.serviceTag)
+}
+
+// send 发送一个指标，忽略发送失败
+func (s *DogStatsDSink) send(line string) {
+	_, _ = io.WriteString(s.w, line)
+}
+
+// Close 关闭底层的连接
+func (s *DogStatsDSink) Close() error {
+	if c, ok := s.w.(io.Closer); ok {
+		return c.Close()
+	}
+	return nil
+}
@@ -0,0 +1,104 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+// Package statsderr 提供基于 StatsD 协议的 errors.MetricsSink 实现，
+// 包括标准 StatsD（Sink）与带标签的 DogStatsD（DogStatsDSink）两种格式.
+package statsderr
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-anyway/framework-errors"
+)
+
+// Sink 通过 UDP 以 StatsD 协议发送错误计数与请求耗时
+// 指标名中包含错误码与严重程度，例如：
+//
+//	framework.errors.1004.warning:1|c
+//	framework.errors.latency.1004:12|ms
+//	framework.errors.dependency.payment-api.3002:1|c
+//	framework.errors.slo.budget_burning.3002:1|c
+//
+// 发送失败会被忽略，不影响错误处理.
+type Sink struct {
+	w      io.Writer
+	prefix string
+}
+
+// New 创建向 addr（如 127.0.0.1:8125）发送指标的 Sink，prefix 为空时使用 framework.errors
+func New(addr, prefix string) (*Sink, error) {
+	conn, err := net.Dial("udp", addr)
+	if err != nil {
+		return nil, err
+	}
+	return NewWithWriter(conn, prefix), nil
+}
+
+// NewWithWriter 创建将指标写入 w 的 Sink，每个指标调用一次 Write
+func NewWithWriter(w io.Writer, prefix string) *Sink {
+	if prefix == "" {
+		prefix = "framework.errors"
+	}
+	return &Sink{w: w, prefix: prefix}
+}
+
+// Increment 实现 errors.MetricsSink 接口
+func (s *Sink) Increment(code int32, severity errors.Severity) {
+	s.send(fmt.Sprintf("%s.%d.%s:1|c", s.prefix, code, severity))
+}
+
+// ObserveLatency 实现 errors.MetricsSink 接口
+func (s *Sink) ObserveLatency(code int32, latency time.Duration) {
+	s.send(s.prefix + ".latency." + strconv.Itoa(int(code)) + ":" + strconv.FormatInt(latency.Milliseconds(), 10) + "|ms")
+}
+
+// IncrementDependency 实现 errors.DependencyMetricsSink 接口
+func (s *Sink) IncrementDependency(dependency string, code int32) {
+	s.send(s.prefix + ".dependency." + sanitize(dependency) + "." + strconv.Itoa(int(code)) + ":1|c")
+}
+
+// IncrementSLOImpact 实现 errors.SLOMetricsSink 接口
+func (s *Sink) IncrementSLOImpact(code int32, impact errors.SLOImpact) {
+	s.send(s.prefix + ".slo." + impact.String() + "." + strconv.Itoa(int(code)) + ":1|c")
+}
+
+// send 发送一个指标，忽略发送失败
+func (s *Sink) send(line string) {
+	_, _ = io.WriteString(s.w, line)
+}
+
+// Close 关闭底层的连接
+func (s *Sink) Close() error {
+	if c, ok := s.w.(io.Closer); ok {
+		return c.Close()
+	}
+	return nil
+}
+
+// sanitize 将指标名与标签值中 StatsD 协议的保留字符（如 "."、":"、"|"、","）替换为 "_"
+func sanitize(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r == '-' || r == '_' || r >= '0' && r <= '9' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' {
+			return r
+		}
+		return '_'
+	}, s)
+}
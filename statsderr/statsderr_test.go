@@ -0,0 +1,116 @@
+package statsderr_test
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/go-anyway/framework-errors"
+	"github.com/go-anyway/framework-errors/statsderr"
+)
+
+var (
+	_ errors.DependencyMetricsSink = (*statsderr.Sink)(nil)
+	_ errors.DependencyMetricsSink = (*statsderr.DogStatsDSink)(nil)
+	_ errors.SLOMetricsSink        = (*statsderr.Sink)(nil)
+	_ errors.SLOMetricsSink        = (*statsderr.DogStatsDSink)(nil)
+)
+
+// lineRecorder 记录每次 Write 的内容
+type lineRecorder struct {
+	lines []string
+}
+
+func (r *lineRecorder) Write(p []byte) (int, error) {
+	r.lines = append(r.lines, string(p))
+	return len(p), nil
+}
+
+func TestSink(t *testing.T) {
+	rec := &lineRecorder{}
+	sink := statsderr.NewWithWriter(rec, "")
+	sink.Increment(errors.CodeNotFound, errors.SeverityWarning)
+	sink.ObserveLatency(errors.CodeNotFound, 12*time.Millisecond)
+	sink.IncrementDependency("payment.api", errors.CodeDependencyTimeout)
+	sink.IncrementSLOImpact(errors.CodeDependencyTimeout, errors.SLOImpactBudgetBurning)
+
+	want := []string{
+		"framework.errors.1004.warning:1|c",
+		"framework.errors.latency.1004:12|ms",
+		"framework.errors.dependency.payment_api.3002:1|c",
+		"framework.errors.slo.budget_burning.3002:1|c",
+	}
+	if len(rec.lines) != len(want) {
+		t.Fatalf("lines = %v, want %v", rec.lines, want)
+	}
+	for i := range want {
+		if rec.lines[i] != want[i] {
+			t.Errorf("lines[%d] = %q, want %q", i, rec.lines[i], want[i])
+		}
+	}
+}
+
+func TestNewUDP(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Skipf("ListenPacket() error = %v", err)
+	}
+	defer conn.Close()
+
+	sink, err := statsderr.New(conn.LocalAddr().String(), "svc.errors")
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	defer sink.Close()
+	sink.Increment(errors.CodeInternalError, errors.SeverityCritical)
+
+	buf := make([]byte, 512)
+	_ = conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	n, _, err := conn.ReadFrom(buf)
+	if err != nil {
+		t.Fatalf("ReadFrom() error = %v", err)
+	}
+	if got := string(buf[:n]); got != "svc.errors.1006.critical:1|c" {
+		t.Errorf("packet = %q", got)
+	}
+}
+
+func TestDogStatsDSink(t *testing.T) {
+	tests := []struct {
+		name    string
+		service string
+		want    []string
+	}{
+		{name: "带 service 标签", service: "order", want: []string{
+			"framework.errors.count:1|c|#code:1004,severity:warning,service:order",
+			"framework.errors.latency:12|ms|#code:1004,service:order",
+			"framework.errors.dependency:1|c|#dependency:payment_api,code:3002,service:order",
+			"framework.errors.slo:1|c|#code:3002,slo_impact:budget_burning,service:order",
+		}},
+		{name: "不带 service 标签", want: []string{
+			"framework.errors.count:1|c|#code:1004,severity:warning",
+			"framework.errors.latency:12|ms|#code:1004",
+			"framework.errors.dependency:1|c|#dependency:payment_api,code:3002",
+			"framework.errors.slo:1|c|#code:3002,slo_impact:budget_burning",
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := &lineRecorder{}
+			sink := statsderr.NewDogStatsDWithWriter(rec, tt.service)
+			sink.Increment(errors.CodeNotFound, errors.SeverityWarning)
+			sink.ObserveLatency(errors.CodeNotFound, 12*time.Millisecond)
+			sink.IncrementDependency("payment,api", errors.CodeDependencyTimeout)
+			sink.IncrementSLOImpact(errors.CodeDependencyTimeout, errors.SLOImpactBudgetBurning)
+
+			if len(rec.lines) != len(tt.want) {
+				t.Fatalf("lines = %v, want %v", rec.lines, tt.want)
+			}
+			for i := range tt.want {
+				if rec.lines[i] != tt.want[i] {
+					t.Errorf("lines[%d] = %q, want %q", i, rec.lines[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,115 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package errors
+
+import (
+	"fmt"
+
+	spb "google.golang.org/genproto/googleapis/rpc/status"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// 流式 RPC 的终止状态会结束整个流，长连接的流需要为单条消息报告错误时，
+// 在响应消息中声明一个 google.rpc.Status 类型的字段，由 AttachStreamError 与 ExtractStreamError 读写：
+//
+//	import "google/rpc/status.proto";
+//
+//	message SyncItemResponse {
+//	  string item_id = 1;
+//	  google.rpc.Status error = 2;
+//	}
+
+// streamStatusName 是流式消息中错误字段的类型
+const streamStatusName protoreflect.FullName = "google.rpc.Status"
+
+// StreamStatus 将错误编码为可以嵌入流式响应消息的 google.rpc.Status，err 为 nil 时返回 nil
+// 编码方式与 ToGRPCStatus 相同，非 StatusError 会先经过 Translate 转换.
+func StreamStatus(err error) *spb.Status {
+	se := Translate(err)
+	if se == nil {
+		return nil
+	}
+	return ToGRPCStatus(se).Proto()
+}
+
+// FromStreamStatus 从流式响应消息中的 google.rpc.Status 还原 StatusError，st 为 nil 或 OK 时返回 nil
+func FromStreamStatus(st *spb.Status) StatusError {
+	if st == nil || codes.Code(st.GetCode()) == codes.OK {
+		return nil
+	}
+	return FromGRPCStatus(status.FromProto(st))
+}
+
+// AttachStreamError 将错误写入 msg 中 google.rpc.Status 类型的字段，err 为 nil 时清空该字段
+// msg 没有该类型的字段时返回错误.
+//
+//	resp := &pb.SyncItemResponse{ItemId: id}
+//	if err := sync(item); err != nil {
+//		_ = errors.AttachStreamError(resp, err)
+//	}
+//	stream.Send(resp)
+func AttachStreamError(msg proto.Message, err error) error {
+	m := msg.ProtoReflect()
+	fd := streamErrorField(m.Descriptor())
+	if fd == nil {
+		return fmt.Errorf("errors: %s has no %s field", m.Descriptor().FullName(), streamStatusName)
+	}
+	st := StreamStatus(err)
+	if st == nil {
+		m.Clear(fd)
+		return nil
+	}
+	m.Set(fd, protoreflect.ValueOfMessage(st.ProtoReflect()))
+	return nil
+}
+
+// ExtractStreamError 从 msg 中 google.rpc.Status 类型的字段还原错误，字段未设置或 msg 没有该字段时返回 nil
+func ExtractStreamError(msg proto.Message) StatusError {
+	m := msg.ProtoReflect()
+	fd := streamErrorField(m.Descriptor())
+	if fd == nil || !m.Has(fd) {
+		return nil
+	}
+	st, ok := m.Get(fd).Message().Interface().(*spb.Status)
+	if !ok {
+		// 动态消息，重新序列化为具体类型
+		b, err := proto.Marshal(m.Get(fd).Message().Interface())
+		if err != nil {
+			return nil
+		}
+		st = new(spb.Status)
+		if proto.Unmarshal(b, st) != nil {
+			return nil
+		}
+	}
+	return FromStreamStatus(st)
+}
+
+// streamErrorField 返回 md 中第一个 google.rpc.Status 类型的单值字段
+func streamErrorField(md protoreflect.MessageDescriptor) protoreflect.FieldDescriptor {
+	fields := md.Fields()
+	for i := 0; i < fields.Len(); i++ {
+		fd := fields.Get(i)
+		if fd.Message() != nil && fd.Message().FullName() == streamStatusName && !fd.IsList() && !fd.IsMap() {
+			return fd
+		}
+	}
+	return nil
+}
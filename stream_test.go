@@ -0,0 +1,108 @@
+package errors_test
+
+import (
+	errstd "errors"
+	"testing"
+
+	"github.com/go-anyway/framework-errors"
+	_ "google.golang.org/genproto/googleapis/rpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// newStreamMessage 构造带有 google.rpc.Status 字段的流式响应消息
+func newStreamMessage(t *testing.T) proto.Message {
+	t.Helper()
+	fdp := &descriptorpb.FileDescriptorProto{
+		Name:       proto.String("stream_test.proto"),
+		Package:    proto.String("errors.test"),
+		Syntax:     proto.String("proto3"),
+		Dependency: []string{"google/rpc/status.proto"},
+		MessageType: []*descriptorpb.DescriptorProto{{
+			Name: proto.String("SyncItemResponse"),
+			Field: []*descriptorpb.FieldDescriptorProto{
+				{
+					Name:   proto.String("item_id"),
+					Number: proto.Int32(1),
+					Type:   descriptorpb.FieldDescriptorProto_TYPE_STRING.Enum(),
+					Label:  descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+				},
+				{
+					Name:     proto.String("error"),
+					Number:   proto.Int32(2),
+					Type:     descriptorpb.FieldDescriptorProto_TYPE_MESSAGE.Enum(),
+					TypeName: proto.String(".google.rpc.Status"),
+					Label:    descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+				},
+			},
+		}},
+	}
+	fd, err := protodesc.NewFile(fdp, protoregistry.GlobalFiles)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return dynamicpb.NewMessage(fd.Messages().ByName("SyncItemResponse"))
+}
+
+func TestStreamError(t *testing.T) {
+	msg := newStreamMessage(t)
+	if err := errors.ExtractStreamError(msg); err != nil {
+		t.Fatalf("ExtractStreamError() = %v, want nil", err)
+	}
+
+	sent := errors.NewWithStatus(errors.CodeNotFound, "item not found", errors.Extra("item_id", "42"))
+	if err := errors.AttachStreamError(msg, sent); err != nil {
+		t.Fatalf("AttachStreamError() error = %v", err)
+	}
+
+	// 经过序列化传输
+	b, err := proto.Marshal(msg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	received := dynamicpb.NewMessage(msg.ProtoReflect().Descriptor())
+	if err := proto.Unmarshal(b, received); err != nil {
+		t.Fatal(err)
+	}
+
+	got := errors.ExtractStreamError(received)
+	if got == nil || got.Code() != errors.CodeNotFound || got.Msg() != "item not found" || got.Extra()["item_id"] != "42" {
+		t.Errorf("ExtractStreamError() = %v", got)
+	}
+
+	if err := errors.AttachStreamError(received, nil); err != nil {
+		t.Fatalf("AttachStreamError(nil) error = %v", err)
+	}
+	if got := errors.ExtractStreamError(received); got != nil {
+		t.Errorf("ExtractStreamError() after clear = %v, want nil", got)
+	}
+}
+
+func TestStreamErrorNoField(t *testing.T) {
+	msg := &structpb.Struct{}
+	if err := errors.AttachStreamError(msg, errstd.New("boom")); err == nil {
+		t.Error("AttachStreamError() error = nil, want error")
+	}
+	if got := errors.ExtractStreamError(msg); got != nil {
+		t.Errorf("ExtractStreamError() = %v, want nil", got)
+	}
+}
+
+func TestStreamStatus(t *testing.T) {
+	if st := errors.StreamStatus(nil); st != nil {
+		t.Errorf("StreamStatus(nil) = %v, want nil", st)
+	}
+	if err := errors.FromStreamStatus(nil); err != nil {
+		t.Errorf("FromStreamStatus(nil) = %v, want nil", err)
+	}
+
+	st := errors.StreamStatus(errstd.New("boom"))
+	got := errors.FromStreamStatus(st)
+	if got == nil || got.Code() != errors.CodeInternalError {
+		t.Errorf("FromStreamStatus() = %v", got)
+	}
+}
@@ -0,0 +1,96 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+// Package temporalerr 在 StatusError 与 Temporal 的 ApplicationError 之间相互转换.
+package temporalerr
+
+import (
+	stderrors "errors"
+	"strconv"
+
+	"github.com/go-anyway/framework-errors"
+
+	"go.temporal.io/sdk/temporal"
+)
+
+// ToApplicationError 将错误转换为 temporal.ApplicationError，用于 Activity 的返回值
+// 错误码作为 ApplicationError 的 type，NonRetryable 取自 !IsRetryable，
+// Extra（不含堆栈）作为唯一的 details 传递；不影响稳定性的错误标记为 Benign，避免触发告警.
+//
+//	func (a *Activities) Charge(ctx context.Context, req Req) error {
+//		return temporalerr.ToApplicationError(a.svc.Charge(ctx, req))
+//	}
+func ToApplicationError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	var appErr *temporal.ApplicationError
+	if stderrors.As(err, &appErr) {
+		return err
+	}
+
+	se := errors.Translate(err)
+	extra := make(map[string]string)
+	errors.RangePublicExtra(se, func(k, v string) bool {
+		if k != "stack" {
+			extra[k] = v
+		}
+		return true
+	})
+
+	opts := temporal.ApplicationErrorOptions{
+		NonRetryable: !errors.IsRetryable(se),
+		Cause:        stderrors.Unwrap(se),
+		Details:      []interface{}{extra},
+	}
+	if !se.IsAffectStability() {
+		opts.Category = temporal.ApplicationErrorCategoryBenign
+	}
+
+	return temporal.NewApplicationErrorWithOptions(errors.ScrubbedMsg(se), strconv.Itoa(int(se.Code())), opts)
+}
+
+// FromApplicationError 从 err 链中的 temporal.ApplicationError 还原 StatusError
+// err 中不包含 ApplicationError 时返回 nil；type 不是错误码（例如由其他 SDK 产生）时
+// 使用 CodeInternalError，并将原始 type 记录在 Extra["temporal_type"] 中.
+func FromApplicationError(err error) errors.StatusError {
+	var appErr *temporal.ApplicationError
+	if !stderrors.As(err, &appErr) {
+		return nil
+	}
+
+	// 错误码来自 ApplicationError 的类型，即使包装的是 StatusError 也使用该错误码
+	opts := []errors.Option{errors.Retryable(!appErr.NonRetryable()), errors.OverrideCode()}
+
+	code := errors.CodeInternalError
+	if n, parseErr := strconv.ParseInt(appErr.Type(), 10, 32); parseErr == nil {
+		code = int32(n)
+	} else if appErr.Type() != "" {
+		opts = append(opts, errors.Extra("temporal_type", appErr.Type()))
+	}
+
+	if appErr.HasDetails() {
+		var extra map[string]string
+		if appErr.Details(&extra) == nil {
+			for k, v := range extra {
+				opts = append(opts, errors.Extra(k, v))
+			}
+		}
+	}
+
+	return errors.WrapWithStatusOptions(err, code, appErr.Message(), opts...)
+}
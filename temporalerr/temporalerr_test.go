@@ -0,0 +1,96 @@
+package temporalerr_test
+
+import (
+	stderrors "errors"
+	"fmt"
+	"testing"
+
+	"github.com/go-anyway/framework-errors"
+	"github.com/go-anyway/framework-errors/temporalerr"
+
+	"go.temporal.io/sdk/temporal"
+)
+
+func TestToApplicationError(t *testing.T) {
+	err := temporalerr.ToApplicationError(errors.NewWithStatus(
+		errors.CodeNotFound,
+		"订单不存在",
+		errors.Extra("order_id", "42"),
+	))
+
+	var appErr *temporal.ApplicationError
+	if !stderrors.As(err, &appErr) {
+		t.Fatalf("error = %v, want *temporal.ApplicationError", err)
+	}
+	if appErr.Type() != "1004" {
+		t.Errorf("Type() = %s, want 1004", appErr.Type())
+	}
+	if appErr.Message() != "订单不存在" {
+		t.Errorf("Message() = %s, want 订单不存在", appErr.Message())
+	}
+	if !appErr.NonRetryable() {
+		t.Error("NonRetryable() = false, want true")
+	}
+	if appErr.Category() != temporal.ApplicationErrorCategoryBenign {
+		t.Error("不影响稳定性的错误应标记为 Benign")
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	original := errors.NewWithStatus(
+		errors.CodeDependencyUnavailable,
+		"支付网关不可用",
+		errors.Extra("gateway", "alipay"),
+	)
+
+	se := temporalerr.FromApplicationError(fmt.Errorf("activity failed: %w", temporalerr.ToApplicationError(original)))
+	if se == nil {
+		t.Fatal("FromApplicationError() = nil, want StatusError")
+	}
+	if se.Code() != errors.CodeDependencyUnavailable {
+		t.Errorf("Code() = %d, want %d", se.Code(), errors.CodeDependencyUnavailable)
+	}
+	if se.Msg() != "支付网关不可用" {
+		t.Errorf("Msg() = %s, want 支付网关不可用", se.Msg())
+	}
+	if !errors.IsRetryable(se) {
+		t.Error("IsRetryable() = false, want true")
+	}
+	if se.Extra()["gateway"] != "alipay" {
+		t.Errorf("Extra[gateway] = %s, want alipay", se.Extra()["gateway"])
+	}
+	if _, ok := se.Extra()["stack"]; !ok {
+		t.Error("还原后的错误应带有本地堆栈")
+	}
+}
+
+func TestFromForeignApplicationError(t *testing.T) {
+	se := temporalerr.FromApplicationError(temporal.NewNonRetryableApplicationError("boom", "ValueError", nil))
+	if se.Code() != errors.CodeInternalError {
+		t.Errorf("Code() = %d, want %d", se.Code(), errors.CodeInternalError)
+	}
+	if se.Extra()["temporal_type"] != "ValueError" {
+		t.Errorf("Extra[temporal_type] = %s, want ValueError", se.Extra()["temporal_type"])
+	}
+
+	if temporalerr.FromApplicationError(stderrors.New("other")) != nil {
+		t.Error("非 ApplicationError 应返回 nil")
+	}
+}
+
+func TestFromApplicationErrorWrappingStatusError(t *testing.T) {
+	// 活动将 1004 包装为 1006 返回，应使用 ApplicationError 的类型
+	cause := errors.NewWithStatus(errors.CodeNotFound, "订单不存在")
+	appErr := temporal.NewApplicationErrorWithCause("加载订单失败", "1006", cause)
+
+	se := temporalerr.FromApplicationError(appErr)
+	if se.Code() != errors.CodeInternalError {
+		t.Errorf("Code() = %d, want %d", se.Code(), errors.CodeInternalError)
+	}
+	if se.Msg() != "加载订单失败" {
+		t.Errorf("Msg() = %s, want 加载订单失败", se.Msg())
+	}
+	if !stderrors.Is(se, cause) {
+		t.Error("errors.Is(se, cause) = false")
+	}
+}
@@ -0,0 +1,58 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package errors
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+var (
+	stackDisabled atomic.Bool
+	clock         atomic.Pointer[func() time.Time]
+)
+
+// DisableStackForTest 关闭调用堆栈的采集，返回恢复函数
+// 关闭后错误不再包含 "stack"，指纹改为由错误码和消息计算，序列化结果在不同环境下保持一致，
+// 便于集成测试中与 golden 文件比较. 仅用于测试.
+//
+//	defer errors.DisableStackForTest()()
+func DisableStackForTest() (restore func()) {
+	prev := stackDisabled.Swap(true)
+	return func() { stackDisabled.Store(prev) }
+}
+
+// SetClock 替换本包获取当前时间使用的时钟，返回恢复函数
+// 用于在测试中固定错误中记录的时间. clock 为 nil 时恢复为 time.Now.
+//
+//	defer errors.SetClock(func() time.Time { return time.Unix(1700000000, 0) })()
+func SetClock(c func() time.Time) (restore func()) {
+	var next *func() time.Time
+	if c != nil {
+		next = &c
+	}
+	prev := clock.Swap(next)
+	return func() { clock.Store(prev) }
+}
+
+// now 返回当前时间，测试中可以通过 SetClock 替换
+func now() time.Time {
+	if c := clock.Load(); c != nil {
+		return (*c)()
+	}
+	return time.Now()
+}
@@ -0,0 +1,43 @@
+package errors_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/go-anyway/framework-errors"
+)
+
+func newNotFoundErr() errors.StatusError {
+	return errors.NewWithStatus(errors.CodeNotFound, "")
+}
+
+func TestDisableStackForTest(t *testing.T) {
+	restore := errors.DisableStackForTest()
+
+	a := newNotFoundErr()
+	b := errors.NewWithStatus(errors.CodeNotFound, "")
+	if _, ok := a.Extra()["stack"]; ok {
+		t.Error("关闭堆栈采集后不应包含 stack")
+	}
+	if errors.Fingerprint(a) != errors.Fingerprint(b) {
+		t.Error("关闭堆栈采集后，不同位置产生的相同错误指纹应一致")
+	}
+
+	restore()
+	if _, ok := newNotFoundErr().Extra()["stack"]; !ok {
+		t.Error("恢复后应重新采集堆栈")
+	}
+}
+
+func TestSetClock(t *testing.T) {
+	fixed := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	restore := errors.SetClock(func() time.Time { return fixed })
+	if got := errors.Now(); !got.Equal(fixed) {
+		t.Errorf("Now() = %v, want %v", got, fixed)
+	}
+
+	restore()
+	if got := errors.Now(); got.Equal(fixed) {
+		t.Error("恢复后应使用 time.Now")
+	}
+}
@@ -0,0 +1,76 @@
+// Copyright 2025 zampo.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// @contact  zampo3380@gmail.com
+
+package errors
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// Translator 将第三方库（数据库驱动、网络库等）返回的错误翻译为 StatusError.
+// 无法识别该错误时应返回 nil，以便交给下一个 Translator 处理.
+type Translator func(err error) StatusError
+
+var (
+	translatorsMu sync.RWMutex
+	translators   []Translator
+)
+
+// RegisterTranslator 注册一个全局 Translator，按注册顺序依次尝试.
+// 通常在 init() 或服务启动时调用，例如 RegisterTranslator(gormerr.Translate).
+func RegisterTranslator(t Translator) {
+	if t == nil {
+		return
+	}
+	translatorsMu.Lock()
+	defer translatorsMu.Unlock()
+	translators = append(translators, t)
+}
+
+// Translate 将任意 error 转换为 StatusError
+// 如果 err 已经是 StatusError 则直接返回；否则依次尝试已注册的 Translator，
+// 都无法识别时依次识别 context 错误和网络错误，最后包装为 CodeInternalError.
+func Translate(err error) StatusError {
+	if err == nil {
+		return nil
+	}
+
+	var se StatusError
+	if errors.As(err, &se) {
+		return se
+	}
+
+	translatorsMu.RLock()
+	ts := translators
+	translatorsMu.RUnlock()
+
+	for _, t := range ts {
+		if se := t(err); se != nil {
+			return se
+		}
+	}
+
+	if se := FromContextErr(context.Background(), err); se != nil {
+		return se
+	}
+	if se := TranslateNetErr(err); se != nil {
+		return se
+	}
+
+	return WrapWithStatusOptions(err, CodeInternalError, "")
+}
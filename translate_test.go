@@ -0,0 +1,33 @@
+package errors_test
+
+import (
+	errstd "errors"
+	"testing"
+
+	"github.com/go-anyway/framework-errors"
+)
+
+func TestTranslate(t *testing.T) {
+	sentinel := errstd.New("sentinel")
+	errors.RegisterTranslator(func(err error) errors.StatusError {
+		if errstd.Is(err, sentinel) {
+			return errors.WrapWithStatusOptions(err, errors.CodeNotFound, "")
+		}
+		return nil
+	})
+
+	if se := errors.Translate(sentinel); se.Code() != errors.CodeNotFound {
+		t.Errorf("Code() = %d, want %d", se.Code(), errors.CodeNotFound)
+	}
+	if se := errors.Translate(errstd.New("other")); se.Code() != errors.CodeInternalError {
+		t.Errorf("Code() = %d, want %d", se.Code(), errors.CodeInternalError)
+	}
+
+	original := errors.NewStatusError(errors.CodeForbidden, "", nil)
+	if se := errors.Translate(original); se != original {
+		t.Error("已经是 StatusError 时应原样返回")
+	}
+	if se := errors.Translate(nil); se != nil {
+		t.Error("Translate(nil) 应返回 nil")
+	}
+}
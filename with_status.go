@@ -19,19 +19,29 @@ package errors
 import (
 	"errors"
 	"fmt"
+	"reflect"
 	"runtime"
 	"strings"
+	"sync"
 )
 
 // withStatus 是一个包装器，它包含了 statusError、调用堆栈和底层的 cause error.
 type withStatus struct {
 	status *statusError
-	stack  string
+	stack  *stack
 	cause  error
+	// pooled 不为 nil 表示该错误来自对象池，见 EnablePooling
+	pooled *pooledError
+	// annotation 是通过 Annotate 添加的上下文，site 是添加的位置
+	annotation string
+	site       *stack
+	// sites 是最初采集调用堆栈之后每次包装的位置，从内到外，见 WrapSites
+	sites []uintptr
 }
 
 // Option 是一个用于修改 withStatus 错误的函数.
 // withStatus 是我们内部用来包装 statusError 的结构体，将在 errors.go 中定义.
+// Option 只会作用于新创建的错误，为已有错误添加信息请使用 With，它会先复制再修改.
 type Option func(ws *withStatus)
 
 // Param 用于替换错误消息中的占位符.
@@ -60,9 +70,38 @@ func Extra(k, v string) Option {
 	}
 }
 
+// Retryable 用于覆盖错误码定义中的可重试标记.
+// 例如数据库序列化失败虽然使用 CodeConflict，但可以安全重试.
+func Retryable(retryable bool) Option {
+	return func(ws *withStatus) {
+		if ws == nil || ws.status == nil {
+			return
+		}
+		ws.status.ext.Retryable = retryable
+	}
+}
+
+// OverrideCode 用于 WrapWithStatusOptions 包装已有的 StatusError 时使用新的错误码
+// 默认沿用内层错误的错误码与稳定性，只有确实需要重新分类时才使用，例如将依赖服务的 NotFound 转换为本服务的内部错误.
+//
+//	return errors.WrapWithStatusOptions(err, errors.CodeInternalError, "", errors.OverrideCode())
+func OverrideCode() Option {
+	return overrideCode
+}
+
+// overrideCode 是 OverrideCode 返回的 Option，本身不修改错误，WrapWithStatusOptions 通过函数地址识别
+func overrideCode(*withStatus) {}
+
 // Error 实现 error 接口
 func (w *withStatus) Error() string {
+	if w.annotation != "" && w.cause != nil {
+		return fmt.Sprintf("%s: %v", w.annotation, w.cause)
+	}
 	if w.cause != nil {
+		// 沿用内层错误消息的包装不重复输出消息
+		if se, ok := w.cause.(StatusError); ok && se.Msg() == w.status.message {
+			return se.Error()
+		}
 		return fmt.Sprintf("%s: %v", w.status.message, w.cause)
 	}
 	return w.status.message
@@ -73,9 +112,24 @@ func (w *withStatus) Code() int32 {
 	return w.status.statusCode
 }
 
-// IsAffectStability 返回是否影响系统稳定性
+// IsAffectStability 返回是否影响系统稳定性，错误码处于维护窗口内时返回 false，见 SetMaintenanceWindow
 func (w *withStatus) IsAffectStability() bool {
-	return w.status.ext.IsAffectStability
+	return w.status.IsAffectStability()
+}
+
+// IsRetryable 返回是否可以重试
+func (w *withStatus) IsRetryable() bool {
+	return w.status.ext.Retryable
+}
+
+// Timeout 实现 net.Error 接口，错误码定义为超时类错误时返回 true
+func (w *withStatus) Timeout() bool {
+	return w.status.Timeout()
+}
+
+// Temporary 实现 net.Error 接口，与 IsRetryable 一致
+func (w *withStatus) Temporary() bool {
+	return w.status.ext.Retryable
 }
 
 // Msg 返回错误消息
@@ -85,20 +139,30 @@ func (w *withStatus) Msg() string {
 
 // Extra 返回扩展信息
 func (w *withStatus) Extra() map[string]string {
-	if w.status.ext.Extra == nil {
-		return make(map[string]string)
-	}
 	// 复制扩展信息，并添加堆栈信息
-	extra := make(map[string]string)
+	extra := make(map[string]string, len(w.status.ext.Extra)+1)
 	for k, v := range w.status.ext.Extra {
 		extra[k] = v
 	}
-	if w.stack != "" {
-		extra["stack"] = w.stack
+	if st := w.stack.String(); st != "" {
+		extra["stack"] = st
 	}
 	return extra
 }
 
+// RangeExtra 依次对每个扩展信息调用 fn，fn 返回 false 时停止遍历
+// 与 Extra() 一致，堆栈信息以 "stack" 为 key 最后返回.
+func (w *withStatus) RangeExtra(fn func(k, v string) bool) {
+	for k, v := range w.status.ext.Extra {
+		if !fn(k, v) {
+			return
+		}
+	}
+	if st := w.stack.String(); st != "" {
+		fn("stack", st)
+	}
+}
+
 // Unwrap 返回底层的 cause error，用于 errors.Unwrap()
 func (w *withStatus) Unwrap() error {
 	return w.cause
@@ -106,7 +170,7 @@ func (w *withStatus) Unwrap() error {
 
 // Stack 返回调用堆栈
 func (w *withStatus) Stack() string {
-	return w.stack
+	return w.stack.String()
 }
 
 // Cause 返回底层的 cause error
@@ -115,15 +179,17 @@ func (w *withStatus) Cause() error {
 }
 
 // WithStack 为 StatusError 添加调用堆栈信息
+// 已经有调用堆栈的错误保留最初采集的堆栈，只记录本次调用的位置，见 WrapSites.
 func WithStack(err StatusError) StatusError {
 	if err == nil {
 		return nil
 	}
 
-	// 如果已经是 withStatus，直接返回
 	var ws *withStatus
 	if errors.As(err, &ws) {
-		return ws
+		c := With(ws).(*withStatus)
+		c.sites = appendSite(ws.sites, captureSite(2))
+		return c
 	}
 
 	// 获取调用堆栈
@@ -146,6 +212,7 @@ func WithStack(err StatusError) StatusError {
 			message:    err.Msg(),
 			ext: Extension{
 				IsAffectStability: err.IsAffectStability(),
+				Retryable:         IsRetryable(err),
 				Extra:             err.Extra(),
 			},
 		},
@@ -154,15 +221,64 @@ func WithStack(err StatusError) StatusError {
 	}
 }
 
+// With 返回应用了 opts 的新错误，err 本身不会被修改
+// 错误可能已经被其他 goroutine 持有（例如正在记录日志），因此所有修改都作用于副本
+// （copy-on-write），新错误与 err 共享调用堆栈和 cause. err 不是 withStatus 时会记录当前调用堆栈.
+//
+//	err = errors.With(err, errors.Extra("order_id", id))
+func With(err StatusError, opts ...Option) StatusError {
+	if err == nil {
+		return nil
+	}
+
+	var ws *withStatus
+	var se *statusError
+	var c *withStatus
+	switch {
+	case errors.As(err, &ws):
+		c = &withStatus{
+			status:     ws.status.clone(),
+			stack:      ws.stack,
+			cause:      ws.cause,
+			annotation: ws.annotation,
+			site:       ws.site,
+			sites:      ws.sites,
+		}
+	case errors.As(err, &se):
+		c = &withStatus{status: se.clone(), stack: captureStack(2)}
+	default:
+		c = &withStatus{
+			status: &statusError{
+				statusCode: err.Code(),
+				message:    err.Msg(),
+				ext: Extension{
+					IsAffectStability: err.IsAffectStability(),
+					Retryable:         IsRetryable(err),
+					Extra:             err.Extra(),
+				},
+			},
+			stack: captureStack(2),
+			cause: err,
+		}
+	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
 // WrapWithStatus 将一个普通 error 包装为带状态的 StatusError
+// 与旧版本一致，WrapWithStatus 总是使用 code，即使 err 已经是 StatusError；
+// 需要沿用内层错误码时使用 WrapWithStatusOptions. err 已有调用堆栈时沿用最初采集的堆栈，见 WrapSites.
 func WrapWithStatus(err error, code int32, message string, data interface{}) StatusError {
 	if err == nil {
 		return nil
 	}
 
 	// 创建 statusError
+	code = CanonicalCode(code)
 	statusErr := NewStatusError(code, message, data)
-	stack := captureStack(2) // 跳过当前函数和调用者
 
 	// 尝试提取内部的 statusError
 	var se *statusError
@@ -189,20 +305,22 @@ func WrapWithStatus(err error, code int32, message string, data interface{}) Sta
 			message:    statusErr.Msg(),
 			ext: Extension{
 				IsAffectStability: def.IsAffectStability,
+				Retryable:         def.Retryable,
 				Extra:             extra,
 			},
 		}
 	}
 
-	return &withStatus{
-		status: se,
-		stack:  stack,
-		cause:  err,
+	ws = &withStatus{status: se, cause: err}
+	if !inheritStack(ws, err, captureSite(2)) {
+		ws.stack = captureStack(2) // 跳过当前函数和调用者
 	}
+	return ws
 }
 
 // NewWithStatus 创建一个带堆栈的 StatusError，支持 Option 模式
 func NewWithStatus(code int32, message string, opts ...Option) StatusError {
+	code = CanonicalCode(code)
 	if message == "" {
 		message = GetMessage(code, "")
 	}
@@ -210,22 +328,14 @@ func NewWithStatus(code int32, message string, opts ...Option) StatusError {
 	// 获取错误码定义
 	def := GetCodeDefinition(code)
 
-	// 创建 statusError
-	se := &statusError{
-		statusCode: code,
-		message:    message,
-		ext: Extension{
-			IsAffectStability: def.IsAffectStability,
-			Extra:             make(map[string]string),
-		},
-	}
-
-	// 创建 withStatus
-	ws := &withStatus{
-		status: se,
-		stack:  captureStack(2), // 跳过当前函数和调用者
-		cause:  nil,
-	}
+	// 创建 withStatus，启用对象池时从池中获取
+	ws := newWithStatus()
+	ws.status.statusCode = code
+	ws.status.message = message
+	ws.status.template = message
+	ws.status.ext.IsAffectStability = def.IsAffectStability
+	ws.status.ext.Retryable = def.Retryable
+	ws.stack = captureStack(2) // 跳过当前函数和调用者
 
 	// 应用所有 Option
 	for _, opt := range opts {
@@ -236,11 +346,28 @@ func NewWithStatus(code int32, message string, opts ...Option) StatusError {
 }
 
 // WrapWithStatusOptions 将一个普通 error 包装为带状态的 StatusError，支持 Option 模式
+// err 已经是 StatusError 时默认沿用其错误码、稳定性、可重试标记与扩展信息，message 只作为上下文加在原消息之前，
+// 避免将 NotFound 等业务错误意外地重新分类为 InternalError 而影响告警. 需要使用 code 时传入 OverrideCode.
 func WrapWithStatusOptions(err error, code int32, message string, opts ...Option) StatusError {
 	if err == nil {
 		return nil
 	}
 
+	var inner StatusError
+	if errors.As(err, &inner) && !overridesCode(opts) {
+		ws := newWithStatus()
+		if !inheritStack(ws, err, captureSite(2)) {
+			ws.stack = captureStack(2) // 跳过当前函数和调用者
+		}
+		ws.cause = err
+		inheritStatus(ws, inner, message)
+		for _, opt := range opts {
+			opt(ws)
+		}
+		return ws
+	}
+
+	code = CanonicalCode(code)
 	if message == "" {
 		message = GetMessage(code, "")
 	}
@@ -248,22 +375,17 @@ func WrapWithStatusOptions(err error, code int32, message string, opts ...Option
 	// 获取错误码定义
 	def := GetCodeDefinition(code)
 
-	// 创建 statusError
-	se := &statusError{
-		statusCode: code,
-		message:    message,
-		ext: Extension{
-			IsAffectStability: def.IsAffectStability,
-			Extra:             make(map[string]string),
-		},
-	}
-
-	// 创建 withStatus
-	ws := &withStatus{
-		status: se,
-		stack:  captureStack(2), // 跳过当前函数和调用者
-		cause:  err,
+	// 创建 withStatus，启用对象池时从池中获取
+	ws := newWithStatus()
+	ws.status.statusCode = code
+	ws.status.message = message
+	ws.status.template = message
+	ws.status.ext.IsAffectStability = def.IsAffectStability
+	ws.status.ext.Retryable = def.Retryable
+	if inner == nil || !inheritStack(ws, err, captureSite(2)) {
+		ws.stack = captureStack(2) // 跳过当前函数和调用者
 	}
+	ws.cause = err
 
 	// 应用所有 Option
 	for _, opt := range opts {
@@ -273,22 +395,205 @@ func WrapWithStatusOptions(err error, code int32, message string, opts ...Option
 	return ws
 }
 
-// captureStack 捕获调用堆栈
-func captureStack(skip int) string {
-	var pcs [32]uintptr
-	n := runtime.Callers(skip+1, pcs[:])
-	if n == 0 {
+// inheritStack 在 err 的错误链中已有调用堆栈时沿用最初采集的堆栈，只记录本次包装的位置 pc，
+// 返回是否沿用了堆栈. 多次包装的错误只保存一份完整的堆栈.
+func inheritStack(ws *withStatus, err error, pc uintptr) bool {
+	var inner *withStatus
+	if !errors.As(err, &inner) || inner.stack == nil {
+		return false
+	}
+	ws.stack = inner.stack
+	ws.sites = appendSite(inner.sites, pc)
+	return true
+}
+
+// appendSite 返回追加了 pc 的新切片，不会修改 sites
+func appendSite(sites []uintptr, pc uintptr) []uintptr {
+	if pc == 0 {
+		return sites
+	}
+	c := make([]uintptr, len(sites), len(sites)+1)
+	copy(c, sites)
+	return append(c, pc)
+}
+
+// WrapSites 返回错误最初采集调用堆栈之后每次包装（WithStack、WrapWithStatus、Annotate 等）的位置，
+// 从内到外排列，格式与调用堆栈相同. 与 Stack 一起可以同时看到错误的源头与传播路径.
+//
+//	for _, site := range errors.WrapSites(err) {
+//		fmt.Println(site)
+//	}
+func WrapSites(err error) []string {
+	var ws *withStatus
+	if !errors.As(err, &ws) || len(ws.sites) == 0 {
+		return nil
+	}
+	sites := make([]string, 0, len(ws.sites))
+	for _, pc := range ws.sites {
+		frame, _ := runtime.CallersFrames([]uintptr{pc}).Next()
+		sites = append(sites, fmt.Sprintf("%s\n\t%s:%d", frame.Function, frame.File, frame.Line))
+	}
+	return sites
+}
+
+// overridesCode 判断 opts 中是否包含 OverrideCode，只比较函数地址，不会执行 opts
+func overridesCode(opts []Option) bool {
+	target := reflect.ValueOf(Option(overrideCode)).Pointer()
+	for _, opt := range opts {
+		if opt != nil && reflect.ValueOf(opt).Pointer() == target {
+			return true
+		}
+	}
+	return false
+}
+
+// inheritStatus 沿用内层 StatusError 的错误码、稳定性、可重试标记、扩展信息与附加的 proto 消息
+// message 不为空且与原消息不同时作为注解加在原消息之前，与 Annotate 一致.
+func inheritStatus(ws *withStatus, inner StatusError, message string) {
+	var iws *withStatus
+	var ise *statusError
+	if errors.As(inner, &iws) {
+		ise = iws.status
+	} else if !errors.As(inner, &ise) {
+		ise = &statusError{
+			statusCode: inner.Code(),
+			message:    inner.Msg(),
+			ext: Extension{
+				IsAffectStability: inner.IsAffectStability(),
+				Retryable:         IsRetryable(inner),
+				Extra:             inner.Extra(),
+			},
+			internal: internalKeysOf(inner),
+			details:  detailsOf(inner),
+		}
+		delete(ise.ext.Extra, "stack")
+	}
+	*ws.status = *ise.clone()
+	ws.status.template = messageTemplate(inner)
+	if message != "" && message != inner.Msg() {
+		ws.annotation = message
+		ws.status.message = fmt.Sprintf("%s: %s", message, ws.status.message)
+		ws.status.template = fmt.Sprintf("%s: %s", message, ws.status.template)
+	}
+}
+
+// captureSite 返回调用者的位置，关闭调用堆栈采集时返回 0
+func captureSite(skip int) uintptr {
+	if stackDisabled.Load() || getStackMode() == StackModeOff {
+		return 0
+	}
+	var pcs [1]uintptr
+	if runtime.Callers(skip+1, pcs[:]) == 0 {
+		return 0
+	}
+	return pcs[0]
+}
+
+// stack 保存构造错误时的原始调用栈
+// 大部分错误在处理过程中从不输出堆栈，因此只在第一次需要时才进行符号化和格式化.
+type stack struct {
+	pcs  [32]uintptr
+	n    int
+	once sync.Once
+	str  string
+}
+
+// String 返回格式化后的调用堆栈，结果会被缓存
+func (s *stack) String() string {
+	if s == nil || s.n == 0 {
 		return ""
 	}
+	s.once.Do(func() {
+		frames := runtime.CallersFrames(s.pcs[:s.n])
+		var lines []string
+		for {
+			frame, more := frames.Next()
+			lines = append(lines, fmt.Sprintf("%s\n\t%s:%d", frame.Function, frame.File, frame.Line))
+			if !more {
+				break
+			}
+		}
+		s.str = strings.Join(lines, "\n")
+	})
+	return s.str
+}
+
+// Frame 是调用堆栈中的一帧
+type Frame struct {
+	Func string `json:"func"`
+	File string `json:"file"`
+	Line int    `json:"line"`
+}
 
-	frames := runtime.CallersFrames(pcs[:n])
-	var lines []string
+// frames 返回符号化后的调用堆栈
+func (s *stack) frames() []Frame {
+	if s == nil || s.n == 0 {
+		return nil
+	}
+	frames := runtime.CallersFrames(s.pcs[:s.n])
+	result := make([]Frame, 0, s.n)
 	for {
 		frame, more := frames.Next()
-		lines = append(lines, fmt.Sprintf("%s\n\t%s:%d", frame.Function, frame.File, frame.Line))
+		result = append(result, Frame{Func: frame.Function, File: frame.File, Line: frame.Line})
 		if !more {
 			break
 		}
 	}
-	return strings.Join(lines, "\n")
+	return result
+}
+
+// StackFrames 返回 err 构造时的调用堆栈，没有堆栈时返回 nil
+// 与 Extra()["stack"] 中格式化的字符串不同，返回结构化的帧，便于日志、错误上报等使用.
+func StackFrames(err error) []Frame {
+	var ws *withStatus
+	if !errors.As(err, &ws) {
+		return nil
+	}
+	return ws.stack.frames()
+}
+
+// origin 返回堆栈中第一个不属于本模块的帧的函数名，不需要格式化整个堆栈
+// 跳过本包与各翻译器子包（如 rediserr）的帧，否则经由翻译器或辅助函数创建的错误都会聚合到同一个位置.
+// 所有帧都属于本模块时返回第一帧.
+func (s *stack) origin() string {
+	if s == nil || s.n == 0 {
+		return ""
+	}
+	frames := runtime.CallersFrames(s.pcs[:s.n])
+	var first string
+	for {
+		frame, more := frames.Next()
+		if first == "" {
+			first = frame.Function
+		}
+		if !isModuleFunc(frame.Function) {
+			return frame.Function
+		}
+		if !more {
+			return first
+		}
+	}
+}
+
+// modulePath 是本包的导入路径，各翻译器子包位于其下
+var modulePath = reflect.TypeOf(statusError{}).PkgPath()
+
+// isModuleFunc 判断函数是否属于本模块，测试包除外
+func isModuleFunc(fn string) bool {
+	pkg := fn
+	slash := strings.LastIndexByte(fn, '/')
+	if dot := strings.IndexByte(fn[slash+1:], '.'); dot >= 0 {
+		pkg = fn[:slash+1+dot]
+	}
+	return (pkg == modulePath || strings.HasPrefix(pkg, modulePath+"/")) && !strings.HasSuffix(pkg, "_test")
+}
+
+// captureStack 捕获调用堆栈，只记录程序计数器
+func captureStack(skip int) *stack {
+	if stackDisabled.Load() || getStackMode() == StackModeOff {
+		return nil
+	}
+	s := &stack{}
+	s.n = runtime.Callers(skip+1, s.pcs[:])
+	return s
 }